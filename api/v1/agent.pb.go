@@ -0,0 +1,360 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        v3.14.0
+// source: api/v1/agent.proto
+
+package apiv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// RegisterRequest identifies an agent.
+type RegisterRequest struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	AgentId string                 `protobuf:"bytes,1,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
+	// Free-form agent implementation name and version, e.g. "rust-agent/0.3".
+	AgentVersion  string `protobuf:"bytes,2,opt,name=agent_version,json=agentVersion,proto3" json:"agent_version,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RegisterRequest) Reset() {
+	*x = RegisterRequest{}
+	mi := &file_api_v1_agent_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RegisterRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RegisterRequest) ProtoMessage() {}
+
+func (x *RegisterRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_agent_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RegisterRequest.ProtoReflect.Descriptor instead.
+func (*RegisterRequest) Descriptor() ([]byte, []int) {
+	return file_api_v1_agent_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *RegisterRequest) GetAgentId() string {
+	if x != nil {
+		return x.AgentId
+	}
+	return ""
+}
+
+func (x *RegisterRequest) GetAgentVersion() string {
+	if x != nil {
+		return x.AgentVersion
+	}
+	return ""
+}
+
+// Assignment describes the workload an agent should generate.
+type Assignment struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	TargetAddress    string                 `protobuf:"bytes,1,opt,name=target_address,json=targetAddress,proto3" json:"target_address,omitempty"`
+	NumConnections   int32                  `protobuf:"varint,2,opt,name=num_connections,json=numConnections,proto3" json:"num_connections,omitempty"`
+	NumWorkers       int32                  `protobuf:"varint,3,opt,name=num_workers,json=numWorkers,proto3" json:"num_workers,omitempty"`
+	DurationNs       int64                  `protobuf:"varint,4,opt,name=duration_ns,json=durationNs,proto3" json:"duration_ns,omitempty"`
+	WarmupDurationNs int64                  `protobuf:"varint,5,opt,name=warmup_duration_ns,json=warmupDurationNs,proto3" json:"warmup_duration_ns,omitempty"`
+	KeySpace         int32                  `protobuf:"varint,6,opt,name=key_space,json=keySpace,proto3" json:"key_space,omitempty"`
+	ValueSize        int32                  `protobuf:"varint,7,opt,name=value_size,json=valueSize,proto3" json:"value_size,omitempty"`
+	ReadRatio        int32                  `protobuf:"varint,8,opt,name=read_ratio,json=readRatio,proto3" json:"read_ratio,omitempty"`
+	WriteRatio       int32                  `protobuf:"varint,9,opt,name=write_ratio,json=writeRatio,proto3" json:"write_ratio,omitempty"`
+	DeleteRatio      int32                  `protobuf:"varint,10,opt,name=delete_ratio,json=deleteRatio,proto3" json:"delete_ratio,omitempty"`
+	// Percentages of the secondary-index operations, reported with method_name
+	// "IndexPut" (a primary record, then an index entry pointing to it) and
+	// "IndexGet" (the index entry, then the record it points to). All five
+	// ratios sum to 100.
+	IndexWriteRatio int32 `protobuf:"varint,11,opt,name=index_write_ratio,json=indexWriteRatio,proto3" json:"index_write_ratio,omitempty"`
+	IndexReadRatio  int32 `protobuf:"varint,12,opt,name=index_read_ratio,json=indexReadRatio,proto3" json:"index_read_ratio,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *Assignment) Reset() {
+	*x = Assignment{}
+	mi := &file_api_v1_agent_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Assignment) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Assignment) ProtoMessage() {}
+
+func (x *Assignment) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_agent_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Assignment.ProtoReflect.Descriptor instead.
+func (*Assignment) Descriptor() ([]byte, []int) {
+	return file_api_v1_agent_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Assignment) GetTargetAddress() string {
+	if x != nil {
+		return x.TargetAddress
+	}
+	return ""
+}
+
+func (x *Assignment) GetNumConnections() int32 {
+	if x != nil {
+		return x.NumConnections
+	}
+	return 0
+}
+
+func (x *Assignment) GetNumWorkers() int32 {
+	if x != nil {
+		return x.NumWorkers
+	}
+	return 0
+}
+
+func (x *Assignment) GetDurationNs() int64 {
+	if x != nil {
+		return x.DurationNs
+	}
+	return 0
+}
+
+func (x *Assignment) GetWarmupDurationNs() int64 {
+	if x != nil {
+		return x.WarmupDurationNs
+	}
+	return 0
+}
+
+func (x *Assignment) GetKeySpace() int32 {
+	if x != nil {
+		return x.KeySpace
+	}
+	return 0
+}
+
+func (x *Assignment) GetValueSize() int32 {
+	if x != nil {
+		return x.ValueSize
+	}
+	return 0
+}
+
+func (x *Assignment) GetReadRatio() int32 {
+	if x != nil {
+		return x.ReadRatio
+	}
+	return 0
+}
+
+func (x *Assignment) GetWriteRatio() int32 {
+	if x != nil {
+		return x.WriteRatio
+	}
+	return 0
+}
+
+func (x *Assignment) GetDeleteRatio() int32 {
+	if x != nil {
+		return x.DeleteRatio
+	}
+	return 0
+}
+
+func (x *Assignment) GetIndexWriteRatio() int32 {
+	if x != nil {
+		return x.IndexWriteRatio
+	}
+	return 0
+}
+
+func (x *Assignment) GetIndexReadRatio() int32 {
+	if x != nil {
+		return x.IndexReadRatio
+	}
+	return 0
+}
+
+// StreamResultsResponse acknowledges a finished result stream.
+type StreamResultsResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Number of operation results accepted into the controller's aggregation.
+	Accepted int64 `protobuf:"varint,1,opt,name=accepted,proto3" json:"accepted,omitempty"`
+	// Number of operation results discarded, e.g. outside the measured phase.
+	Discarded     int64 `protobuf:"varint,2,opt,name=discarded,proto3" json:"discarded,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StreamResultsResponse) Reset() {
+	*x = StreamResultsResponse{}
+	mi := &file_api_v1_agent_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StreamResultsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamResultsResponse) ProtoMessage() {}
+
+func (x *StreamResultsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_agent_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamResultsResponse.ProtoReflect.Descriptor instead.
+func (*StreamResultsResponse) Descriptor() ([]byte, []int) {
+	return file_api_v1_agent_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *StreamResultsResponse) GetAccepted() int64 {
+	if x != nil {
+		return x.Accepted
+	}
+	return 0
+}
+
+func (x *StreamResultsResponse) GetDiscarded() int64 {
+	if x != nil {
+		return x.Discarded
+	}
+	return 0
+}
+
+var File_api_v1_agent_proto protoreflect.FileDescriptor
+
+const file_api_v1_agent_proto_rawDesc = "" +
+	"\n" +
+	"\x12api/v1/agent.proto\x12\n" +
+	"kvbench.v1\x1a\x14api/v1/results.proto\"Q\n" +
+	"\x0fRegisterRequest\x12\x19\n" +
+	"\bagent_id\x18\x01 \x01(\tR\aagentId\x12#\n" +
+	"\ragent_version\x18\x02 \x01(\tR\fagentVersion\"\xc1\x03\n" +
+	"\n" +
+	"Assignment\x12%\n" +
+	"\x0etarget_address\x18\x01 \x01(\tR\rtargetAddress\x12'\n" +
+	"\x0fnum_connections\x18\x02 \x01(\x05R\x0enumConnections\x12\x1f\n" +
+	"\vnum_workers\x18\x03 \x01(\x05R\n" +
+	"numWorkers\x12\x1f\n" +
+	"\vduration_ns\x18\x04 \x01(\x03R\n" +
+	"durationNs\x12,\n" +
+	"\x12warmup_duration_ns\x18\x05 \x01(\x03R\x10warmupDurationNs\x12\x1b\n" +
+	"\tkey_space\x18\x06 \x01(\x05R\bkeySpace\x12\x1d\n" +
+	"\n" +
+	"value_size\x18\a \x01(\x05R\tvalueSize\x12\x1d\n" +
+	"\n" +
+	"read_ratio\x18\b \x01(\x05R\treadRatio\x12\x1f\n" +
+	"\vwrite_ratio\x18\t \x01(\x05R\n" +
+	"writeRatio\x12!\n" +
+	"\fdelete_ratio\x18\n" +
+	" \x01(\x05R\vdeleteRatio\x12*\n" +
+	"\x11index_write_ratio\x18\v \x01(\x05R\x0findexWriteRatio\x12(\n" +
+	"\x10index_read_ratio\x18\f \x01(\x05R\x0eindexReadRatio\"Q\n" +
+	"\x15StreamResultsResponse\x12\x1a\n" +
+	"\baccepted\x18\x01 \x01(\x03R\baccepted\x12\x1c\n" +
+	"\tdiscarded\x18\x02 \x01(\x03R\tdiscarded2\xa1\x01\n" +
+	"\x0fAgentController\x12?\n" +
+	"\bRegister\x12\x1b.kvbench.v1.RegisterRequest\x1a\x16.kvbench.v1.Assignment\x12M\n" +
+	"\rStreamResults\x12\x17.kvbench.v1.ResultFrame\x1a!.kvbench.v1.StreamResultsResponse(\x01B\"Z kvstore-benchmarker/api/v1;apiv1b\x06proto3"
+
+var (
+	file_api_v1_agent_proto_rawDescOnce sync.Once
+	file_api_v1_agent_proto_rawDescData []byte
+)
+
+func file_api_v1_agent_proto_rawDescGZIP() []byte {
+	file_api_v1_agent_proto_rawDescOnce.Do(func() {
+		file_api_v1_agent_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_api_v1_agent_proto_rawDesc), len(file_api_v1_agent_proto_rawDesc)))
+	})
+	return file_api_v1_agent_proto_rawDescData
+}
+
+var file_api_v1_agent_proto_msgTypes = make([]protoimpl.MessageInfo, 3)
+var file_api_v1_agent_proto_goTypes = []any{
+	(*RegisterRequest)(nil),       // 0: kvbench.v1.RegisterRequest
+	(*Assignment)(nil),            // 1: kvbench.v1.Assignment
+	(*StreamResultsResponse)(nil), // 2: kvbench.v1.StreamResultsResponse
+	(*ResultFrame)(nil),           // 3: kvbench.v1.ResultFrame
+}
+var file_api_v1_agent_proto_depIdxs = []int32{
+	0, // 0: kvbench.v1.AgentController.Register:input_type -> kvbench.v1.RegisterRequest
+	3, // 1: kvbench.v1.AgentController.StreamResults:input_type -> kvbench.v1.ResultFrame
+	1, // 2: kvbench.v1.AgentController.Register:output_type -> kvbench.v1.Assignment
+	2, // 3: kvbench.v1.AgentController.StreamResults:output_type -> kvbench.v1.StreamResultsResponse
+	2, // [2:4] is the sub-list for method output_type
+	0, // [0:2] is the sub-list for method input_type
+	0, // [0:0] is the sub-list for extension type_name
+	0, // [0:0] is the sub-list for extension extendee
+	0, // [0:0] is the sub-list for field type_name
+}
+
+func init() { file_api_v1_agent_proto_init() }
+func file_api_v1_agent_proto_init() {
+	if File_api_v1_agent_proto != nil {
+		return
+	}
+	file_api_v1_results_proto_init()
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_api_v1_agent_proto_rawDesc), len(file_api_v1_agent_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   3,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_api_v1_agent_proto_goTypes,
+		DependencyIndexes: file_api_v1_agent_proto_depIdxs,
+		MessageInfos:      file_api_v1_agent_proto_msgTypes,
+	}.Build()
+	File_api_v1_agent_proto = out.File
+	file_api_v1_agent_proto_goTypes = nil
+	file_api_v1_agent_proto_depIdxs = nil
+}
@@ -0,0 +1,177 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v3.14.0
+// source: api/v1/agent.proto
+
+package apiv1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	AgentController_Register_FullMethodName      = "/kvbench.v1.AgentController/Register"
+	AgentController_StreamResults_FullMethodName = "/kvbench.v1.AgentController/StreamResults"
+)
+
+// AgentControllerClient is the client API for AgentController service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// Control API between the benchmark controller and external load agents.
+//
+// This API is stable: fields and RPCs may be added, but existing ones are
+// never renumbered, retyped or removed within v1. Third-party agents (in any
+// language) implement the client side: they Register, run the returned
+// Assignment against the target, and stream their results back so the
+// controller aggregates and reports them together with its own workers.
+type AgentControllerClient interface {
+	// Register announces an agent and returns the workload it should run.
+	Register(ctx context.Context, in *RegisterRequest, opts ...grpc.CallOption) (*Assignment, error)
+	// StreamResults streams result frames from an agent to the controller.
+	// The first frame must be a RunHeader carrying the agent_id. Only results
+	// of the measured phase should be sent, warm-up results are discarded.
+	StreamResults(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[ResultFrame, StreamResultsResponse], error)
+}
+
+type agentControllerClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewAgentControllerClient(cc grpc.ClientConnInterface) AgentControllerClient {
+	return &agentControllerClient{cc}
+}
+
+func (c *agentControllerClient) Register(ctx context.Context, in *RegisterRequest, opts ...grpc.CallOption) (*Assignment, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Assignment)
+	err := c.cc.Invoke(ctx, AgentController_Register_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *agentControllerClient) StreamResults(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[ResultFrame, StreamResultsResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &AgentController_ServiceDesc.Streams[0], AgentController_StreamResults_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[ResultFrame, StreamResultsResponse]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type AgentController_StreamResultsClient = grpc.ClientStreamingClient[ResultFrame, StreamResultsResponse]
+
+// AgentControllerServer is the server API for AgentController service.
+// All implementations must embed UnimplementedAgentControllerServer
+// for forward compatibility.
+//
+// Control API between the benchmark controller and external load agents.
+//
+// This API is stable: fields and RPCs may be added, but existing ones are
+// never renumbered, retyped or removed within v1. Third-party agents (in any
+// language) implement the client side: they Register, run the returned
+// Assignment against the target, and stream their results back so the
+// controller aggregates and reports them together with its own workers.
+type AgentControllerServer interface {
+	// Register announces an agent and returns the workload it should run.
+	Register(context.Context, *RegisterRequest) (*Assignment, error)
+	// StreamResults streams result frames from an agent to the controller.
+	// The first frame must be a RunHeader carrying the agent_id. Only results
+	// of the measured phase should be sent, warm-up results are discarded.
+	StreamResults(grpc.ClientStreamingServer[ResultFrame, StreamResultsResponse]) error
+	mustEmbedUnimplementedAgentControllerServer()
+}
+
+// UnimplementedAgentControllerServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedAgentControllerServer struct{}
+
+func (UnimplementedAgentControllerServer) Register(context.Context, *RegisterRequest) (*Assignment, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Register not implemented")
+}
+func (UnimplementedAgentControllerServer) StreamResults(grpc.ClientStreamingServer[ResultFrame, StreamResultsResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method StreamResults not implemented")
+}
+func (UnimplementedAgentControllerServer) mustEmbedUnimplementedAgentControllerServer() {}
+func (UnimplementedAgentControllerServer) testEmbeddedByValue()                         {}
+
+// UnsafeAgentControllerServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to AgentControllerServer will
+// result in compilation errors.
+type UnsafeAgentControllerServer interface {
+	mustEmbedUnimplementedAgentControllerServer()
+}
+
+func RegisterAgentControllerServer(s grpc.ServiceRegistrar, srv AgentControllerServer) {
+	// If the following call pancis, it indicates UnimplementedAgentControllerServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&AgentController_ServiceDesc, srv)
+}
+
+func _AgentController_Register_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RegisterRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AgentControllerServer).Register(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AgentController_Register_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AgentControllerServer).Register(ctx, req.(*RegisterRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AgentController_StreamResults_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(AgentControllerServer).StreamResults(&grpc.GenericServerStream[ResultFrame, StreamResultsResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type AgentController_StreamResultsServer = grpc.ClientStreamingServer[ResultFrame, StreamResultsResponse]
+
+// AgentController_ServiceDesc is the grpc.ServiceDesc for AgentController service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var AgentController_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "kvbench.v1.AgentController",
+	HandlerType: (*AgentControllerServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Register",
+			Handler:    _AgentController_Register_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StreamResults",
+			Handler:       _AgentController_StreamResults_Handler,
+			ClientStreams: true,
+		},
+	},
+	Metadata: "api/v1/agent.proto",
+}
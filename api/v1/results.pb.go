@@ -0,0 +1,688 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        v3.14.0
+// source: api/v1/results.proto
+
+package apiv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// Method identifies the operation a result belongs to.
+type Method int32
+
+const (
+	Method_METHOD_UNSPECIFIED Method = 0
+	Method_METHOD_GET         Method = 1
+	Method_METHOD_PUT         Method = 2
+	Method_METHOD_DELETE      Method = 3
+)
+
+// Enum value maps for Method.
+var (
+	Method_name = map[int32]string{
+		0: "METHOD_UNSPECIFIED",
+		1: "METHOD_GET",
+		2: "METHOD_PUT",
+		3: "METHOD_DELETE",
+	}
+	Method_value = map[string]int32{
+		"METHOD_UNSPECIFIED": 0,
+		"METHOD_GET":         1,
+		"METHOD_PUT":         2,
+		"METHOD_DELETE":      3,
+	}
+)
+
+func (x Method) Enum() *Method {
+	p := new(Method)
+	*p = x
+	return p
+}
+
+func (x Method) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Method) Descriptor() protoreflect.EnumDescriptor {
+	return file_api_v1_results_proto_enumTypes[0].Descriptor()
+}
+
+func (Method) Type() protoreflect.EnumType {
+	return &file_api_v1_results_proto_enumTypes[0]
+}
+
+func (x Method) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Method.Descriptor instead.
+func (Method) EnumDescriptor() ([]byte, []int) {
+	return file_api_v1_results_proto_rawDescGZIP(), []int{0}
+}
+
+// RunHeader describes the run that produced the frames that follow it.
+type RunHeader struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Wall-clock start of the run; all offsets are relative to it.
+	StartUnixNano int64 `protobuf:"varint,1,opt,name=start_unix_nano,json=startUnixNano,proto3" json:"start_unix_nano,omitempty"`
+	// Human readable description of the effective configuration.
+	Config string `protobuf:"bytes,2,opt,name=config,proto3" json:"config,omitempty"`
+	// Identifier of the agent that produced the frames, empty for local runs.
+	AgentId       string `protobuf:"bytes,3,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RunHeader) Reset() {
+	*x = RunHeader{}
+	mi := &file_api_v1_results_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RunHeader) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RunHeader) ProtoMessage() {}
+
+func (x *RunHeader) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_results_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RunHeader.ProtoReflect.Descriptor instead.
+func (*RunHeader) Descriptor() ([]byte, []int) {
+	return file_api_v1_results_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *RunHeader) GetStartUnixNano() int64 {
+	if x != nil {
+		return x.StartUnixNano
+	}
+	return 0
+}
+
+func (x *RunHeader) GetConfig() string {
+	if x != nil {
+		return x.Config
+	}
+	return ""
+}
+
+func (x *RunHeader) GetAgentId() string {
+	if x != nil {
+		return x.AgentId
+	}
+	return ""
+}
+
+// OpResult is a single operation outcome.
+type OpResult struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Method Method                 `protobuf:"varint,1,opt,name=method,proto3,enum=kvbench.v1.Method" json:"method,omitempty"`
+	// Set only when method is METHOD_UNSPECIFIED.
+	MethodName string `protobuf:"bytes,2,opt,name=method_name,json=methodName,proto3" json:"method_name,omitempty"`
+	// Completion time relative to RunHeader.start_unix_nano.
+	OffsetNs  int64 `protobuf:"varint,3,opt,name=offset_ns,json=offsetNs,proto3" json:"offset_ns,omitempty"`
+	LatencyNs int64 `protobuf:"varint,4,opt,name=latency_ns,json=latencyNs,proto3" json:"latency_ns,omitempty"`
+	// Empty for successful operations.
+	Error         string `protobuf:"bytes,5,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OpResult) Reset() {
+	*x = OpResult{}
+	mi := &file_api_v1_results_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OpResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OpResult) ProtoMessage() {}
+
+func (x *OpResult) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_results_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OpResult.ProtoReflect.Descriptor instead.
+func (*OpResult) Descriptor() ([]byte, []int) {
+	return file_api_v1_results_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *OpResult) GetMethod() Method {
+	if x != nil {
+		return x.Method
+	}
+	return Method_METHOD_UNSPECIFIED
+}
+
+func (x *OpResult) GetMethodName() string {
+	if x != nil {
+		return x.MethodName
+	}
+	return ""
+}
+
+func (x *OpResult) GetOffsetNs() int64 {
+	if x != nil {
+		return x.OffsetNs
+	}
+	return 0
+}
+
+func (x *OpResult) GetLatencyNs() int64 {
+	if x != nil {
+		return x.LatencyNs
+	}
+	return 0
+}
+
+func (x *OpResult) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+// MethodStats holds aggregated statistics for a single method.
+type MethodStats struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Method        string                 `protobuf:"bytes,1,opt,name=method,proto3" json:"method,omitempty"`
+	Count         int64                  `protobuf:"varint,2,opt,name=count,proto3" json:"count,omitempty"`
+	ErrorCount    int64                  `protobuf:"varint,3,opt,name=error_count,json=errorCount,proto3" json:"error_count,omitempty"`
+	AvgLatencyMs  float64                `protobuf:"fixed64,4,opt,name=avg_latency_ms,json=avgLatencyMs,proto3" json:"avg_latency_ms,omitempty"`
+	MinLatencyMs  float64                `protobuf:"fixed64,5,opt,name=min_latency_ms,json=minLatencyMs,proto3" json:"min_latency_ms,omitempty"`
+	MaxLatencyMs  float64                `protobuf:"fixed64,6,opt,name=max_latency_ms,json=maxLatencyMs,proto3" json:"max_latency_ms,omitempty"`
+	P50LatencyMs  float64                `protobuf:"fixed64,7,opt,name=p50_latency_ms,json=p50LatencyMs,proto3" json:"p50_latency_ms,omitempty"`
+	P95LatencyMs  float64                `protobuf:"fixed64,8,opt,name=p95_latency_ms,json=p95LatencyMs,proto3" json:"p95_latency_ms,omitempty"`
+	P99LatencyMs  float64                `protobuf:"fixed64,9,opt,name=p99_latency_ms,json=p99LatencyMs,proto3" json:"p99_latency_ms,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MethodStats) Reset() {
+	*x = MethodStats{}
+	mi := &file_api_v1_results_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MethodStats) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MethodStats) ProtoMessage() {}
+
+func (x *MethodStats) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_results_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MethodStats.ProtoReflect.Descriptor instead.
+func (*MethodStats) Descriptor() ([]byte, []int) {
+	return file_api_v1_results_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *MethodStats) GetMethod() string {
+	if x != nil {
+		return x.Method
+	}
+	return ""
+}
+
+func (x *MethodStats) GetCount() int64 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+func (x *MethodStats) GetErrorCount() int64 {
+	if x != nil {
+		return x.ErrorCount
+	}
+	return 0
+}
+
+func (x *MethodStats) GetAvgLatencyMs() float64 {
+	if x != nil {
+		return x.AvgLatencyMs
+	}
+	return 0
+}
+
+func (x *MethodStats) GetMinLatencyMs() float64 {
+	if x != nil {
+		return x.MinLatencyMs
+	}
+	return 0
+}
+
+func (x *MethodStats) GetMaxLatencyMs() float64 {
+	if x != nil {
+		return x.MaxLatencyMs
+	}
+	return 0
+}
+
+func (x *MethodStats) GetP50LatencyMs() float64 {
+	if x != nil {
+		return x.P50LatencyMs
+	}
+	return 0
+}
+
+func (x *MethodStats) GetP95LatencyMs() float64 {
+	if x != nil {
+		return x.P95LatencyMs
+	}
+	return 0
+}
+
+func (x *MethodStats) GetP99LatencyMs() float64 {
+	if x != nil {
+		return x.P99LatencyMs
+	}
+	return 0
+}
+
+// Interval holds per-method statistics for a single reporting window.
+type Interval struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	StartOffsetNs int64                  `protobuf:"varint,1,opt,name=start_offset_ns,json=startOffsetNs,proto3" json:"start_offset_ns,omitempty"`
+	EndOffsetNs   int64                  `protobuf:"varint,2,opt,name=end_offset_ns,json=endOffsetNs,proto3" json:"end_offset_ns,omitempty"`
+	Methods       []*MethodStats         `protobuf:"bytes,3,rep,name=methods,proto3" json:"methods,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Interval) Reset() {
+	*x = Interval{}
+	mi := &file_api_v1_results_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Interval) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Interval) ProtoMessage() {}
+
+func (x *Interval) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_results_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Interval.ProtoReflect.Descriptor instead.
+func (*Interval) Descriptor() ([]byte, []int) {
+	return file_api_v1_results_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *Interval) GetStartOffsetNs() int64 {
+	if x != nil {
+		return x.StartOffsetNs
+	}
+	return 0
+}
+
+func (x *Interval) GetEndOffsetNs() int64 {
+	if x != nil {
+		return x.EndOffsetNs
+	}
+	return 0
+}
+
+func (x *Interval) GetMethods() []*MethodStats {
+	if x != nil {
+		return x.Methods
+	}
+	return nil
+}
+
+// Summary holds the final statistics of a run.
+type Summary struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	EndOffsetNs   int64                  `protobuf:"varint,1,opt,name=end_offset_ns,json=endOffsetNs,proto3" json:"end_offset_ns,omitempty"`
+	Methods       []*MethodStats         `protobuf:"bytes,2,rep,name=methods,proto3" json:"methods,omitempty"`
+	Aggregated    *MethodStats           `protobuf:"bytes,3,opt,name=aggregated,proto3" json:"aggregated,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Summary) Reset() {
+	*x = Summary{}
+	mi := &file_api_v1_results_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Summary) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Summary) ProtoMessage() {}
+
+func (x *Summary) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_results_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Summary.ProtoReflect.Descriptor instead.
+func (*Summary) Descriptor() ([]byte, []int) {
+	return file_api_v1_results_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *Summary) GetEndOffsetNs() int64 {
+	if x != nil {
+		return x.EndOffsetNs
+	}
+	return 0
+}
+
+func (x *Summary) GetMethods() []*MethodStats {
+	if x != nil {
+		return x.Methods
+	}
+	return nil
+}
+
+func (x *Summary) GetAggregated() *MethodStats {
+	if x != nil {
+		return x.Aggregated
+	}
+	return nil
+}
+
+// ResultFrame wraps a single record of a result stream.
+type ResultFrame struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Record:
+	//
+	//	*ResultFrame_Header
+	//	*ResultFrame_Op
+	//	*ResultFrame_Interval
+	//	*ResultFrame_Summary
+	Record        isResultFrame_Record `protobuf_oneof:"record"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ResultFrame) Reset() {
+	*x = ResultFrame{}
+	mi := &file_api_v1_results_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ResultFrame) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResultFrame) ProtoMessage() {}
+
+func (x *ResultFrame) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_results_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResultFrame.ProtoReflect.Descriptor instead.
+func (*ResultFrame) Descriptor() ([]byte, []int) {
+	return file_api_v1_results_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ResultFrame) GetRecord() isResultFrame_Record {
+	if x != nil {
+		return x.Record
+	}
+	return nil
+}
+
+func (x *ResultFrame) GetHeader() *RunHeader {
+	if x != nil {
+		if x, ok := x.Record.(*ResultFrame_Header); ok {
+			return x.Header
+		}
+	}
+	return nil
+}
+
+func (x *ResultFrame) GetOp() *OpResult {
+	if x != nil {
+		if x, ok := x.Record.(*ResultFrame_Op); ok {
+			return x.Op
+		}
+	}
+	return nil
+}
+
+func (x *ResultFrame) GetInterval() *Interval {
+	if x != nil {
+		if x, ok := x.Record.(*ResultFrame_Interval); ok {
+			return x.Interval
+		}
+	}
+	return nil
+}
+
+func (x *ResultFrame) GetSummary() *Summary {
+	if x != nil {
+		if x, ok := x.Record.(*ResultFrame_Summary); ok {
+			return x.Summary
+		}
+	}
+	return nil
+}
+
+type isResultFrame_Record interface {
+	isResultFrame_Record()
+}
+
+type ResultFrame_Header struct {
+	Header *RunHeader `protobuf:"bytes,1,opt,name=header,proto3,oneof"`
+}
+
+type ResultFrame_Op struct {
+	Op *OpResult `protobuf:"bytes,2,opt,name=op,proto3,oneof"`
+}
+
+type ResultFrame_Interval struct {
+	Interval *Interval `protobuf:"bytes,3,opt,name=interval,proto3,oneof"`
+}
+
+type ResultFrame_Summary struct {
+	Summary *Summary `protobuf:"bytes,4,opt,name=summary,proto3,oneof"`
+}
+
+func (*ResultFrame_Header) isResultFrame_Record() {}
+
+func (*ResultFrame_Op) isResultFrame_Record() {}
+
+func (*ResultFrame_Interval) isResultFrame_Record() {}
+
+func (*ResultFrame_Summary) isResultFrame_Record() {}
+
+var File_api_v1_results_proto protoreflect.FileDescriptor
+
+const file_api_v1_results_proto_rawDesc = "" +
+	"\n" +
+	"\x14api/v1/results.proto\x12\n" +
+	"kvbench.v1\"f\n" +
+	"\tRunHeader\x12&\n" +
+	"\x0fstart_unix_nano\x18\x01 \x01(\x03R\rstartUnixNano\x12\x16\n" +
+	"\x06config\x18\x02 \x01(\tR\x06config\x12\x19\n" +
+	"\bagent_id\x18\x03 \x01(\tR\aagentId\"\xa9\x01\n" +
+	"\bOpResult\x12*\n" +
+	"\x06method\x18\x01 \x01(\x0e2\x12.kvbench.v1.MethodR\x06method\x12\x1f\n" +
+	"\vmethod_name\x18\x02 \x01(\tR\n" +
+	"methodName\x12\x1b\n" +
+	"\toffset_ns\x18\x03 \x01(\x03R\boffsetNs\x12\x1d\n" +
+	"\n" +
+	"latency_ns\x18\x04 \x01(\x03R\tlatencyNs\x12\x14\n" +
+	"\x05error\x18\x05 \x01(\tR\x05error\"\xc0\x02\n" +
+	"\vMethodStats\x12\x16\n" +
+	"\x06method\x18\x01 \x01(\tR\x06method\x12\x14\n" +
+	"\x05count\x18\x02 \x01(\x03R\x05count\x12\x1f\n" +
+	"\verror_count\x18\x03 \x01(\x03R\n" +
+	"errorCount\x12$\n" +
+	"\x0eavg_latency_ms\x18\x04 \x01(\x01R\favgLatencyMs\x12$\n" +
+	"\x0emin_latency_ms\x18\x05 \x01(\x01R\fminLatencyMs\x12$\n" +
+	"\x0emax_latency_ms\x18\x06 \x01(\x01R\fmaxLatencyMs\x12$\n" +
+	"\x0ep50_latency_ms\x18\a \x01(\x01R\fp50LatencyMs\x12$\n" +
+	"\x0ep95_latency_ms\x18\b \x01(\x01R\fp95LatencyMs\x12$\n" +
+	"\x0ep99_latency_ms\x18\t \x01(\x01R\fp99LatencyMs\"\x89\x01\n" +
+	"\bInterval\x12&\n" +
+	"\x0fstart_offset_ns\x18\x01 \x01(\x03R\rstartOffsetNs\x12\"\n" +
+	"\rend_offset_ns\x18\x02 \x01(\x03R\vendOffsetNs\x121\n" +
+	"\amethods\x18\x03 \x03(\v2\x17.kvbench.v1.MethodStatsR\amethods\"\x99\x01\n" +
+	"\aSummary\x12\"\n" +
+	"\rend_offset_ns\x18\x01 \x01(\x03R\vendOffsetNs\x121\n" +
+	"\amethods\x18\x02 \x03(\v2\x17.kvbench.v1.MethodStatsR\amethods\x127\n" +
+	"\n" +
+	"aggregated\x18\x03 \x01(\v2\x17.kvbench.v1.MethodStatsR\n" +
+	"aggregated\"\xd5\x01\n" +
+	"\vResultFrame\x12/\n" +
+	"\x06header\x18\x01 \x01(\v2\x15.kvbench.v1.RunHeaderH\x00R\x06header\x12&\n" +
+	"\x02op\x18\x02 \x01(\v2\x14.kvbench.v1.OpResultH\x00R\x02op\x122\n" +
+	"\binterval\x18\x03 \x01(\v2\x14.kvbench.v1.IntervalH\x00R\binterval\x12/\n" +
+	"\asummary\x18\x04 \x01(\v2\x13.kvbench.v1.SummaryH\x00R\asummaryB\b\n" +
+	"\x06record*S\n" +
+	"\x06Method\x12\x16\n" +
+	"\x12METHOD_UNSPECIFIED\x10\x00\x12\x0e\n" +
+	"\n" +
+	"METHOD_GET\x10\x01\x12\x0e\n" +
+	"\n" +
+	"METHOD_PUT\x10\x02\x12\x11\n" +
+	"\rMETHOD_DELETE\x10\x03B\"Z kvstore-benchmarker/api/v1;apiv1b\x06proto3"
+
+var (
+	file_api_v1_results_proto_rawDescOnce sync.Once
+	file_api_v1_results_proto_rawDescData []byte
+)
+
+func file_api_v1_results_proto_rawDescGZIP() []byte {
+	file_api_v1_results_proto_rawDescOnce.Do(func() {
+		file_api_v1_results_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_api_v1_results_proto_rawDesc), len(file_api_v1_results_proto_rawDesc)))
+	})
+	return file_api_v1_results_proto_rawDescData
+}
+
+var file_api_v1_results_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_api_v1_results_proto_msgTypes = make([]protoimpl.MessageInfo, 6)
+var file_api_v1_results_proto_goTypes = []any{
+	(Method)(0),         // 0: kvbench.v1.Method
+	(*RunHeader)(nil),   // 1: kvbench.v1.RunHeader
+	(*OpResult)(nil),    // 2: kvbench.v1.OpResult
+	(*MethodStats)(nil), // 3: kvbench.v1.MethodStats
+	(*Interval)(nil),    // 4: kvbench.v1.Interval
+	(*Summary)(nil),     // 5: kvbench.v1.Summary
+	(*ResultFrame)(nil), // 6: kvbench.v1.ResultFrame
+}
+var file_api_v1_results_proto_depIdxs = []int32{
+	0, // 0: kvbench.v1.OpResult.method:type_name -> kvbench.v1.Method
+	3, // 1: kvbench.v1.Interval.methods:type_name -> kvbench.v1.MethodStats
+	3, // 2: kvbench.v1.Summary.methods:type_name -> kvbench.v1.MethodStats
+	3, // 3: kvbench.v1.Summary.aggregated:type_name -> kvbench.v1.MethodStats
+	1, // 4: kvbench.v1.ResultFrame.header:type_name -> kvbench.v1.RunHeader
+	2, // 5: kvbench.v1.ResultFrame.op:type_name -> kvbench.v1.OpResult
+	4, // 6: kvbench.v1.ResultFrame.interval:type_name -> kvbench.v1.Interval
+	5, // 7: kvbench.v1.ResultFrame.summary:type_name -> kvbench.v1.Summary
+	8, // [8:8] is the sub-list for method output_type
+	8, // [8:8] is the sub-list for method input_type
+	8, // [8:8] is the sub-list for extension type_name
+	8, // [8:8] is the sub-list for extension extendee
+	0, // [0:8] is the sub-list for field type_name
+}
+
+func init() { file_api_v1_results_proto_init() }
+func file_api_v1_results_proto_init() {
+	if File_api_v1_results_proto != nil {
+		return
+	}
+	file_api_v1_results_proto_msgTypes[5].OneofWrappers = []any{
+		(*ResultFrame_Header)(nil),
+		(*ResultFrame_Op)(nil),
+		(*ResultFrame_Interval)(nil),
+		(*ResultFrame_Summary)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_api_v1_results_proto_rawDesc), len(file_api_v1_results_proto_rawDesc)),
+			NumEnums:      1,
+			NumMessages:   6,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_api_v1_results_proto_goTypes,
+		DependencyIndexes: file_api_v1_results_proto_depIdxs,
+		EnumInfos:         file_api_v1_results_proto_enumTypes,
+		MessageInfos:      file_api_v1_results_proto_msgTypes,
+	}.Build()
+	File_api_v1_results_proto = out.File
+	file_api_v1_results_proto_goTypes = nil
+	file_api_v1_results_proto_depIdxs = nil
+}
@@ -0,0 +1,170 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"kvstore-benchmarker/pkg/analyze"
+	"kvstore-benchmarker/pkg/archive"
+	"kvstore-benchmarker/pkg/latency"
+)
+
+// runAnalyze prints statistics for a time window of a recorded run, or
+// compares two windows method by method
+func runAnalyze(args []string) error {
+	fs := flag.NewFlagSet("analyze", flag.ExitOnError)
+	from := fs.Duration("from", 0, "Start of the window as an offset from the start of the recording")
+	to := fs.Duration("to", 0, "End of the window (0 for the end of the recording)")
+	vsFrom := fs.Duration("vs-from", 0, "Start of a second window to compare against")
+	vsTo := fs.Duration("vs-to", 0, "End of the second window (0 for the end of the recording)")
+	method := fs.String("method", "", "Only report this method")
+	unitName := fs.String("latency-unit", "ms", "Unit of latencies: ms, us or ns")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: %s analyze [flags] <archive|raw-log|histogram-store>\n", os.Args[0])
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return fmt.Errorf("expected exactly one input file")
+	}
+
+	unit, err := latency.ParseUnit(*unitName)
+	if err != nil {
+		return err
+	}
+
+	compare := false
+	fs.Visit(func(f *flag.Flag) {
+		if f.Name == "vs-from" || f.Name == "vs-to" {
+			compare = true
+		}
+	})
+
+	a, err := analyze.Load(fs.Arg(0), archive.Window{From: *from, To: *to})
+	if err != nil {
+		return err
+	}
+	if !compare {
+		return writeWindow(os.Stdout, a, *method, unit)
+	}
+
+	b, err := analyze.Load(fs.Arg(0), archive.Window{From: *vsFrom, To: *vsTo})
+	if err != nil {
+		return err
+	}
+	return writeComparison(os.Stdout, a, b, *method, unit)
+}
+
+// analyzeStat is a statistic reported by the analyze subcommand
+type analyzeStat struct {
+	name    string
+	latency bool
+	value   func(m *analyze.MethodStats) float64
+}
+
+// analyzeStats lists the reported statistics in column order
+var analyzeStats = []analyzeStat{
+	{"ops", false, func(m *analyze.MethodStats) float64 { return float64(m.Ops) }},
+	{"error%", false, func(m *analyze.MethodStats) float64 { return m.ErrorRate() }},
+	{"p50", true, func(m *analyze.MethodStats) float64 { return m.Percentile(50) }},
+	{"p90", true, func(m *analyze.MethodStats) float64 { return m.Percentile(90) }},
+	{"p95", true, func(m *analyze.MethodStats) float64 { return m.Percentile(95) }},
+	{"p99", true, func(m *analyze.MethodStats) float64 { return m.Percentile(99) }},
+	{"p99.9", true, func(m *analyze.MethodStats) float64 { return m.Percentile(99.9) }},
+	{"max", true, func(m *analyze.MethodStats) float64 { return m.Max() }},
+}
+
+// format renders a statistic value
+func (s analyzeStat) format(v float64, unit latency.Unit) string {
+	switch {
+	case s.latency:
+		return unit.Value(v)
+	case s.name == "ops":
+		return fmt.Sprintf("%.0f", v)
+	case v < 0:
+		return "-"
+	default:
+		return fmt.Sprintf("%.2f", v)
+	}
+}
+
+// selectMethods returns the methods to report, followed by all methods combined
+func selectMethods(r *analyze.Result, method string) []*analyze.MethodStats {
+	if method != "" {
+		if m, ok := r.Methods[method]; ok {
+			return []*analyze.MethodStats{m}
+		}
+		return nil
+	}
+
+	var methods []*analyze.MethodStats
+	for _, name := range r.Names() {
+		methods = append(methods, r.Methods[name])
+	}
+	return append(methods, r.Total())
+}
+
+// writeWindow writes one row per method for a single window
+func writeWindow(out io.Writer, r *analyze.Result, method string, unit latency.Unit) error {
+	fmt.Fprintf(out, "%s window %s\n\n", r.Format, r.Window)
+
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', tabwriter.AlignRight)
+	header := []string{"method"}
+	for _, s := range analyzeStats {
+		name := s.name
+		if s.latency {
+			name = unit.Column(name)
+		}
+		header = append(header, name)
+	}
+	fmt.Fprintln(w, strings.Join(header, "\t")+"\t")
+
+	for _, m := range selectMethods(r, method) {
+		row := []string{m.Method}
+		for _, s := range analyzeStats {
+			row = append(row, s.format(s.value(m), unit))
+		}
+		fmt.Fprintln(w, strings.Join(row, "\t")+"\t")
+	}
+	return w.Flush()
+}
+
+// writeComparison writes each statistic of each method in both windows and
+// the relative change from the first to the second
+func writeComparison(out io.Writer, a, b *analyze.Result, method string, unit latency.Unit) error {
+	fmt.Fprintf(out, "%s window A %s vs window B %s\n\n", a.Format, a.Window, b.Window)
+
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(w, "method\tstat\tA\tB\tchange\t")
+
+	bMethods := make(map[string]*analyze.MethodStats)
+	for _, m := range selectMethods(b, method) {
+		bMethods[m.Method] = m
+	}
+
+	for _, ma := range selectMethods(a, method) {
+		mb, ok := bMethods[ma.Method]
+		if !ok {
+			continue
+		}
+		for _, s := range analyzeStats {
+			name := s.name
+			if s.latency {
+				name = unit.Column(name)
+			}
+			va, vb := s.value(ma), s.value(mb)
+			change := "-"
+			if va > 0 && vb >= 0 {
+				change = fmt.Sprintf("%+.1f%%", (vb-va)/va*100)
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t\n", ma.Method, name, s.format(va, unit), s.format(vb, unit), change)
+		}
+	}
+	return w.Flush()
+}
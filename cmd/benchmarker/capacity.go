@@ -0,0 +1,112 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"kvstore-benchmarker/pkg/analyze"
+	"kvstore-benchmarker/pkg/archive"
+	"kvstore-benchmarker/pkg/latency"
+	"kvstore-benchmarker/pkg/slo"
+)
+
+// runCapacity combines the recorded runs of a load sweep into a Markdown
+// capacity-planning summary: the highest throughput meeting each latency SLO
+// and its headroom over the production load
+func runCapacity(args []string) error {
+	fs := flag.NewFlagSet("capacity", flag.ExitOnError)
+	objectives := fs.String("slo", "p99<=1ms,p99<=5ms,p99<=10ms", "Comma-separated SLOs to find the maximum throughput for")
+	load := fs.Float64("production-load", 0, "Production load in ops/sec to report headroom against (0 to omit)")
+	from := fs.Duration("from", 0, "Start of the window of every run, e.g. to skip the ramp")
+	to := fs.Duration("to", 0, "End of the window of every run (0 for the end of the recording)")
+	unitName := fs.String("latency-unit", "ms", "Unit of latencies: ms, us or ns")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: %s capacity [flags] <run>...\n", os.Args[0])
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return fmt.Errorf("expected at least one recorded run")
+	}
+	unit, err := latency.ParseUnit(*unitName)
+	if err != nil {
+		return err
+	}
+	assertions, err := slo.ParseAssertions(*objectives)
+	if err != nil {
+		return err
+	}
+	for _, a := range assertions {
+		if a.Phase != slo.RunPhase || a.Metric == "throughput" {
+			return fmt.Errorf("SLO %q must be a latency or error_rate assertion without a phase", a.Text)
+		}
+	}
+
+	runs := make([]*analyze.Result, fs.NArg())
+	for i, path := range fs.Args() {
+		if runs[i], err = analyze.Load(path, archive.Window{From: *from, To: *to}); err != nil {
+			return err
+		}
+	}
+	return writeCapacity(os.Stdout, fs.Args(), runs, analyze.Capacity(runs, assertions), *load, unit)
+}
+
+// writeCapacity renders the sweep and its limits as Markdown
+func writeCapacity(out io.Writer, paths []string, runs []*analyze.Result, limits []analyze.Limit, load float64, unit latency.Unit) error {
+	fmt.Fprintf(out, "# Capacity Planning\n\n")
+	if load > 0 {
+		fmt.Fprintf(out, "Production load: %.0f ops/sec\n\n", load)
+	}
+
+	fmt.Fprintf(out, "## Maximum Throughput by SLO\n\n")
+	if load > 0 {
+		fmt.Fprintf(out, "| SLO | Max ops/sec | Run | Headroom |\n|---|--:|---|--:|\n")
+	} else {
+		fmt.Fprintf(out, "| SLO | Max ops/sec | Run |\n|---|--:|---|\n")
+	}
+	for _, l := range limits {
+		if l.Run < 0 {
+			fmt.Fprintf(out, "| `%s` | not met | - |", l.Objective.Text)
+			if load > 0 {
+				fmt.Fprintf(out, " - |")
+			}
+			fmt.Fprintln(out)
+			continue
+		}
+		fmt.Fprintf(out, "| `%s` | %.0f | %s |", l.Objective.Text, l.Throughput, filepath.Base(paths[l.Run]))
+		if load > 0 {
+			fmt.Fprintf(out, " %+.1f%% |", l.Headroom(load))
+		}
+		fmt.Fprintln(out)
+	}
+
+	fmt.Fprintf(out, "\n## Sweep (latencies in %s)\n\n", unit.Name())
+	fmt.Fprintf(out, "| Run | ops/sec | Error%% | P50 | P95 | P99 | P99.9 | Max |\n|---|--:|--:|--:|--:|--:|--:|--:|\n")
+
+	// Runs in order of increasing throughput, as in the sweep
+	order := make([]int, len(runs))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool { return runs[order[a]].Throughput() < runs[order[b]].Throughput() })
+	for _, i := range order {
+		run := runs[i]
+		total := run.Total()
+		errorRate := "-"
+		if total.Errors >= 0 {
+			errorRate = fmt.Sprintf("%.2f", total.ErrorRate())
+		}
+		fmt.Fprintf(out, "| %s | %.0f | %s | %s | %s | %s | %s | %s |\n",
+			filepath.Base(paths[i]), run.Throughput(), errorRate,
+			unit.Value(total.Percentile(50)), unit.Value(total.Percentile(95)),
+			unit.Value(total.Percentile(99)), unit.Value(total.Percentile(99.9)), unit.Value(total.Max()))
+	}
+	_, err := fmt.Fprintln(out)
+	return err
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"kvstore-benchmarker/pkg/compare"
+	"kvstore-benchmarker/pkg/latency"
+	"kvstore-benchmarker/pkg/runner"
+)
+
+// runCompare prints the per-method changes in throughput, latency
+// percentiles and error rate between two runs' JSON summaries or results CSVs
+func runCompare(args []string) error {
+	fs := flag.NewFlagSet("compare", flag.ExitOnError)
+	threshold := fs.Float64("threshold", 5, "Highlight changes for the worse (or better) by more than this many percent")
+	unitName := fs.String("latency-unit", "ms", "Unit of latencies: ms, us or ns")
+	color := fs.String("color", "auto", "Color regressions and improvements: auto, always or never")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: %s compare [flags] <baseline> <current>\n", os.Args[0])
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	if fs.NArg() != 2 {
+		fs.Usage()
+		return fmt.Errorf("expected a baseline and a current results file")
+	}
+	unit, err := latency.ParseUnit(*unitName)
+	if err != nil {
+		return err
+	}
+
+	base, err := compare.Load(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	current, err := compare.Load(fs.Arg(1))
+	if err != nil {
+		return err
+	}
+	runner.WriteComparison(os.Stdout, base, current, *threshold, *color, unit)
+	return nil
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"kvstore-benchmarker/pkg/archive"
+	"kvstore-benchmarker/pkg/latency"
+)
+
+// runConvert converts a binary result archive into CSV or JSON lines
+func runConvert(args []string) error {
+	fs := flag.NewFlagSet("convert", flag.ExitOnError)
+	format := fs.String("format", "json", "Output format: json or csv")
+	records := fs.String("records", "summary", "Records to emit in CSV: ops, intervals or summary")
+	unitName := fs.String("latency-unit", "ms", "Unit of latencies in CSV output: ms, us or ns")
+	output := fs.String("o", "", "Output file path (default stdout)")
+	from := fs.Duration("from", 0, "Only include records from this offset after the run start")
+	to := fs.Duration("to", 0, "Only include records up to this offset after the run start (0 for the end)")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: %s convert [flags] <archive>\n", os.Args[0])
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return fmt.Errorf("expected exactly one archive file")
+	}
+
+	unit, err := latency.ParseUnit(*unitName)
+	if err != nil {
+		return err
+	}
+
+	in, err := os.Open(fs.Arg(0))
+	if err != nil {
+		return fmt.Errorf("failed to open archive: %w", err)
+	}
+	defer in.Close()
+
+	out := os.Stdout
+	if *output != "" {
+		out, err = os.Create(*output)
+		if err != nil {
+			return fmt.Errorf("failed to create output file: %w", err)
+		}
+		defer out.Close()
+	}
+
+	reader := archive.NewReader(in)
+	window := archive.Window{From: *from, To: *to}
+	switch *format {
+	case "json":
+		return archive.ToJSON(reader, out, window)
+	case "csv":
+		return archive.ToCSV(reader, out, *records, unit, window)
+	default:
+		return fmt.Errorf("unknown format %q", *format)
+	}
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"kvstore-benchmarker/pkg/grafana"
+)
+
+// runGrafana writes a Grafana dashboard for the metrics exported with
+// --remote-write or --pushgateway, showing the annotations of --grafana-url
+func runGrafana(args []string) error {
+	fs := flag.NewFlagSet("grafana", flag.ExitOnError)
+	title := fs.String("title", "KV Store Benchmark", "Dashboard title")
+	uid := fs.String("uid", "kvbench", "Dashboard UID, which provisioning and links refer to")
+	labels := fs.String("labels", "", "Comma-separated run label names to add filters for (e.g. team,env)")
+	output := fs.String("o", "-", "Write the dashboard JSON to this file (- for stdout)")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: %s grafana [flags]\n", os.Args[0])
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	if fs.NArg() != 0 {
+		fs.Usage()
+		return fmt.Errorf("unexpected arguments %v", fs.Args())
+	}
+
+	var names []string
+	if *labels != "" {
+		names = strings.Split(*labels, ",")
+	}
+	data, err := grafana.Dashboard(grafana.DashboardOptions{Title: *title, UID: *uid, Labels: names})
+	if err != nil {
+		return err
+	}
+	data = append(data, '\n')
+
+	if *output == "-" {
+		_, err = os.Stdout.Write(data)
+		return err
+	}
+	return os.WriteFile(*output, data, 0o644)
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"errors"
+	"log"
+	"os"
+
+	"kvstore-benchmarker/pkg/config"
+	"kvstore-benchmarker/pkg/runner"
+)
+
+func main() {
+	// Human-readable logs go to stderr, stdout is reserved for machine-readable output
+	log.SetOutput(os.Stderr)
+
+	// Dispatch subcommands before parsing benchmark flags
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "convert":
+			if err := runConvert(os.Args[2:]); err != nil {
+				log.Fatalf("convert: %v", err)
+			}
+			return
+		case "analyze":
+			if err := runAnalyze(os.Args[2:]); err != nil {
+				log.Fatalf("analyze: %v", err)
+			}
+			return
+		case "compare":
+			if err := runCompare(os.Args[2:]); err != nil {
+				log.Fatalf("compare: %v", err)
+			}
+			return
+		case "capacity":
+			if err := runCapacity(os.Args[2:]); err != nil {
+				log.Fatalf("capacity: %v", err)
+			}
+			return
+		case "query":
+			if err := runQuery(os.Args[2:]); err != nil {
+				log.Fatalf("query: %v", err)
+			}
+			return
+		case "selftest":
+			// A failed self-test exits with status 2 to tell it apart from errors
+			if err := runSelfTest(os.Args[2:]); errors.Is(err, runner.ErrSelfTestFailed) {
+				log.Printf("selftest: %v", err)
+				os.Exit(2)
+			} else if err != nil {
+				log.Fatalf("selftest: %v", err)
+			}
+			return
+		case "scenarios":
+			// Like a single run, failed SLO assertions, regression thresholds and searches exit with status 2
+			if err := runScenarios(os.Args[2:]); errors.Is(err, runner.ErrSLOViolated) || errors.Is(err, runner.ErrRegressed) || errors.Is(err, runner.ErrTargetNotMet) {
+				log.Printf("scenarios: %v", err)
+				os.Exit(2)
+			} else if err != nil {
+				log.Fatalf("scenarios: %v", err)
+			}
+			return
+		case "shell":
+			if err := runShell(os.Args[2:]); err != nil {
+				log.Fatalf("shell: %v", err)
+			}
+			return
+		case "grafana":
+			if err := runGrafana(os.Args[2:]); err != nil {
+				log.Fatalf("grafana: %v", err)
+			}
+			return
+		}
+	}
+
+	cfg := config.ParseFlags()
+	if err := cfg.Validate(); err != nil {
+		log.Fatalf("Invalid configuration: %v", err)
+	}
+
+	// Failed SLO assertions, regression thresholds and searches exit with status 2 to tell them apart from errors
+	if err := runner.Repeat(cfg); errors.Is(err, runner.ErrSLOViolated) || errors.Is(err, runner.ErrRegressed) || errors.Is(err, runner.ErrTargetNotMet) {
+		log.Printf("Benchmark failed: %v", err)
+		os.Exit(2)
+	} else if err != nil {
+		log.Fatalf("Benchmark failed: %v", err)
+	}
+}
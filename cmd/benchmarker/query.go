@@ -0,0 +1,95 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/HdrHistogram/hdrhistogram-go"
+
+	"kvstore-benchmarker/pkg/histstore"
+	"kvstore-benchmarker/pkg/latency"
+)
+
+// queryPercentiles are the percentiles reported by the query subcommand
+var queryPercentiles = []float64{50, 90, 95, 99, 99.9, 99.99}
+
+// runQuery prints percentiles over a time window of a histogram store
+func runQuery(args []string) error {
+	fs := flag.NewFlagSet("query", flag.ExitOnError)
+	method := fs.String("method", "", "Only query this method (default: each method and all combined)")
+	from := fs.Duration("from", 0, "Start of the window as an offset from the run start")
+	to := fs.Duration("to", 0, "End of the window as an offset from the run start (0 for the end of the run)")
+	unitName := fs.String("latency-unit", "ms", "Unit of latencies: ms, us or ns")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: %s query [flags] <histogram-store>\n", os.Args[0])
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return fmt.Errorf("expected exactly one histogram store file")
+	}
+
+	unit, err := latency.ParseUnit(*unitName)
+	if err != nil {
+		return err
+	}
+
+	store, err := histstore.Open(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	methods := []string{*method}
+	if *method == "" {
+		methods = append(store.Methods(), "")
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', tabwriter.AlignRight)
+	header := []string{"method", "intervals", "ops"}
+	for _, p := range queryPercentiles {
+		header = append(header, unit.Column(fmt.Sprintf("p%g", p)))
+	}
+	header = append(header, unit.Column("max"))
+	fmt.Fprintln(w, strings.Join(header, "\t")+"\t")
+
+	for _, m := range methods {
+		h, n, err := store.Query(m, *from, *to)
+		if err != nil {
+			return err
+		}
+		name := m
+		if name == "" {
+			name = "ALL"
+		}
+		fmt.Fprintln(w, strings.Join(queryRow(name, n, h, unit), "\t")+"\t")
+	}
+	return w.Flush()
+}
+
+// queryRow formats the percentiles of a merged histogram, which records nanoseconds
+func queryRow(name string, intervals int, h *hdrhistogram.Histogram, unit latency.Unit) []string {
+	row := []string{name, fmt.Sprintf("%d", intervals)}
+	if h == nil {
+		row = append(row, "0")
+		for range queryPercentiles {
+			row = append(row, "-")
+		}
+		return append(row, "-")
+	}
+
+	ms := func(ns int64) string {
+		return unit.Value(float64(ns) / float64(time.Millisecond))
+	}
+	row = append(row, fmt.Sprintf("%d", h.TotalCount()))
+	for _, p := range queryPercentiles {
+		row = append(row, ms(h.ValueAtQuantile(p)))
+	}
+	return append(row, ms(h.Max()))
+}
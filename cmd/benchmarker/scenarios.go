@@ -0,0 +1,76 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"text/tabwriter"
+
+	"kvstore-benchmarker/pkg/runner"
+	"kvstore-benchmarker/pkg/scenario"
+)
+
+// runScenarios lists, shows and runs the built-in scenarios. Benchmark flags
+// after the scenario name override the scenario's settings, e.g.
+// "scenarios run ycsb-a --target=db:50051 --duration=1m".
+func runScenarios(args []string) error {
+	usage := fmt.Errorf("usage: %s scenarios list | show <name> | run <name> [benchmark flags]", os.Args[0])
+	if len(args) == 0 {
+		return usage
+	}
+
+	switch args[0] {
+	case "list":
+		scenarios, err := scenario.List()
+		if err != nil {
+			return err
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		for _, s := range scenarios {
+			fmt.Fprintf(w, "%s\t%s\n", s.Name, s.Description)
+		}
+		return w.Flush()
+	case "show":
+		if len(args) != 2 {
+			return usage
+		}
+		data, err := scenario.Source(args[1])
+		if err != nil {
+			return err
+		}
+		_, err = os.Stdout.Write(data)
+		return err
+	case "run":
+		if len(args) < 2 {
+			return usage
+		}
+		s, err := scenario.Get(args[1])
+		if err != nil {
+			return err
+		}
+		return runScenario(s, args[2:])
+	}
+	return usage
+}
+
+// runScenario runs the steps of a scenario one after the other, stopping at
+// the first that fails
+func runScenario(s *scenario.Scenario, args []string) error {
+	for i, step := range s.Steps {
+		cfg, err := s.StepConfig(i, args)
+		if err != nil {
+			return err
+		}
+		if err := cfg.Validate(); err != nil {
+			return fmt.Errorf("invalid configuration: %w", err)
+		}
+		if len(s.Steps) > 1 {
+			log.Printf("Scenario %s: step %d/%d (%s)", s.Name, i+1, len(s.Steps), step.Name)
+		}
+
+		if err := runner.Repeat(cfg); err != nil {
+			return err
+		}
+	}
+	return nil
+}
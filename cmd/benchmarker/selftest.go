@@ -0,0 +1,70 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"kvstore-benchmarker/pkg/collector"
+	"kvstore-benchmarker/pkg/config"
+	"kvstore-benchmarker/pkg/runner"
+)
+
+// runSelfTest checks that this host can generate the target load with
+// accurate percentiles, and prints the highest credible load it can generate
+func runSelfTest(args []string) error {
+	cfg := config.DefaultConfig()
+	fs := flag.NewFlagSet("selftest", flag.ExitOnError)
+	fs.IntVar(&cfg.NumConnections, "connections", cfg.NumConnections, "Number of gRPC connections")
+	fs.IntVar(&cfg.NumWorkers, "workers", cfg.NumWorkers, "Number of concurrent workers")
+	fs.IntVar(&cfg.KeySpace, "keyspace", cfg.KeySpace, "Number of unique keys")
+	fs.IntVar(&cfg.ValueSize, "valuesize", cfg.ValueSize, "Size of values in bytes")
+	fs.StringVar(&cfg.PercentileEngine, "percentile-engine", cfg.PercentileEngine, "Latency recorder to check: hdr, tdigest, exact, kll or reservoir")
+	fs.StringVar(&cfg.Percentiles, "percentiles", cfg.Percentiles, "Comma-separated percentiles to check")
+	duration := fs.Duration("duration", 5*time.Second, "Duration of each load phase")
+	rate := fs.Int("rate", 0, "Target rate in ops/sec the host must sustain (0 = only measure the maximum)")
+	tolerance := fs.Float64("tolerance", 0, "Allowed relative percentile error (0 for the engine default)")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: %s selftest [flags]\n", os.Args[0])
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	cfg.WarmupDuration = 0
+	cfg.Duration = *duration
+	if err := cfg.Validate(); err != nil {
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
+	if *tolerance <= 0 {
+		*tolerance = runner.SelfTestTolerance(cfg.PercentileEngine)
+	}
+
+	result, err := runner.SelfTest(cfg, *duration, *rate, *tolerance)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Maximum credible load: %.0f ops/sec with %d workers over %d connections\n",
+		result.MaxRate, cfg.NumWorkers, cfg.NumConnections)
+	if result.TargetRate > 0 {
+		verdict := "PASS"
+		if !result.RateMet() {
+			verdict = "FAIL"
+		}
+		fmt.Printf("Target rate: %d ops/sec, achieved %.0f ops/sec: %s\n", result.TargetRate, result.AchievedRate, verdict)
+	}
+	for i, p := range result.Percentiles {
+		verdict := "PASS"
+		if result.Errors[i] > result.Tolerance {
+			verdict = "FAIL"
+		}
+		fmt.Printf("Percentile accuracy (%s): %s error %.3f%% (tolerance %.3f%%): %s\n",
+			result.Engine, collector.PercentileLabel(p), result.Errors[i]*100, result.Tolerance*100, verdict)
+	}
+
+	if !result.RateMet() || !result.AccuracyMet() {
+		return runner.ErrSelfTestFailed
+	}
+	return nil
+}
@@ -0,0 +1,190 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/hex"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"kvstore-benchmarker/pkg/config"
+	"kvstore-benchmarker/pkg/kvclient"
+	"kvstore-benchmarker/pkg/latency"
+	"kvstore-benchmarker/pkg/runner"
+)
+
+// shellHelp lists the commands of the interactive shell
+const shellHelp = `Commands:
+  get <key>            Read a key
+  put <key> <value>    Write a key; the value is the rest of the line
+  delete <key>         Delete a key
+  scan [prefix]        Range reads (not supported by the KeyValueStore service)
+  help                 Show this help
+  quit                 Leave the shell
+Keys and values starting with 0x are hex-encoded bytes.`
+
+// runShell reads commands from stdin and issues them against the target with
+// the benchmark's client, connection options and request credentials,
+// printing each result and its latency
+func runShell(args []string) error {
+	fs := flag.NewFlagSet("shell", flag.ExitOnError)
+	cfg := config.DefaultConfig()
+	cfg.RegisterClientFlags(fs)
+	timeout := fs.Duration("timeout", 5*time.Second, "Deadline of every command")
+	unitName := fs.String("latency-unit", "ms", "Unit of latencies: ms, us or ns")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: %s shell [flags]\n", os.Args[0])
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	unit, err := latency.ParseUnit(*unitName)
+	if err != nil {
+		return err
+	}
+
+	if err := cfg.ValidateClient(); err != nil {
+		return err
+	}
+	client, closeClient, err := runner.NewClient(cfg)
+	if err != nil {
+		return err
+	}
+	defer closeClient()
+
+	// Only prompt when a person is typing
+	interactive := false
+	if info, err := os.Stdin.Stat(); err == nil {
+		interactive = info.Mode()&os.ModeCharDevice != 0
+	}
+	if interactive {
+		fmt.Fprintf(os.Stderr, "Connected to %s. Type help for commands.\n", cfg.TargetAddress)
+	}
+
+	scanner := bufio.NewScanner(os.Stdin)
+	for {
+		if interactive {
+			fmt.Fprint(os.Stderr, "kvbench> ")
+		}
+		if !scanner.Scan() {
+			break
+		}
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if line == "quit" || line == "exit" {
+			return nil
+		}
+		shellCommand(os.Stdout, client, line, *timeout, unit)
+	}
+	if err := scanner.Err(); err != nil && err != io.EOF {
+		return fmt.Errorf("failed to read commands: %w", err)
+	}
+	return nil
+}
+
+// shellCommand runs one shell command line and prints its outcome
+func shellCommand(w io.Writer, client *kvclient.Client, line string, timeout time.Duration, unit latency.Unit) {
+	command, rest, _ := strings.Cut(line, " ")
+	rest = strings.TrimSpace(rest)
+	command = strings.ToLower(command)
+
+	var keyArg, valueArg string
+	switch command {
+	case "get", "delete":
+		keyArg = rest
+	case "put":
+		keyArg, valueArg, _ = strings.Cut(rest, " ")
+		valueArg = strings.TrimSpace(valueArg)
+	case "scan":
+		fmt.Fprintln(w, "ERR scan is not supported: the KeyValueStore service has no range reads")
+		return
+	case "help":
+		fmt.Fprintln(w, shellHelp)
+		return
+	default:
+		fmt.Fprintf(w, "ERR unknown command %q (type help for commands)\n", command)
+		return
+	}
+	if keyArg == "" {
+		fmt.Fprintf(w, "ERR %s needs a key\n", command)
+		return
+	}
+	key, err := shellBytes(keyArg)
+	if err != nil {
+		fmt.Fprintf(w, "ERR %v\n", err)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	start := time.Now()
+	var out string
+	switch command {
+	case "get":
+		resp, getErr := client.Get(ctx, key)
+		err = getErr
+		if err == nil {
+			if resp.Found {
+				out = fmt.Sprintf("%s (%d bytes)", shellFormat(resp.Value), len(resp.Value))
+			} else {
+				out = "(not found)"
+			}
+		}
+	case "put":
+		var value []byte
+		if value, err = shellBytes(valueArg); err != nil {
+			fmt.Fprintf(w, "ERR %v\n", err)
+			return
+		}
+		if _, err = client.Put(ctx, key, value); err == nil {
+			out = fmt.Sprintf("OK (%d bytes)", len(value))
+		}
+	case "delete":
+		if _, err = client.Delete(ctx, key); err == nil {
+			out = "OK"
+		}
+	}
+	elapsed := unit.Display(float64(time.Since(start)) / float64(time.Millisecond))
+
+	if err != nil {
+		fmt.Fprintf(w, "ERR %v [%s]\n", err, elapsed)
+		return
+	}
+	fmt.Fprintf(w, "%s [%s]\n", out, elapsed)
+}
+
+// shellBytes decodes a shell argument, hex-encoded when prefixed with 0x
+func shellBytes(arg string) ([]byte, error) {
+	if strings.HasPrefix(arg, "0x") {
+		b, err := hex.DecodeString(arg[2:])
+		if err != nil {
+			return nil, fmt.Errorf("invalid hex %q: %w", arg, err)
+		}
+		return b, nil
+	}
+	return []byte(arg), nil
+}
+
+// shellFormat renders a value as quoted text when printable, hex otherwise
+func shellFormat(value []byte) string {
+	if utf8.Valid(value) {
+		printable := true
+		for _, r := range string(value) {
+			if r < 0x20 && r != '\t' {
+				printable = false
+				break
+			}
+		}
+		if printable {
+			return fmt.Sprintf("%q", value)
+		}
+	}
+	return "0x" + hex.EncodeToString(value)
+}
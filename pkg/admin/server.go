@@ -0,0 +1,69 @@
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Server is the HTTP admin endpoint for controlling a running benchmark, also
+// serving the read-only web dashboard
+type Server struct {
+	name   string
+	mux    *http.ServeMux
+	server *http.Server
+}
+
+// NewServer creates a server with no routes registered, named in log lines
+// (e.g. "Admin server")
+func NewServer(name string) *Server {
+	mux := http.NewServeMux()
+	return &Server{
+		name: name,
+		mux:  mux,
+		server: &http.Server{
+			Handler:           mux,
+			ReadHeaderTimeout: 5 * time.Second,
+		},
+	}
+}
+
+// Handle registers a handler for the given pattern
+func (s *Server) Handle(pattern string, handler http.HandlerFunc) {
+	s.mux.HandleFunc(pattern, handler)
+}
+
+// Start listens on the given address and serves requests in the background
+func (s *Server) Start(address string) error {
+	lis, err := net.Listen("tcp", address)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", address, err)
+	}
+
+	go func() {
+		if err := s.server.Serve(lis); err != nil && err != http.ErrServerClosed {
+			log.Printf("Warning: %s stopped: %v", strings.ToLower(s.name), err)
+		}
+	}()
+
+	log.Printf("%s listening on %s", s.name, lis.Addr())
+	return nil
+}
+
+// Shutdown gracefully stops the server
+func (s *Server) Shutdown(ctx context.Context) error {
+	return s.server.Shutdown(ctx)
+}
+
+// WriteJSON writes v as a JSON response body
+func WriteJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.Printf("Warning: failed to write admin response: %v", err)
+	}
+}
@@ -0,0 +1,160 @@
+// Package analyze computes latency statistics over time windows of recorded
+// runs, reading binary result archives, raw result logs and histogram stores,
+// so that runs can be examined after the fact without re-running them.
+package analyze
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"time"
+
+	"kvstore-benchmarker/pkg/archive"
+	"kvstore-benchmarker/pkg/histstore"
+)
+
+// MethodStats holds the results of one method within a window. Errors is -1
+// when the source does not record errors.
+type MethodStats struct {
+	Method string
+	*archive.Accumulator
+}
+
+// newMethodStats creates empty statistics for a method
+func newMethodStats(method string) *MethodStats {
+	return &MethodStats{Method: method, Accumulator: archive.NewAccumulator()}
+}
+
+// Percentile returns the latency in milliseconds at percentile p (0-100) of
+// successful operations
+func (m *MethodStats) Percentile(p float64) float64 {
+	if m.Histogram.TotalCount() == 0 {
+		return 0
+	}
+	return float64(m.Histogram.ValueAtQuantile(p)) / float64(time.Millisecond)
+}
+
+// Max returns the maximum latency in milliseconds of successful operations
+func (m *MethodStats) Max() float64 {
+	return float64(m.Histogram.Max()) / float64(time.Millisecond)
+}
+
+// ErrorRate returns the error percentage, or -1 when errors are not recorded
+func (m *MethodStats) ErrorRate() float64 {
+	if m.Errors < 0 {
+		return -1
+	}
+	if m.Ops == 0 {
+		return 0
+	}
+	return float64(m.Errors) / float64(m.Ops) * 100
+}
+
+// Result holds the per-method statistics of a window
+type Result struct {
+	Format  string // Source format: archive, raw-log or histogram-store
+	Window  archive.Window
+	Methods map[string]*MethodStats
+
+	// Offsets of the first and last result within the window
+	First, Last time.Duration
+	seen        bool
+}
+
+// span extends the covered time of the result to an offset
+func (r *Result) span(offset time.Duration) {
+	if !r.seen || offset < r.First {
+		r.First = offset
+	}
+	if !r.seen || offset > r.Last {
+		r.Last = offset
+	}
+	r.seen = true
+}
+
+// Throughput returns the operations per second of all methods combined over
+// the covered time
+func (r *Result) Throughput() float64 {
+	seconds := (r.Last - r.First).Seconds()
+	if seconds <= 0 {
+		return 0
+	}
+	var ops int64
+	for _, m := range r.Methods {
+		ops += m.Ops
+	}
+	return float64(ops) / seconds
+}
+
+// Names returns the sorted method names
+func (r *Result) Names() []string {
+	names := make([]string, 0, len(r.Methods))
+	for name := range r.Methods {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Total returns the statistics of all methods combined
+func (r *Result) Total() *MethodStats {
+	total := newMethodStats("ALL")
+	unknown := false
+	for _, m := range r.Methods {
+		total.Merge(m.Accumulator)
+		unknown = unknown || m.Errors < 0
+	}
+	if unknown {
+		total.Errors = -1
+	}
+	return total
+}
+
+// method returns the statistics of a method, creating them on first use
+func (r *Result) method(name string) *MethodStats {
+	m, ok := r.Methods[name]
+	if !ok {
+		m = newMethodStats(name)
+		r.Methods[name] = m
+	}
+	return m
+}
+
+// Load reads a recorded run and computes the statistics of the window. The
+// format is detected from the file contents. Offsets are relative to the run
+// start for archives and histogram stores, and to the first result for raw
+// logs, which carry no run header.
+func Load(path string, window archive.Window) (*Result, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	defer file.Close()
+
+	buf := bufio.NewReaderSize(file, 64*1024)
+	head, err := buf.Peek(8)
+	if err != nil && err != io.EOF {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	result := &Result{Window: window, Methods: make(map[string]*MethodStats)}
+	switch {
+	case string(head) == histstore.Magic:
+		file.Close()
+		result.Format = "histogram-store"
+		err = loadHistogramStore(path, result)
+	case bytes.HasPrefix(bytes.TrimLeft(head, " \t\r\n"), []byte("{")):
+		result.Format = "raw-log"
+		err = loadRawLog(buf, result)
+	default:
+		result.Format = "archive"
+		err = loadArchive(buf, result)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
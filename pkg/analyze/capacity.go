@@ -0,0 +1,73 @@
+package analyze
+
+import (
+	"time"
+
+	"kvstore-benchmarker/pkg/collector"
+	"kvstore-benchmarker/pkg/slo"
+)
+
+// Limit is the highest throughput of a sweep at which an objective was met
+type Limit struct {
+	Objective  slo.Assertion
+	Run        int // Index of the run setting the limit, -1 if no run met the objective
+	Throughput float64
+}
+
+// Headroom returns the percentage by which the limit exceeds a production
+// load in ops/sec; it is negative when the load is beyond the limit
+func (l Limit) Headroom(load float64) float64 {
+	return (l.Throughput/load - 1) * 100
+}
+
+// Capacity finds, for every objective, the highest-throughput run of a sweep
+// that met it. Throughput is that of all methods combined. Error rate
+// objectives are never met by sources that do not record errors.
+func Capacity(runs []*Result, objectives []slo.Assertion) []Limit {
+	limits := make([]Limit, len(objectives))
+	for i, objective := range objectives {
+		limits[i] = Limit{Objective: objective, Run: -1}
+		for j, run := range runs {
+			if objective.Metric == "error_rate" && run.Total().Errors < 0 {
+				continue
+			}
+			throughput := run.Throughput()
+			if !run.Evaluate(objective).Passed || throughput <= limits[i].Throughput {
+				continue
+			}
+			limits[i].Run = j
+			limits[i].Throughput = throughput
+		}
+	}
+	return limits
+}
+
+// Evaluate checks an SLO assertion against the result. Phases do not apply to
+// recorded runs and are ignored.
+func (r *Result) Evaluate(a slo.Assertion) slo.Result {
+	stats := make(map[string]collector.Stats, len(r.Methods))
+	for name, m := range r.Methods {
+		stats[name] = m.Stats()
+	}
+	return a.Evaluate(stats, r.Total().Stats(), (r.Last - r.First).Seconds())
+}
+
+// Stats converts the statistics into the collector's representation
+func (m *MethodStats) Stats() collector.Stats {
+	stats := collector.Stats{
+		Method:      m.Method,
+		Count:       m.Ops,
+		ErrorCount:  max(m.Errors, 0),
+		ErrorRate:   max(m.ErrorRate(), 0),
+		MinLatency:  float64(m.Histogram.Min()) / float64(time.Millisecond),
+		MaxLatency:  m.Max(),
+		P50Latency:  m.Percentile(50),
+		P95Latency:  m.Percentile(95),
+		P99Latency:  m.Percentile(99),
+		P999Latency: m.Percentile(99.9),
+	}
+	if m.Histogram.TotalCount() > 0 {
+		stats.AvgLatency = m.Histogram.Mean() / float64(time.Millisecond)
+	}
+	return stats
+}
@@ -0,0 +1,113 @@
+package analyze
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"kvstore-benchmarker/pkg/archive"
+	"kvstore-benchmarker/pkg/histstore"
+)
+
+// rawRecord is the subset of a raw result log line used for analysis
+type rawRecord struct {
+	Timestamp time.Time `json:"timestamp"`
+	OffsetNs  *int64    `json:"offset_ns"` // Absent in logs of older versions
+	Method    string    `json:"method"`
+	LatencyNs int64     `json:"latency_ns"`
+	Error     string    `json:"error"`
+}
+
+// loadRawLog reads a raw result log; offsets count from the first result,
+// using the monotonic run offsets when recorded so wall-clock adjustments
+// during the run do not distort them
+func loadRawLog(r io.Reader, result *Result) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	var start time.Time
+	var startOffset *int64
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var record rawRecord
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			return fmt.Errorf("failed to parse raw log line %d: %w", line, err)
+		}
+		if start.IsZero() {
+			start = record.Timestamp
+			startOffset = record.OffsetNs
+		}
+		offset := record.Timestamp.Sub(start)
+		if startOffset != nil && record.OffsetNs != nil {
+			offset = time.Duration(*record.OffsetNs - *startOffset)
+		}
+		if !result.Window.Contains(offset) {
+			continue
+		}
+		result.span(offset)
+		result.method(record.Method).Record(time.Duration(record.LatencyNs), record.Error != "")
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read raw log: %w", err)
+	}
+	return nil
+}
+
+// loadArchive reads the operation results of a binary result archive
+func loadArchive(r io.Reader, result *Result) error {
+	reader := archive.NewReader(r)
+	for {
+		frame, err := reader.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		op := frame.GetOp()
+		if op == nil || !result.Window.Contains(time.Duration(op.OffsetNs)) {
+			continue
+		}
+		result.span(time.Duration(op.OffsetNs))
+		result.method(archive.MethodName(op)).Record(time.Duration(op.LatencyNs), op.Error != "")
+	}
+}
+
+// loadHistogramStore merges the stored interval histograms lying entirely
+// within the window. Stores do not record errors.
+func loadHistogramStore(path string, result *Result) error {
+	store, err := histstore.Open(path)
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	for _, method := range store.Methods() {
+		h, n, err := store.Query(method, result.Window.From, result.Window.To)
+		if err != nil {
+			return err
+		}
+		if n == 0 {
+			continue
+		}
+		m := result.method(method)
+		m.Histogram.Merge(h)
+		m.Ops = h.TotalCount()
+		m.Errors = -1
+	}
+
+	// The covered time is that of the intervals the queries included
+	for _, interval := range store.Intervals() {
+		if result.Window.ContainsSpan(interval.Start, interval.End) {
+			result.span(interval.Start)
+			result.span(interval.End)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,49 @@
+package archive
+
+import (
+	"time"
+
+	"github.com/HdrHistogram/hdrhistogram-go"
+)
+
+// Histograms record nanoseconds up to one hour with three significant digits,
+// matching the collector
+const (
+	histogramMinValue = 1
+	histogramMaxValue = int64(time.Hour)
+	histogramSigFigs  = 3
+)
+
+// Accumulator accumulates the operation results of one method: counts, the
+// sum of successful latencies and their histogram
+type Accumulator struct {
+	Ops       int64
+	Errors    int64
+	Total     time.Duration // Sum of the latencies of successful operations
+	Histogram *hdrhistogram.Histogram
+}
+
+// NewAccumulator creates an empty accumulator
+func NewAccumulator() *Accumulator {
+	return &Accumulator{Histogram: hdrhistogram.New(histogramMinValue, histogramMaxValue, histogramSigFigs)}
+}
+
+// Record adds a single operation result; the latency of failed operations is
+// not recorded
+func (a *Accumulator) Record(latency time.Duration, failed bool) {
+	a.Ops++
+	if failed {
+		a.Errors++
+		return
+	}
+	a.Total += latency
+	a.Histogram.RecordValue(min(max(latency.Nanoseconds(), histogramMinValue), histogramMaxValue))
+}
+
+// Merge adds the results accumulated by another accumulator
+func (a *Accumulator) Merge(other *Accumulator) {
+	a.Ops += other.Ops
+	a.Errors += other.Errors
+	a.Total += other.Total
+	a.Histogram.Merge(other.Histogram)
+}
@@ -0,0 +1,157 @@
+package archive
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"google.golang.org/protobuf/encoding/protodelim"
+
+	apiv1 "kvstore-benchmarker/api/v1"
+)
+
+// Writer streams length-delimited result frames to a file
+type Writer struct {
+	file  *os.File
+	buf   *bufio.Writer
+	start time.Time
+	mu    sync.Mutex
+}
+
+// Create creates an archive file and writes the run header
+func Create(path string, start time.Time, config string) (*Writer, error) {
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create archive file: %w", err)
+	}
+
+	w := &Writer{
+		file:  file,
+		buf:   bufio.NewWriterSize(file, 64*1024),
+		start: start,
+	}
+
+	header := &apiv1.RunHeader{
+		StartUnixNano: start.UnixNano(),
+		Config:        config,
+	}
+	if err := w.WriteFrame(&apiv1.ResultFrame{Record: &apiv1.ResultFrame_Header{Header: header}}); err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	return w, nil
+}
+
+// WriteFrame appends a single frame to the archive
+func (w *Writer) WriteFrame(frame *apiv1.ResultFrame) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if _, err := protodelim.MarshalTo(w.buf, frame); err != nil {
+		return fmt.Errorf("failed to write archive frame: %w", err)
+	}
+	return nil
+}
+
+// WriteOp appends a single operation result to the archive
+func (w *Writer) WriteOp(method string, completed time.Time, latency time.Duration, opErr error) error {
+	op := &apiv1.OpResult{
+		Method:    MethodFromName(method),
+		OffsetNs:  completed.Sub(w.start).Nanoseconds(),
+		LatencyNs: latency.Nanoseconds(),
+	}
+	if op.Method == apiv1.Method_METHOD_UNSPECIFIED {
+		op.MethodName = method
+	}
+	if opErr != nil {
+		op.Error = opErr.Error()
+	}
+	return w.WriteFrame(&apiv1.ResultFrame{Record: &apiv1.ResultFrame_Op{Op: op}})
+}
+
+// WriteInterval appends the per-method statistics of a reporting interval to the archive
+func (w *Writer) WriteInterval(start, end time.Time, methods []*apiv1.MethodStats) error {
+	interval := &apiv1.Interval{
+		StartOffsetNs: start.Sub(w.start).Nanoseconds(),
+		EndOffsetNs:   end.Sub(w.start).Nanoseconds(),
+		Methods:       methods,
+	}
+	return w.WriteFrame(&apiv1.ResultFrame{Record: &apiv1.ResultFrame_Interval{Interval: interval}})
+}
+
+// WriteSummary appends the final run summary to the archive
+func (w *Writer) WriteSummary(end time.Time, methods []*apiv1.MethodStats, aggregated *apiv1.MethodStats) error {
+	summary := &apiv1.Summary{
+		EndOffsetNs: end.Sub(w.start).Nanoseconds(),
+		Methods:     methods,
+		Aggregated:  aggregated,
+	}
+	return w.WriteFrame(&apiv1.ResultFrame{Record: &apiv1.ResultFrame_Summary{Summary: summary}})
+}
+
+// Close flushes buffered frames and closes the archive file
+func (w *Writer) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if err := w.buf.Flush(); err != nil {
+		w.file.Close()
+		return fmt.Errorf("failed to flush archive: %w", err)
+	}
+	return w.file.Close()
+}
+
+// Reader reads result frames from an archive stream
+type Reader struct {
+	buf *bufio.Reader
+}
+
+// NewReader creates a reader over an archive stream
+func NewReader(r io.Reader) *Reader {
+	return &Reader{buf: bufio.NewReaderSize(r, 64*1024)}
+}
+
+// Next returns the next frame, or io.EOF when the stream is exhausted
+func (r *Reader) Next() (*apiv1.ResultFrame, error) {
+	frame := &apiv1.ResultFrame{}
+	if err := protodelim.UnmarshalFrom(r.buf, frame); err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, io.EOF
+		}
+		return nil, fmt.Errorf("failed to read archive frame: %w", err)
+	}
+	return frame, nil
+}
+
+// MethodFromName maps a method name to its compact enum value
+func MethodFromName(name string) apiv1.Method {
+	switch name {
+	case "Get":
+		return apiv1.Method_METHOD_GET
+	case "Put":
+		return apiv1.Method_METHOD_PUT
+	case "Delete":
+		return apiv1.Method_METHOD_DELETE
+	default:
+		return apiv1.Method_METHOD_UNSPECIFIED
+	}
+}
+
+// MethodName returns the method name of an operation result
+func MethodName(op *apiv1.OpResult) string {
+	switch op.Method {
+	case apiv1.Method_METHOD_GET:
+		return "Get"
+	case apiv1.Method_METHOD_PUT:
+		return "Put"
+	case apiv1.Method_METHOD_DELETE:
+		return "Delete"
+	default:
+		return op.MethodName
+	}
+}
@@ -0,0 +1,154 @@
+package archive
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"time"
+
+	"google.golang.org/protobuf/encoding/protojson"
+
+	apiv1 "kvstore-benchmarker/api/v1"
+	"kvstore-benchmarker/pkg/latency"
+)
+
+// ToJSON converts an archive stream into JSON lines, one object per frame.
+// Outside the zero window only operations and intervals within the window are
+// kept, and the whole-run summary is dropped.
+func ToJSON(r *Reader, w io.Writer, window Window) error {
+	marshaler := protojson.MarshalOptions{UseProtoNames: true}
+
+	for {
+		frame, err := r.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if !window.IsZero() && !window.keeps(frame) {
+			continue
+		}
+
+		data, err := marshaler.Marshal(frame)
+		if err != nil {
+			return fmt.Errorf("failed to encode frame as JSON: %w", err)
+		}
+		if _, err := w.Write(append(data, '\n')); err != nil {
+			return fmt.Errorf("failed to write JSON: %w", err)
+		}
+	}
+}
+
+// ToCSV converts the selected record kind ("ops", "intervals" or "summary") of
+// an archive stream into CSV, with latencies in the given unit. Outside the
+// zero window only operations and intervals within the window are written,
+// and the summary is recomputed from the operations within the window.
+func ToCSV(r *Reader, w io.Writer, kind string, unit latency.Unit, window Window) error {
+	csvWriter := csv.NewWriter(w)
+
+	switch kind {
+	case "ops":
+		csvWriter.Write([]string{"timestamp", "method", unit.Column("latency"), "error"})
+	case "intervals", "summary":
+		csvWriter.Write(append([]string{"start", "end"}, statsHeader(unit)...))
+	default:
+		return fmt.Errorf("unknown record kind %q", kind)
+	}
+
+	var start time.Time
+	windowed := newWindowSummary()
+	for {
+		frame, err := r.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		switch record := frame.Record.(type) {
+		case *apiv1.ResultFrame_Header:
+			start = time.Unix(0, record.Header.StartUnixNano).UTC()
+		case *apiv1.ResultFrame_Op:
+			op := record.Op
+			if !window.Contains(time.Duration(op.OffsetNs)) {
+				continue
+			}
+			if kind == "summary" && !window.IsZero() {
+				windowed.add(op)
+			}
+			if kind != "ops" {
+				continue
+			}
+			csvWriter.Write([]string{
+				start.Add(time.Duration(op.OffsetNs)).Format(time.RFC3339Nano),
+				MethodName(op),
+				unit.Value(float64(op.LatencyNs) / float64(time.Millisecond)),
+				op.Error,
+			})
+		case *apiv1.ResultFrame_Interval:
+			if kind != "intervals" || !window.ContainsSpan(time.Duration(record.Interval.StartOffsetNs), time.Duration(record.Interval.EndOffsetNs)) {
+				continue
+			}
+			from := start.Add(time.Duration(record.Interval.StartOffsetNs)).Format(time.RFC3339Nano)
+			to := start.Add(time.Duration(record.Interval.EndOffsetNs)).Format(time.RFC3339Nano)
+			for _, stats := range record.Interval.Methods {
+				csvWriter.Write(append([]string{from, to}, statsRow(stats, unit)...))
+			}
+		case *apiv1.ResultFrame_Summary:
+			if kind != "summary" || !window.IsZero() {
+				continue
+			}
+			from := start.Format(time.RFC3339Nano)
+			to := start.Add(time.Duration(record.Summary.EndOffsetNs)).Format(time.RFC3339Nano)
+			for _, stats := range record.Summary.Methods {
+				csvWriter.Write(append([]string{from, to}, statsRow(stats, unit)...))
+			}
+			if record.Summary.Aggregated != nil {
+				csvWriter.Write(append([]string{from, to}, statsRow(record.Summary.Aggregated, unit)...))
+			}
+		}
+	}
+
+	if kind == "summary" && !window.IsZero() {
+		from := start.Add(windowed.first).Format(time.RFC3339Nano)
+		to := start.Add(windowed.last).Format(time.RFC3339Nano)
+		for _, stats := range windowed.summary() {
+			csvWriter.Write(append([]string{from, to}, statsRow(stats, unit)...))
+		}
+	}
+
+	csvWriter.Flush()
+	return csvWriter.Error()
+}
+
+// statsHeader lists the CSV columns produced by statsRow
+func statsHeader(unit latency.Unit) []string {
+	return []string{
+		"method",
+		"total_ops",
+		"error_ops",
+		unit.Column("avg_latency"),
+		unit.Column("p50_latency"),
+		unit.Column("p95_latency"),
+		unit.Column("p99_latency"),
+		unit.Column("min_latency"),
+		unit.Column("max_latency"),
+	}
+}
+
+// statsRow renders method statistics as CSV fields
+func statsRow(stats *apiv1.MethodStats, unit latency.Unit) []string {
+	return []string{
+		stats.Method,
+		fmt.Sprintf("%d", stats.Count),
+		fmt.Sprintf("%d", stats.ErrorCount),
+		unit.Value(stats.AvgLatencyMs),
+		unit.Value(stats.P50LatencyMs),
+		unit.Value(stats.P95LatencyMs),
+		unit.Value(stats.P99LatencyMs),
+		unit.Value(stats.MinLatencyMs),
+		unit.Value(stats.MaxLatencyMs),
+	}
+}
@@ -0,0 +1,124 @@
+package archive
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	apiv1 "kvstore-benchmarker/api/v1"
+)
+
+// Window selects records by their offset from the run start, e.g. to exclude
+// warm-up or fault-injection periods. A non-positive To leaves the window open
+// until the end of the run; the zero Window selects everything.
+type Window struct {
+	From time.Duration
+	To   time.Duration
+}
+
+// IsZero reports whether the window selects the whole run
+func (w Window) IsZero() bool {
+	return w.From <= 0 && w.To <= 0
+}
+
+// Contains reports whether an offset lies within the window
+func (w Window) Contains(offset time.Duration) bool {
+	return offset >= w.From && (w.To <= 0 || offset <= w.To)
+}
+
+// ContainsSpan reports whether the span [start, end] lies entirely within the window
+func (w Window) ContainsSpan(start, end time.Duration) bool {
+	return start >= w.From && (w.To <= 0 || end <= w.To)
+}
+
+// String formats the window as "from-to"
+func (w Window) String() string {
+	if w.To <= 0 {
+		return fmt.Sprintf("%v-end", w.From)
+	}
+	return fmt.Sprintf("%v-%v", w.From, w.To)
+}
+
+// keeps reports whether a frame survives filtering to the window: the header
+// always does, the whole-run summary never does
+func (w Window) keeps(frame *apiv1.ResultFrame) bool {
+	switch record := frame.Record.(type) {
+	case *apiv1.ResultFrame_Op:
+		return w.Contains(time.Duration(record.Op.OffsetNs))
+	case *apiv1.ResultFrame_Interval:
+		return w.ContainsSpan(time.Duration(record.Interval.StartOffsetNs), time.Duration(record.Interval.EndOffsetNs))
+	case *apiv1.ResultFrame_Summary:
+		return false
+	default:
+		return true
+	}
+}
+
+// windowSummary accumulates per-method statistics of the operations within a
+// window, for summaries that cannot use the stored whole-run summary
+type windowSummary struct {
+	methods map[string]*Accumulator
+	first   time.Duration
+	last    time.Duration
+}
+
+// newWindowSummary creates an empty window summary
+func newWindowSummary() *windowSummary {
+	return &windowSummary{methods: make(map[string]*Accumulator)}
+}
+
+// add records an operation
+func (s *windowSummary) add(op *apiv1.OpResult) {
+	offset := time.Duration(op.OffsetNs)
+	if len(s.methods) == 0 || offset < s.first {
+		s.first = offset
+	}
+	if offset > s.last {
+		s.last = offset
+	}
+
+	name := MethodName(op)
+	m, ok := s.methods[name]
+	if !ok {
+		m = NewAccumulator()
+		s.methods[name] = m
+	}
+	m.Record(time.Duration(op.LatencyNs), op.Error != "")
+}
+
+// methodStats returns the statistics accumulated for a method
+func methodStats(method string, m *Accumulator) *apiv1.MethodStats {
+	stats := &apiv1.MethodStats{Method: method, Count: m.Ops, ErrorCount: m.Errors}
+	if success := m.Ops - m.Errors; success > 0 {
+		ms := func(ns int64) float64 { return float64(ns) / float64(time.Millisecond) }
+		stats.AvgLatencyMs = ms(int64(m.Total) / success)
+		stats.MinLatencyMs = ms(m.Histogram.Min())
+		stats.MaxLatencyMs = ms(m.Histogram.Max())
+		stats.P50LatencyMs = ms(m.Histogram.ValueAtQuantile(50))
+		stats.P95LatencyMs = ms(m.Histogram.ValueAtQuantile(95))
+		stats.P99LatencyMs = ms(m.Histogram.ValueAtQuantile(99))
+	}
+	return stats
+}
+
+// summary returns per-method statistics in method order, followed by the
+// aggregated statistics of all methods
+func (s *windowSummary) summary() []*apiv1.MethodStats {
+	names := make([]string, 0, len(s.methods))
+	for name := range s.methods {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	all := NewAccumulator()
+	var rows []*apiv1.MethodStats
+	for _, name := range names {
+		m := s.methods[name]
+		rows = append(rows, methodStats(name, m))
+		all.Merge(m)
+	}
+	if len(names) > 0 {
+		rows = append(rows, methodStats("AGGREGATED", all))
+	}
+	return rows
+}
@@ -0,0 +1,138 @@
+package chart
+
+import (
+	"fmt"
+	"html"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Chart dimensions in SVG user units
+const (
+	width        = 760
+	height       = 300
+	marginLeft   = 70
+	marginRight  = 20
+	marginTop    = 36
+	marginBottom = 46
+	yTicks       = 5
+	xTicks       = 6
+)
+
+// palette colors the series in order
+var palette = []string{"#1f77b4", "#ff7f0e", "#2ca02c", "#d62728", "#9467bd", "#8c564b", "#e377c2", "#7f7f7f"}
+
+// Point is a data point of a series
+type Point struct {
+	X, Y float64
+}
+
+// Series is a named sequence of points
+type Series struct {
+	Name   string
+	Points []Point
+}
+
+// Chart is a two-dimensional chart of one or more series. Line charts connect
+// the points of each series in order, scatter charts only mark them.
+type Chart struct {
+	Name    string // Short identifier, e.g. the base name of an image file
+	Title   string
+	XLabel  string
+	YLabel  string
+	Scatter bool
+	Series  []Series
+}
+
+// Empty reports whether the chart has no points to plot
+func (c Chart) Empty() bool {
+	for _, s := range c.Series {
+		if len(s.Points) > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// SVG renders the chart as a standalone SVG element. Both axes start at zero
+// and end at the largest value plotted, so charts of a run compare at a glance.
+func (c Chart) SVG() string {
+	var maxX, maxY float64
+	for _, s := range c.Series {
+		for _, p := range s.Points {
+			maxX = math.Max(maxX, p.X)
+			maxY = math.Max(maxY, p.Y)
+		}
+	}
+	maxX, maxY = niceMax(maxX), niceMax(maxY)
+
+	plotW := float64(width - marginLeft - marginRight)
+	plotH := float64(height - marginTop - marginBottom)
+	x := func(v float64) float64 { return marginLeft + v/maxX*plotW }
+	y := func(v float64) float64 { return marginTop + plotH - v/maxY*plotH }
+
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 %d %d" width="%d" height="%d" font-family="sans-serif" font-size="11">`, width, height, width, height)
+	b.WriteString("\n")
+	fmt.Fprintf(&b, `<rect width="%d" height="%d" fill="#fff"/>`+"\n", width, height)
+	fmt.Fprintf(&b, `<text x="%d" y="20" font-size="14" font-weight="bold">%s</text>`+"\n", marginLeft, html.EscapeString(c.Title))
+
+	// Grid lines and tick labels
+	for i := 0; i <= yTicks; i++ {
+		v := maxY * float64(i) / yTicks
+		fmt.Fprintf(&b, `<line x1="%d" y1="%.1f" x2="%d" y2="%.1f" stroke="#e0e0e0"/>`+"\n", marginLeft, y(v), width-marginRight, y(v))
+		fmt.Fprintf(&b, `<text x="%d" y="%.1f" text-anchor="end" dominant-baseline="middle">%s</text>`+"\n", marginLeft-6, y(v), tickLabel(v))
+	}
+	for i := 0; i <= xTicks; i++ {
+		v := maxX * float64(i) / xTicks
+		fmt.Fprintf(&b, `<text x="%.1f" y="%d" text-anchor="middle">%s</text>`+"\n", x(v), height-marginBottom+16, tickLabel(v))
+	}
+	fmt.Fprintf(&b, `<line x1="%d" y1="%d" x2="%d" y2="%d" stroke="#333"/>`+"\n", marginLeft, height-marginBottom, width-marginRight, height-marginBottom)
+	fmt.Fprintf(&b, `<line x1="%d" y1="%d" x2="%d" y2="%d" stroke="#333"/>`+"\n", marginLeft, marginTop, marginLeft, height-marginBottom)
+	fmt.Fprintf(&b, `<text x="%.1f" y="%d" text-anchor="middle">%s</text>`+"\n", marginLeft+plotW/2, height-8, html.EscapeString(c.XLabel))
+	fmt.Fprintf(&b, `<text transform="translate(14 %.1f) rotate(-90)" text-anchor="middle">%s</text>`+"\n", marginTop+plotH/2, html.EscapeString(c.YLabel))
+
+	for i, s := range c.Series {
+		color := palette[i%len(palette)]
+		if !c.Scatter && len(s.Points) > 1 {
+			coords := make([]string, len(s.Points))
+			for j, p := range s.Points {
+				coords[j] = fmt.Sprintf("%.1f,%.1f", x(p.X), y(p.Y))
+			}
+			fmt.Fprintf(&b, `<polyline fill="none" stroke="%s" stroke-width="1.5" points="%s"/>`+"\n", color, strings.Join(coords, " "))
+		} else {
+			for _, p := range s.Points {
+				fmt.Fprintf(&b, `<circle cx="%.1f" cy="%.1f" r="3" fill="%s" fill-opacity="0.7"/>`+"\n", x(p.X), y(p.Y), color)
+			}
+		}
+
+		// Legend entries from the top right, one per series
+		ly := marginTop + 4 + 16*i
+		fmt.Fprintf(&b, `<rect x="%d" y="%d" width="10" height="10" fill="%s"/>`+"\n", width-marginRight-110, ly, color)
+		fmt.Fprintf(&b, `<text x="%d" y="%d" dominant-baseline="middle">%s</text>`+"\n", width-marginRight-96, ly+5, html.EscapeString(s.Name))
+	}
+
+	b.WriteString("</svg>\n")
+	return b.String()
+}
+
+// niceMax rounds the largest value of an axis up to 1, 2, 2.5 or 5 times a
+// power of ten, so ticks fall on round numbers; empty axes span 0 to 1
+func niceMax(v float64) float64 {
+	if v <= 0 || math.IsNaN(v) || math.IsInf(v, 0) {
+		return 1
+	}
+	magnitude := math.Pow(10, math.Floor(math.Log10(v)))
+	for _, step := range []float64{1, 2, 2.5, 5, 10} {
+		if v <= step*magnitude {
+			return step * magnitude
+		}
+	}
+	return 10 * magnitude
+}
+
+// tickLabel renders a tick value with at most four significant digits
+func tickLabel(v float64) string {
+	return strconv.FormatFloat(v, 'g', 4, 64)
+}
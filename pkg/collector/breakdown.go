@@ -0,0 +1,93 @@
+package collector
+
+import "time"
+
+// Latency breakdown components, in the order an RPC passes them
+const (
+	ComponentQueue  = "queue"  // Client-side stream creation and connection waits
+	ComponentSend   = "send"   // Sending the request
+	ComponentWait   = "wait"   // Network round trip and server processing
+	ComponentServer = "server" // Server processing as reported by the server, part of wait
+	ComponentRecv   = "recv"   // Receiving the response
+)
+
+// BreakdownComponents lists the components in reporting order
+var BreakdownComponents = []string{ComponentQueue, ComponentSend, ComponentWait, ComponentServer, ComponentRecv}
+
+// Breakdown splits the latency of an operation into components, in milliseconds
+type Breakdown struct {
+	Queue float64
+	Send  float64
+	Wait  float64
+	Recv  float64
+
+	// Server processing time, part of Wait, valid when ServerReported is set
+	Server         float64
+	ServerReported bool
+}
+
+// components returns the latency of every component present in the breakdown
+func (b *Breakdown) components() map[string]float64 {
+	components := map[string]float64{
+		ComponentQueue: b.Queue,
+		ComponentSend:  b.Send,
+		ComponentWait:  b.Wait,
+		ComponentRecv:  b.Recv,
+	}
+	if b.ServerReported {
+		components[ComponentServer] = b.Server
+	}
+	return components
+}
+
+// BreakdownStats are the per-method and aggregated statistics of a latency
+// component
+type BreakdownStats struct {
+	Component  string
+	Methods    []Stats // Methods with operations, sorted by name
+	Aggregated Stats   // All methods
+}
+
+// addBreakdownResult adds the components of a successful result to their
+// statistics; the caller holds c.mu
+func (c *Collector) addBreakdownResult(result *BenchmarkResult) {
+	if result.Breakdown == nil || result.Error != nil {
+		return
+	}
+	if c.breakdown == nil {
+		c.breakdown = make(map[string]map[string]*Metrics)
+	}
+	for component, ms := range result.Breakdown.components() {
+		group, exists := c.breakdown[component]
+		if !exists {
+			group = make(map[string]*Metrics)
+			c.breakdown[component] = group
+		}
+		metrics, exists := group[result.Method]
+		if !exists {
+			metrics = c.newMethodMetrics(result.Method)
+			group[result.Method] = metrics
+		}
+		metrics.AddResult(&BenchmarkResult{Method: result.Method, LatencyMs: ms, Timestamp: result.Timestamp})
+	}
+}
+
+// GetBreakdownStats returns the statistics of every latency component
+// measured during the run, in the order of BreakdownComponents
+func (c *Collector) GetBreakdownStats() []BreakdownStats {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	var stats []BreakdownStats
+	for _, component := range BreakdownComponents {
+		group, exists := c.breakdown[component]
+		if !exists {
+			continue
+		}
+		s := c.sinkStats(group, time.Time{}, time.Time{}, 0, c.final())
+		if len(s.Methods) > 0 {
+			stats = append(stats, BreakdownStats{Component: component, Methods: s.Methods, Aggregated: s.Aggregated})
+		}
+	}
+	return stats
+}
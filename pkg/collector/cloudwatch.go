@@ -0,0 +1,130 @@
+package collector
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"time"
+)
+
+// CloudWatchEMFExporter writes per-method statistics as CloudWatch Embedded
+// Metric Format (EMF) log lines every interval. The CloudWatch agent or Lambda
+// log pipeline turns these lines into CloudWatch metrics.
+type CloudWatchEMFExporter struct {
+	collector *Collector
+	namespace string
+	out       io.Writer
+	file      *os.File
+	pusher    *intervalPusher
+	last      map[string]Stats // Statistics at the previous push, for interval deltas
+}
+
+// NewCloudWatchEMFExporter creates an exporter writing EMF lines to path, or stdout if path is "-"
+func NewCloudWatchEMFExporter(c *Collector, path, namespace string, interval time.Duration) (*CloudWatchEMFExporter, error) {
+	e := &CloudWatchEMFExporter{
+		collector: c,
+		namespace: namespace,
+		out:       os.Stdout,
+		last:      make(map[string]Stats),
+	}
+
+	if path != "-" {
+		file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open EMF output: %w", err)
+		}
+		e.file = file
+		e.out = file
+	}
+
+	e.pusher = newIntervalPusher("CloudWatch EMF", interval, c.alignIntervals, e.push)
+	return e, nil
+}
+
+// Start starts the export loop
+func (e *CloudWatchEMFExporter) Start(ctx context.Context) {
+	e.pusher.start(ctx)
+}
+
+// Stop stops the export loop, writes the final interval and closes the output
+func (e *CloudWatchEMFExporter) Stop() {
+	e.pusher.stop()
+	if e.file != nil {
+		e.file.Close()
+	}
+}
+
+// emfMetric describes a metric in the EMF metadata block
+type emfMetric struct {
+	Name string `json:"Name"`
+	Unit string `json:"Unit"`
+}
+
+// emfMetrics lists the metrics emitted for every method
+var emfMetrics = []emfMetric{
+	{Name: "Operations", Unit: "Count"},
+	{Name: "Errors", Unit: "Count"},
+	{Name: "AvgLatency", Unit: "Milliseconds"},
+	{Name: "P50Latency", Unit: "Milliseconds"},
+	{Name: "P95Latency", Unit: "Milliseconds"},
+	{Name: "P99Latency", Unit: "Milliseconds"},
+	{Name: "MaxLatency", Unit: "Milliseconds"},
+}
+
+// push writes one EMF line per method. Counts are deltas since the previous
+// push so that CloudWatch's Sum statistic yields correct totals.
+func (e *CloudWatchEMFExporter) push(now time.Time) error {
+	stats := e.collector.GetStats()
+
+	methods := make([]string, 0, len(stats))
+	for method, stat := range stats {
+		if stat.Count > 0 {
+			methods = append(methods, method)
+		}
+	}
+	sort.Strings(methods)
+
+	for _, method := range methods {
+		stat := stats[method]
+		prev := e.last[method]
+		e.last[method] = stat
+
+		dimensions := []string{"Method"}
+		for _, label := range e.collector.Labels() {
+			dimensions = append(dimensions, label.Name)
+		}
+		line := map[string]interface{}{
+			"_aws": map[string]interface{}{
+				"Timestamp": now.UnixMilli(),
+				"CloudWatchMetrics": []map[string]interface{}{{
+					"Namespace":  e.namespace,
+					"Dimensions": [][]string{dimensions},
+					"Metrics":    emfMetrics,
+				}},
+			},
+			"Method":     method,
+			"Operations": stat.Count - prev.Count,
+			"Errors":     stat.ErrorCount - prev.ErrorCount,
+			"AvgLatency": stat.AvgLatency,
+			"P50Latency": stat.P50Latency,
+			"P95Latency": stat.P95Latency,
+			"P99Latency": stat.P99Latency,
+			"MaxLatency": stat.MaxLatency,
+		}
+		for _, label := range e.collector.Labels() {
+			line[label.Name] = label.Value
+		}
+
+		data, err := json.Marshal(line)
+		if err != nil {
+			return fmt.Errorf("failed to encode EMF line: %w", err)
+		}
+		if _, err := e.out.Write(append(data, '\n')); err != nil {
+			return fmt.Errorf("failed to write EMF line: %w", err)
+		}
+	}
+	return nil
+}
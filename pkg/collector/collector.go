@@ -2,13 +2,15 @@ package collector
 
 import (
 	"context"
-	"encoding/csv"
-	"fmt"
 	"log"
-	"os"
-	"sort"
 	"sync"
+	"sync/atomic"
 	"time"
+
+	apiv1 "kvstore-benchmarker/api/v1"
+	"kvstore-benchmarker/pkg/archive"
+	"kvstore-benchmarker/pkg/histstore"
+	"kvstore-benchmarker/pkg/latency"
 )
 
 // BenchmarkResult represents a single benchmark operation result
@@ -17,37 +19,79 @@ type BenchmarkResult struct {
 	LatencyMs float64
 	Error     error
 	Timestamp time.Time
+	Worker    int    // Worker ID, or -1 for results reported by an external agent
+	Agent     string // Agent ID for results reported by an external agent
+
+	// Key and value bytes sent in the request and received in the response
+	BytesSent     int64
+	BytesReceived int64
+
+	// Serialized request and response bytes including gRPC framing and
+	// response metadata, as seen by the gRPC layer
+	WireSent     int64
+	WireReceived int64
+
+	// Tag combination of the operation as sorted name=value pairs separated
+	// by commas, empty for untagged operations
+	Tags string
+
+	// Latency of the operation split into components, nil when not measured
+	Breakdown *Breakdown
+
+	// Retry delay the server suggested when it throttled the operation, 0 for none
+	RetryAfter time.Duration
+
+	// Warmup marks results of the warm-up phase, collected separately
+	Warmup bool
 }
 
 // Metrics holds aggregated metrics for a method
 type Metrics struct {
-	Method       string
-	Count        int64
-	ErrorCount   int64
-	TotalLatency float64
-	MinLatency   float64
-	MaxLatency   float64
-	Latencies    []float64 // For percentile calculations
-	mu           sync.RWMutex
-	maxLatencies int // Maximum number of latencies to store
+	Method        string
+	Count         int64
+	ErrorCount    int64
+	TotalLatency  float64
+	MinLatency    float64
+	MaxLatency    float64
+	BytesSent     int64            // Payload bytes of successful requests
+	BytesRecv     int64            // Payload bytes of successful responses
+	WireSent      int64            // Wire bytes of all requests
+	WireRecv      int64            // Wire bytes of all responses
+	ErrorCodes    map[string]int64 // Error counts by gRPC status code
+	SLOMet        int64            // Operations within the latency SLO
+	SLOViolations int64            // Operations slower than the latency SLO or failed
+	Overflow      int64            // Successful operations slower than the latency bound
+	StartTime     time.Time        // Timestamp of the first result
+	EndTime       time.Time        // Timestamp of the latest result
+	recorder      latencyRecorder  // Latency distribution for percentiles
+	errors        *errorLatency    // Latency distribution of failures, nil until the first one
+	throttling    Throttling       // Operations throttled by the server, not counted as errors
+	percentiles   []float64        // Percentiles reported in Stats.Percentiles
+	sloThreshold  float64          // Latency SLO in milliseconds, 0 for none
+	engine        string           // Percentile engine of the recorder
+	latencyMax    float64          // Latency bound in milliseconds
+	trimPct       float64          // Share trimmed from each end for the trimmed mean, in percent
+	mu            sync.RWMutex
 }
 
-// NewMetrics creates a new metrics instance
+// NewMetrics creates a new metrics instance recording the default percentiles with an HDR histogram
 func NewMetrics(method string) *Metrics {
-	return &Metrics{
-		Method:       method,
-		MinLatency:   float64(^uint(0) >> 1), // Max float64
-		MaxLatency:   0,
-		Latencies:    make([]float64, 0, 1000), // Pre-allocate for efficiency
-		maxLatencies: 10000,                    // Default limit
-	}
+	return newMetrics(method, EngineHDR, DefaultPercentiles, msOf(DefaultLatencyMax))
 }
 
-// SetMaxLatencies sets the maximum number of latencies to store for percentile calculation
-func (m *Metrics) SetMaxLatencies(max int) {
-	m.mu.Lock()
-	defer m.mu.Unlock()
-	m.maxLatencies = max
+// newMetrics creates a new metrics instance using the given percentile
+// engine and latency bound in milliseconds
+func newMetrics(method, engine string, percentiles []float64, latencyMax float64) *Metrics {
+	return &Metrics{
+		Method:      method,
+		MinLatency:  float64(^uint(0) >> 1), // Max float64
+		MaxLatency:  0,
+		recorder:    newLatencyRecorder(engine, latencyMax),
+		percentiles: percentiles,
+		engine:      engine,
+		latencyMax:  latencyMax,
+		trimPct:     DefaultTrimPct,
+	}
 }
 
 // AddResult adds a result to the metrics
@@ -56,19 +100,33 @@ func (m *Metrics) AddResult(result *BenchmarkResult) {
 	defer m.mu.Unlock()
 
 	m.Count++
+	m.countSLO(result)
+	if m.StartTime.IsZero() || result.Timestamp.Before(m.StartTime) {
+		m.StartTime = result.Timestamp
+	}
+	if result.Timestamp.After(m.EndTime) {
+		m.EndTime = result.Timestamp
+	}
+	m.WireSent += result.WireSent
+	m.WireRecv += result.WireReceived
+	if IsThrottled(result.Error) {
+		m.recordThrottle(msOf(result.RetryAfter))
+		return
+	}
 	if result.Error != nil {
 		m.ErrorCount++
+		if m.ErrorCodes == nil {
+			m.ErrorCodes = make(map[string]int64)
+		}
+		m.ErrorCodes[ErrorCode(result.Error)]++
+		m.recordError(result.LatencyMs)
 		return
 	}
 
 	m.TotalLatency += result.LatencyMs
-	m.Latencies = append(m.Latencies, result.LatencyMs)
-
-	// Limit the number of stored latencies to prevent memory issues
-	if len(m.Latencies) > m.maxLatencies {
-		// Keep only the most recent latencies
-		m.Latencies = m.Latencies[len(m.Latencies)-m.maxLatencies:]
-	}
+	m.BytesSent += result.BytesSent
+	m.BytesRecv += result.BytesReceived
+	m.record(result.LatencyMs)
 
 	if result.LatencyMs < m.MinLatency {
 		m.MinLatency = result.LatencyMs
@@ -80,47 +138,80 @@ func (m *Metrics) AddResult(result *BenchmarkResult) {
 
 // GetStats returns computed statistics
 func (m *Metrics) GetStats() Stats {
-	m.mu.RLock()
-	defer m.mu.RUnlock()
+	return m.getStats(true)
+}
 
+// getStats returns the statistics, with the trimmed mean and MAD if robust
+func (m *Metrics) getStats(robust bool) Stats {
+	// Percentile queries may compact the recorder, so take the write lock
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.stats(robust)
+}
+
+// stats computes the statistics, with the trimmed mean and MAD if robust;
+// the caller holds m.mu
+func (m *Metrics) stats(robust bool) Stats {
 	if m.Count == 0 {
 		return Stats{}
 	}
 
-	successCount := m.Count - m.ErrorCount
+	successCount := m.Count - m.ErrorCount - m.throttling.Count
 	if successCount == 0 {
-		return Stats{
+		stats := Stats{
 			Method:     m.Method,
 			Count:      m.Count,
 			ErrorCount: m.ErrorCount,
-			ErrorRate:  100.0,
+			ErrorRate:  float64(m.ErrorCount) / float64(m.Count) * 100.0,
+			ErrorCodes: mergeErrorCodes(nil, m.ErrorCodes),
+			WireSent:   m.WireSent,
+			WireRecv:   m.WireRecv,
+
+			SLOThreshold:  m.sloThreshold,
+			SLOViolations: m.SLOViolations,
 		}
+		m.errors.fill(&stats, m.ErrorCount)
+		m.throttling.fill(&stats)
+		return stats
 	}
 
 	avgLatency := m.TotalLatency / float64(successCount)
 	errorRate := float64(m.ErrorCount) / float64(m.Count) * 100.0
+	var trimmedMean, mad float64
+	if robust {
+		trimmedMean, mad = robustStats(m.recorder, m.trimPct)
+	}
 
-	// Calculate percentiles
-	sortedLatencies := make([]float64, len(m.Latencies))
-	copy(sortedLatencies, m.Latencies)
-	sort.Float64s(sortedLatencies)
-
-	p50 := percentile(sortedLatencies, 50)
-	p95 := percentile(sortedLatencies, 95)
-	p99 := percentile(sortedLatencies, 99)
-
-	return Stats{
-		Method:     m.Method,
-		Count:      m.Count,
-		ErrorCount: m.ErrorCount,
-		ErrorRate:  errorRate,
-		AvgLatency: avgLatency,
-		MinLatency: m.MinLatency,
-		MaxLatency: m.MaxLatency,
-		P50Latency: p50,
-		P95Latency: p95,
-		P99Latency: p99,
+	stats := Stats{
+		Method:      m.Method,
+		Count:       m.Count,
+		ErrorCount:  m.ErrorCount,
+		ErrorRate:   errorRate,
+		AvgLatency:  avgLatency,
+		TrimmedMean: trimmedMean,
+		MAD:         mad,
+		MinLatency:  m.MinLatency,
+		MaxLatency:  m.MaxLatency,
+		P50Latency:  m.recorder.Percentile(50),
+		P95Latency:  m.recorder.Percentile(95),
+		P99Latency:  m.recorder.Percentile(99),
+		P999Latency: m.recorder.Percentile(99.9),
+		Percentiles: percentilesOf(m.recorder, m.percentiles),
+		BytesSent:   m.BytesSent,
+		BytesRecv:   m.BytesRecv,
+		WireSent:    m.WireSent,
+		WireRecv:    m.WireRecv,
+		Overflow:    m.Overflow,
+		ErrorCodes:  mergeErrorCodes(nil, m.ErrorCodes),
+
+		SLOThreshold:  m.sloThreshold,
+		SLOMet:        m.SLOMet,
+		SLOViolations: m.SLOViolations,
 	}
+	m.errors.fill(&stats, m.ErrorCount)
+	m.throttling.fill(&stats)
+	return stats
 }
 
 // Stats represents computed statistics
@@ -130,94 +221,454 @@ type Stats struct {
 	ErrorCount   int64
 	ErrorRate    float64
 	AvgLatency   float64
+	TrimmedMean  float64 // Mean latency without the collector's trimmed share of operations at each end
+	MAD          float64 // Median absolute deviation of latencies
 	MinLatency   float64
 	MaxLatency   float64
 	P50Latency   float64
 	P95Latency   float64
 	P99Latency   float64
+	P999Latency  float64
 	TotalLatency float64
+	Dropped      int64            // Results dropped because the results channel was full, set for sinks
+	Percentiles  []float64        // Latencies at the collector's configured percentiles, nil without successes
+	BytesSent    int64            // Payload bytes of successful requests
+	BytesRecv    int64            // Payload bytes of successful responses
+	WireSent     int64            // Wire bytes of all requests, 0 when not measured
+	WireRecv     int64            // Wire bytes of all responses, 0 when not measured
+	Overflow     int64            // Successful operations slower than the latency bound, recorded at the bound
+	ErrorCodes   map[string]int64 // Error counts by gRPC status code, nil without errors
+
+	// Latencies of failed operations, 0 without errors
+	ErrorAvgLatency float64
+	ErrorP50Latency float64
+	ErrorP99Latency float64
+	ErrorMaxLatency float64
+
+	// Operations throttled by the server, not part of Count's errors or
+	// successes, and the retry delays in milliseconds it suggested for the
+	// RetryAfterHinted of them that carried one
+	Throttled        int64
+	RetryAfterHinted int64
+	RetryAfterAvg    float64
+	RetryAfterMax    float64
+
+	// Latency SLO conformance; the threshold is 0 for none and for aggregates
+	SLOThreshold  float64
+	SLOMet        int64
+	SLOViolations int64
+}
+
+// Percentile returns the latency at the i-th configured percentile, 0 without successes
+func (s Stats) Percentile(i int) float64 {
+	if i >= len(s.Percentiles) {
+		return 0
+	}
+	return s.Percentiles[i]
+}
+
+// WirePerOp returns the average wire bytes sent and received per operation
+func (s Stats) WirePerOp() (sent, received float64) {
+	if s.Count == 0 {
+		return 0, 0
+	}
+	return float64(s.WireSent) / float64(s.Count), float64(s.WireRecv) / float64(s.Count)
+}
+
+// bytesPerMB is the size of the megabyte used for bandwidth, as in disk and
+// network specifications
+const bytesPerMB = 1e6
+
+// MBPerSec converts a number of bytes transferred in the given number of
+// seconds into MB/s
+func MBPerSec(bytes int64, seconds float64) float64 {
+	if seconds <= 0 {
+		return 0
+	}
+	return float64(bytes) / bytesPerMB / seconds
 }
 
 // Collector manages result collection and reporting
 type Collector struct {
-	metrics   map[string]*Metrics
-	results   chan *BenchmarkResult
-	done      chan struct{}
-	csvWriter *csv.Writer
-	csvFile   *os.File
-	mu        sync.RWMutex
+	metrics       map[string]*Metrics
+	results       chan *BenchmarkResult
+	done          chan struct{}
+	stopped       sync.WaitGroup
+	block         bool
+	dropped       atomic.Int64
+	droppedMu     sync.Mutex
+	droppedBy     map[string]int64 // Dropped measured results by method
+	csvFormat     CSVFormat
+	engine        string
+	latencyMax    float64 // Latency bound in milliseconds
+	trimPct       float64 // Share trimmed from each end for the trimmed mean, in percent
+	pcts          []float64
+	labels        []Label
+	sloThresholds map[string]float64
+	archive       *archive.Writer
+	rawLog        *RawLog
+	rawParquet    *ParquetRawLog
+	statsd        *StatsDSink
+	hlog          *HistogramLog
+	heatmap       *Heatmap
+	curve         *CurveLog
+	histStore     *histstore.Writer
+	unit          latency.Unit
+	mu            sync.RWMutex
+
+	// Report intervals and exporter pushes fall on wall-clock multiples of the interval
+	alignIntervals bool
+
+	// Sinks receiving the interval and final statistics, the results CSV among them
+	sinks []Sink
+
+	// Statistics of named phases of the run
+	phases []*phaseMetrics
+
+	// Statistics by tag combination and method, nil when operations are untagged
+	tagged map[string]map[string]*Metrics
+
+	// Statistics by latency component and method, nil until a result with a
+	// breakdown arrives
+	breakdown map[string]map[string]*Metrics
+
+	// Statistics of the warm-up phase, when collected
+	warmup map[string]*Metrics
+
+	// Start of the run on the monotonic clock, that offsets in outputs are
+	// relative to so they are unaffected by wall-clock adjustments
+	origin time.Time
+
+	// Measured window of the run, and the measured time of the sessions
+	// before a resume
+	runStart      time.Time
+	runEnd        time.Time
+	priorMeasured time.Duration
+
+	// Total paused time of the run, and its value when the measured window
+	// began and ended, so that pauses within the window are not measured
+	paused      func() time.Duration
+	startPaused time.Duration
+	endPaused   time.Duration
+
+	// Statistics since the last SnapshotAndReset
+	window      map[string]*Metrics
+	windowStart time.Time
+
+	// Statistics of the current report interval
+	interval        map[string]*Metrics
+	intervalTagged  map[string]map[string]*Metrics
+	intervalDropped map[string]int64 // Dropped counts by method at the interval start
+	intervalStart   time.Time
+}
+
+// Options configures a collector
+type Options struct {
+	CSVPath       string       // CSV output file, "-" for stdout
+	BufferSize    int          // Capacity of the results channel
+	BlockWhenFull bool         // Block producers instead of dropping results when the channel is full
+	LatencyUnit   latency.Unit // Unit of latency columns in the CSV output
+	CSVFormat     CSVFormat    // CSV rendering, the zero value selects the defaults
+	Engine        string       // Percentile engine, EngineHDR (default), EngineTDigest, EngineExact, EngineKLL or EngineReservoir
+	CSVIntervals  bool         // Write one CSV row per method per interval instead of a final summary
+	Percentiles   []float64    // Reported percentiles, DefaultPercentiles if empty
+	Tagged        bool         // Operations carry tags; statistics are also grouped by tag combination
+	Labels        []Label      // Run labels attached to every CSV row and exported metric, sorted by name
+	CSVComments   []string     // Lines written as "# " comments before the CSV header
+	CSVAppend     bool         // Add the rows to an existing CSV with the same header instead of truncating it
+	CSVRunID      string       // Written in a run_id column after offset_s unless empty
+
+	// Align report intervals and exporter pushes to wall-clock multiples of the interval
+	AlignIntervals bool
+
+	// Latency thresholds in milliseconds by method, AllMethods for the rest,
+	// that operations are counted against
+	LatencySLOs map[string]float64
+
+	// Highest latency recorded as measured, DefaultLatencyMax if 0; slower
+	// operations are counted as overflow and recorded at the bound
+	LatencyMax time.Duration
+
+	// Share of operations trimmed from each end for the trimmed mean, in
+	// percent below 50
+	TrimPct float64
+
+	// Total time the run has been paused so far, nil if it cannot be paused
+	Paused func() time.Duration
 }
 
 // NewCollector creates a new collector
-func NewCollector(csvPath string) (*Collector, error) {
-	var csvFile *os.File
-	var csvWriter *csv.Writer
+func NewCollector(opts Options) (*Collector, error) {
+	if opts.BufferSize <= 0 {
+		opts.BufferSize = 10000
+	}
+	unit := opts.LatencyUnit
+	if unit == "" {
+		unit = latency.Millis
+	}
+	if opts.CSVFormat == (CSVFormat{}) {
+		opts.CSVFormat = CSVFormat{Delimiter: ',', Precision: -1}
+	}
+	if opts.Engine == "" {
+		opts.Engine = EngineHDR
+	}
+	if opts.CSVFormat.Delimiter == 0 {
+		opts.CSVFormat.Delimiter = ','
+	}
+	if len(opts.Percentiles) == 0 {
+		opts.Percentiles = DefaultPercentiles
+	}
+	if opts.LatencyMax <= 0 {
+		opts.LatencyMax = DefaultLatencyMax
+	}
+
+	var tagged map[string]map[string]*Metrics
+	if opts.Tagged {
+		tagged = make(map[string]map[string]*Metrics)
+	}
 
-	if csvPath != "" {
-		var err error
-		csvFile, err = os.Create(csvPath)
+	var sinks []Sink
+	if opts.CSVPath != "" {
+		csv, err := newCSVSink(opts.CSVPath, opts, unit)
 		if err != nil {
-			return nil, fmt.Errorf("failed to create CSV file: %w", err)
+			return nil, err
 		}
-
-		csvWriter = csv.NewWriter(csvFile)
-		// Write CSV header for aggregated metrics
-		csvWriter.Write([]string{
-			"timestamp",
-			"method",
-			"total_ops",
-			"success_ops",
-			"error_ops",
-			"error_rate_pct",
-			"avg_latency_ms",
-			"p50_latency_ms",
-			"p95_latency_ms",
-			"p99_latency_ms",
-			"min_latency_ms",
-			"max_latency_ms",
-			"throughput_ops_per_sec",
-		})
+		sinks = append(sinks, csv)
 	}
 
 	return &Collector{
 		metrics:   make(map[string]*Metrics),
-		results:   make(chan *BenchmarkResult, 10000), // Buffered channel
+		results:   make(chan *BenchmarkResult, opts.BufferSize),
 		done:      make(chan struct{}),
-		csvWriter: csvWriter,
-		csvFile:   csvFile,
+		block:     opts.BlockWhenFull,
+		droppedBy: make(map[string]int64),
+		csvFormat: opts.CSVFormat,
+		engine:    opts.Engine,
+		pcts:      opts.Percentiles,
+		labels:    opts.Labels,
+
+		alignIntervals: opts.AlignIntervals,
+
+		latencyMax: msOf(opts.LatencyMax),
+		trimPct:    opts.TrimPct,
+		paused:     opts.Paused,
+
+		sloThresholds: opts.LatencySLOs,
+
+		window:      make(map[string]*Metrics),
+		windowStart: time.Now(),
+		warmup:      make(map[string]*Metrics),
+		tagged:      tagged,
+		unit:        unit,
+
+		sinks: sinks,
 	}, nil
 }
 
+// Percentiles returns the reported percentiles
+func (c *Collector) Percentiles() []float64 {
+	return c.pcts
+}
+
+// SetArchive attaches a binary archive that receives every result and the final summary
+func (c *Collector) SetArchive(w *archive.Writer) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.archive = w
+}
+
+// SetRawLog attaches a raw log that receives every result
+func (c *Collector) SetRawLog(l *RawLog) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.rawLog = l
+}
+
+// SetParquetRawLog attaches a Parquet raw log that receives every result
+func (c *Collector) SetParquetRawLog(l *ParquetRawLog) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.rawParquet = l
+}
+
+// SetStatsD attaches a StatsD sink that receives every result
+func (c *Collector) SetStatsD(s *StatsDSink) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.statsd = s
+}
+
+// SetHistogramStore attaches an append-only histogram store that receives
+// every interval histogram
+func (c *Collector) SetHistogramStore(w *histstore.Writer) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.histStore = w
+}
+
+// SetHistogramLog attaches an HdrHistogram log that receives every interval histogram
+func (c *Collector) SetHistogramLog(l *HistogramLog) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.hlog = l
+}
+
+// SetHeatmap attaches a latency heatmap that receives the bucket counts of every interval
+func (c *Collector) SetHeatmap(h *Heatmap) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.heatmap = h
+}
+
+// SetCurveLog attaches a throughput/latency log that receives a row every interval
+func (c *Collector) SetCurveLog(l *CurveLog) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.curve = l
+}
+
 // Start starts the collector goroutine
 func (c *Collector) Start(ctx context.Context) {
+	c.mu.Lock()
+	c.origin = time.Now()
+	c.mu.Unlock()
+
+	c.stopped.Add(1)
 	go c.run(ctx)
 }
 
-// Stop stops the collector and writes final aggregated metrics to CSV
+// Stop stops the collector, processes results still queued and hands the
+// final statistics to the sinks before closing them
 func (c *Collector) Stop() {
 	close(c.done)
+	c.stopped.Wait()
+	c.Flush()
 
-	// Write final aggregated metrics to CSV
-	c.WriteAggregatedMetricsToCSV()
+	c.finishSinks()
 
-	if c.csvFile != nil {
-		c.csvWriter.Flush()
-		c.csvFile.Close()
+	// Write the final summary to the archive
+	if c.archive != nil {
+		c.writeArchiveSummary()
+		if err := c.archive.Close(); err != nil {
+			log.Printf("Warning: failed to close archive: %v", err)
+		}
+	}
+
+	if c.rawLog != nil {
+		if err := c.rawLog.Close(); err != nil {
+			log.Printf("Warning: %v", err)
+		}
+	}
+
+	if c.rawParquet != nil {
+		if err := c.rawParquet.Close(); err != nil {
+			log.Printf("Warning: %v", err)
+		}
+	}
+
+	if c.hlog != nil {
+		if err := c.hlog.Close(); err != nil {
+			log.Printf("Warning: %v", err)
+		}
+	}
+
+	if c.histStore != nil {
+		if err := c.histStore.Close(); err != nil {
+			log.Printf("Warning: %v", err)
+		}
+	}
+
+	if c.heatmap != nil {
+		if err := c.heatmap.Close(); err != nil {
+			log.Printf("Warning: %v", err)
+		}
+	}
+
+	if c.curve != nil {
+		if err := c.curve.Close(); err != nil {
+			log.Printf("Warning: %v", err)
+		}
+	}
+
+	if c.statsd != nil {
+		if err := c.statsd.Close(); err != nil {
+			log.Printf("Warning: failed to close StatsD sink: %v", err)
+		}
+	}
+}
+
+// Close closes the sinks and archive of a collector that was never started,
+// without writing final statistics
+func (c *Collector) Close() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, s := range c.sinks {
+		if err := s.Close(); err != nil {
+			log.Printf("Warning: %v", err)
+		}
+	}
+	if c.archive != nil {
+		if err := c.archive.Close(); err != nil {
+			log.Printf("Warning: failed to close archive: %v", err)
+		}
 	}
 }
 
-// AddResult adds a result to the collector
+// AddResult adds a result to the collector. When the results channel is full
+// the result is dropped, or in blocking mode the caller waits for space.
 func (c *Collector) AddResult(result *BenchmarkResult) {
+	if c.block {
+		select {
+		case c.results <- result:
+		case <-c.done:
+			c.drop(result)
+		}
+		return
+	}
+
 	select {
 	case c.results <- result:
 	default:
-		// Channel is full, log warning
-		log.Printf("Warning: results channel is full, dropping result")
+		c.drop(result)
+	}
+}
+
+// drop counts a dropped result, in total and by method for measured results,
+// warning once
+func (c *Collector) drop(result *BenchmarkResult) {
+	if c.dropped.Add(1) == 1 {
+		log.Printf("Warning: results channel is full, dropping results (consider --results-buffer or --results-block)")
+	}
+	if !result.Warmup {
+		c.droppedMu.Lock()
+		c.droppedBy[result.Method]++
+		c.droppedMu.Unlock()
+	}
+}
+
+// Dropped returns the number of results dropped because the results channel
+// was full, including warm-up results
+func (c *Collector) Dropped() int64 {
+	return c.dropped.Load()
+}
+
+// Flush processes results still queued in the results channel
+func (c *Collector) Flush() {
+	for {
+		select {
+		case result := <-c.results:
+			c.processResult(result)
+		default:
+			return
+		}
 	}
 }
 
 // run is the main collector loop
 func (c *Collector) run(ctx context.Context) {
+	defer c.stopped.Done()
+
 	for {
 		select {
 		case result := <-c.results:
@@ -235,15 +686,48 @@ func (c *Collector) processResult(result *BenchmarkResult) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
+	if result.Warmup {
+		c.addWarmupResult(result)
+		return
+	}
+
 	// Get or create metrics for this method
 	metrics, exists := c.metrics[result.Method]
 	if !exists {
-		metrics = NewMetrics(result.Method)
+		metrics = c.newMethodMetrics(result.Method)
 		c.metrics[result.Method] = metrics
 	}
 
 	// Add to metrics
 	metrics.AddResult(result)
+	c.addIntervalResult(result)
+	c.addWindowResult(result)
+	c.addPhaseResult(result)
+	c.addTaggedResult(c.tagged, result)
+	c.addBreakdownResult(result)
+
+	if c.archive != nil {
+		latency := time.Duration(result.LatencyMs * float64(time.Millisecond))
+		if err := c.archive.WriteOp(result.Method, result.Timestamp, latency, result.Error); err != nil {
+			log.Printf("Warning: %v", err)
+		}
+	}
+
+	if c.rawLog != nil {
+		if err := c.rawLog.Write(result, result.Timestamp.Sub(c.origin)); err != nil {
+			log.Printf("Warning: %v", err)
+		}
+	}
+
+	if c.rawParquet != nil {
+		if err := c.rawParquet.Write(result, result.Timestamp.Sub(c.origin)); err != nil {
+			log.Printf("Warning: %v", err)
+		}
+	}
+
+	if c.statsd != nil {
+		c.statsd.Write(result)
+	}
 
 	// Note: We don't write individual operations to CSV anymore
 	// CSV will be written with aggregated metrics at the end
@@ -254,19 +738,52 @@ func (c *Collector) GetAggregatedStats() Stats {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
 
-	var allLatencies []float64
+	return c.aggregateMetrics(c.metrics, c.final())
+}
+
+// aggregateMetrics combines the metrics of several methods into a single
+// AGGREGATED entry recorded with the collector's engine and bound, with the
+// trimmed mean and MAD if robust
+func (c *Collector) aggregateMetrics(metricsByMethod map[string]*Metrics, robust bool) Stats {
+	all := newLatencyRecorder(c.engine, c.latencyMax)
+	errors := c.newErrorLatency()
+	var throttling Throttling
 	var totalCount int64
 	var totalErrorCount int64
 	var totalLatency float64
-
-	// Collect all latencies and basic stats
-	for _, metrics := range c.metrics {
-		metrics.mu.RLock()
-		allLatencies = append(allLatencies, metrics.Latencies...)
+	var bytesSent, bytesRecv int64
+	var wireSent, wireRecv int64
+	var overflow int64
+	var sloMet, sloViolations int64
+	var minLatency, maxLatency float64
+	var errorCodes map[string]int64
+
+	// Merge all latency distributions and basic stats
+	for _, metrics := range metricsByMethod {
+		metrics.mu.Lock()
+		all.Merge(metrics.recorder)
+		errors.merge(metrics.errors)
+		throttling.merge(metrics.throttling)
+		errorCodes = mergeErrorCodes(errorCodes, metrics.ErrorCodes)
 		totalCount += metrics.Count
 		totalErrorCount += metrics.ErrorCount
 		totalLatency += metrics.TotalLatency
-		metrics.mu.RUnlock()
+		bytesSent += metrics.BytesSent
+		bytesRecv += metrics.BytesRecv
+		wireSent += metrics.WireSent
+		wireRecv += metrics.WireRecv
+		overflow += metrics.Overflow
+		sloMet += metrics.SLOMet
+		sloViolations += metrics.SLOViolations
+		if metrics.Count > metrics.ErrorCount+metrics.throttling.Count {
+			if minLatency == 0 || metrics.MinLatency < minLatency {
+				minLatency = metrics.MinLatency
+			}
+			if metrics.MaxLatency > maxLatency {
+				maxLatency = metrics.MaxLatency
+			}
+		}
+		metrics.mu.Unlock()
 	}
 
 	if totalCount == 0 {
@@ -274,34 +791,46 @@ func (c *Collector) GetAggregatedStats() Stats {
 	}
 
 	// Calculate aggregated statistics
-	successCount := totalCount - totalErrorCount
+	successCount := totalCount - totalErrorCount - throttling.Count
 	errorRate := float64(totalErrorCount) / float64(totalCount) * 100.0
-	avgLatency := totalLatency / float64(successCount)
-
-	var minLatency, maxLatency, p50, p95, p99 float64
-
-	if len(allLatencies) > 0 {
-		sort.Float64s(allLatencies)
-		minLatency = allLatencies[0]
-		maxLatency = allLatencies[len(allLatencies)-1]
-		p50 = percentile(allLatencies, 50)
-		p95 = percentile(allLatencies, 95)
-		p99 = percentile(allLatencies, 99)
+	var avgLatency float64
+	if successCount > 0 {
+		avgLatency = totalLatency / float64(successCount)
+	}
+	var trimmedMean, mad float64
+	if robust {
+		trimmedMean, mad = robustStats(all, c.trimPct)
 	}
 
-	return Stats{
+	stats := Stats{
 		Method:       "AGGREGATED",
 		Count:        totalCount,
 		ErrorCount:   totalErrorCount,
 		ErrorRate:    errorRate,
 		AvgLatency:   avgLatency,
+		TrimmedMean:  trimmedMean,
+		MAD:          mad,
 		MinLatency:   minLatency,
 		MaxLatency:   maxLatency,
-		P50Latency:   p50,
-		P95Latency:   p95,
-		P99Latency:   p99,
+		P50Latency:   all.Percentile(50),
+		P95Latency:   all.Percentile(95),
+		P99Latency:   all.Percentile(99),
+		P999Latency:  all.Percentile(99.9),
 		TotalLatency: totalLatency,
+		Percentiles:  percentilesOf(all, c.pcts),
+		BytesSent:    bytesSent,
+		BytesRecv:    bytesRecv,
+		WireSent:     wireSent,
+		WireRecv:     wireRecv,
+		Overflow:     overflow,
+		ErrorCodes:   errorCodes,
+
+		SLOMet:        sloMet,
+		SLOViolations: sloViolations,
 	}
+	errors.fill(&stats, totalErrorCount)
+	throttling.fill(&stats)
+	return stats
 }
 
 // GetStats returns statistics for all methods
@@ -311,7 +840,7 @@ func (c *Collector) GetStats() map[string]Stats {
 
 	stats := make(map[string]Stats)
 	for method, metrics := range c.metrics {
-		stats[method] = metrics.GetStats()
+		stats[method] = metrics.getStats(c.final())
 	}
 	return stats
 }
@@ -323,132 +852,144 @@ func (c *Collector) GetTotalStats() Stats {
 	var total Stats
 	total.Method = "TOTAL"
 
-	// Collect all latencies from all methods for proper percentile calculation
-	var allLatencies []float64
+	// Merge the latency distributions from all methods for proper percentile calculation
+	all := newLatencyRecorder(c.engine, c.latencyMax)
+	errors := c.newErrorLatency()
+	var throttling Throttling
 	var totalSuccessCount int64
 
 	for _, stat := range stats {
 		total.Count += stat.Count
 		total.ErrorCount += stat.ErrorCount
-		total.TotalLatency += stat.AvgLatency * float64(stat.Count-stat.ErrorCount)
-		totalSuccessCount += stat.Count - stat.ErrorCount
+		total.ErrorCodes = mergeErrorCodes(total.ErrorCodes, stat.ErrorCodes)
+		total.BytesSent += stat.BytesSent
+		total.BytesRecv += stat.BytesRecv
+		total.WireSent += stat.WireSent
+		total.WireRecv += stat.WireRecv
+		total.Overflow += stat.Overflow
+		total.SLOMet += stat.SLOMet
+		total.SLOViolations += stat.SLOViolations
+		total.TotalLatency += stat.AvgLatency * float64(stat.Successes())
+		totalSuccessCount += stat.Successes()
+
+		if stat.Successes() > 0 {
+			if total.MinLatency == 0 || stat.MinLatency < total.MinLatency {
+				total.MinLatency = stat.MinLatency
+			}
+			if stat.MaxLatency > total.MaxLatency {
+				total.MaxLatency = stat.MaxLatency
+			}
+		}
 
-		// Get the actual latencies from the metrics for proper percentile calculation
 		c.mu.RLock()
 		if metrics, exists := c.metrics[stat.Method]; exists {
-			metrics.mu.RLock()
-			allLatencies = append(allLatencies, metrics.Latencies...)
-			metrics.mu.RUnlock()
+			metrics.mu.Lock()
+			all.Merge(metrics.recorder)
+			errors.merge(metrics.errors)
+			throttling.merge(metrics.throttling)
+			metrics.mu.Unlock()
 		}
 		c.mu.RUnlock()
 	}
 
 	if total.Count > 0 {
 		total.ErrorRate = float64(total.ErrorCount) / float64(total.Count) * 100.0
-		total.AvgLatency = total.TotalLatency / float64(totalSuccessCount)
-
-		// Calculate percentiles from all latencies combined
-		if len(allLatencies) > 0 {
-			sort.Float64s(allLatencies)
-			total.MinLatency = allLatencies[0]
-			total.MaxLatency = allLatencies[len(allLatencies)-1]
-			total.P50Latency = percentile(allLatencies, 50)
-			total.P95Latency = percentile(allLatencies, 95)
-			total.P99Latency = percentile(allLatencies, 99)
+		if totalSuccessCount > 0 {
+			total.AvgLatency = total.TotalLatency / float64(totalSuccessCount)
 		}
+
+		// Calculate percentiles from the combined distribution
+		total.P50Latency = all.Percentile(50)
+		total.P95Latency = all.Percentile(95)
+		total.P99Latency = all.Percentile(99)
+		total.P999Latency = all.Percentile(99.9)
+		total.Percentiles = percentilesOf(all, c.pcts)
+		c.mu.RLock()
+		final := c.final()
+		c.mu.RUnlock()
+		if final {
+			total.TrimmedMean, total.MAD = robustStats(all, c.trimPct)
+		}
+		errors.fill(&total, total.ErrorCount)
+		throttling.fill(&total)
 	}
 
 	return total
 }
 
-// percentile calculates the nth percentile from sorted values
-func percentile(values []float64, n int) float64 {
-	if len(values) == 0 {
-		return 0
-	}
-
-	// Calculate the index for the nth percentile
-	index := float64(n) / 100.0 * float64(len(values)-1)
+// EndRun marks the end of the measured window begun by StartIntervals
+func (c *Collector) EndRun(now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.runEnd = now
+	c.endPaused = c.pausedTotal()
+}
 
-	// Handle integer index
-	if index == float64(int(index)) {
-		return values[int(index)]
-	}
+// final reports whether EndRun has ended the run, after which the statistics
+// of the run carry the trimmed mean and MAD; while the run is live, progress,
+// dashboards and exporters skip their hundreds of percentile queries. The
+// caller holds c.mu.
+func (c *Collector) final() bool {
+	return !c.runEnd.IsZero()
+}
 
-	// Handle fractional index (interpolate between two values)
-	lowerIndex := int(index)
-	upperIndex := lowerIndex + 1
+// Measured returns the measured time of the run, from StartIntervals to
+// EndRun or now, that throughput is computed over
+func (c *Collector) Measured() time.Duration {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.measured(time.Now())
+}
 
-	if upperIndex >= len(values) {
-		return values[lowerIndex]
+// measured returns the measured time of the run up to now, excluding pauses
+// and including the sessions before a resume, or 0 if no window was marked;
+// the caller holds c.mu
+func (c *Collector) measured(now time.Time) time.Duration {
+	if c.runStart.IsZero() {
+		return 0
 	}
-
-	// Linear interpolation
-	fraction := index - float64(lowerIndex)
-	return values[lowerIndex] + fraction*(values[upperIndex]-values[lowerIndex])
+	end, paused := c.runEnd, c.endPaused
+	if end.IsZero() {
+		end, paused = now, c.pausedTotal()
+	}
+	return c.priorMeasured + end.Sub(c.runStart) - (paused - c.startPaused)
 }
 
-// WriteAggregatedMetricsToCSV writes aggregated metrics for all methods to CSV
-func (c *Collector) WriteAggregatedMetricsToCSV() {
-	var throughput float64
-
-	if c.csvWriter == nil {
-		return
+// pausedTotal returns the total paused time of the run
+func (c *Collector) pausedTotal() time.Duration {
+	if c.paused == nil {
+		return 0
 	}
+	return c.paused()
+}
 
-	c.mu.RLock()
-	defer c.mu.RUnlock()
-
-	timestamp := time.Now().Format(time.RFC3339Nano)
-
-	// Write per-method aggregated metrics
-	for _, metrics := range c.metrics {
-		stats := metrics.GetStats()
+// writeArchiveSummary writes per-method and aggregated statistics to the archive
+func (c *Collector) writeArchiveSummary() {
+	var methods []*apiv1.MethodStats
+	for _, stats := range c.GetStats() {
 		if stats.Count == 0 {
 			continue
 		}
-		elapsedTime := time.Since(metrics.StartTime).Seconds()
-		if elapsedTime > 0 {
-			throughput = float64(stats.Count-stats.ErrorCount) / elapsedTime
-		} else {
-			throughput = 0.0
-		}
-		c.csvWriter.Write([]string{
-			timestamp,
-			stats.Method,
-			fmt.Sprintf("%d", stats.Count),
-			fmt.Sprintf("%d", stats.Count-stats.ErrorCount),
-			fmt.Sprintf("%d", stats.ErrorCount),
-			fmt.Sprintf("%.2f", stats.ErrorRate),
-			fmt.Sprintf("%.3f", stats.AvgLatency),
-			fmt.Sprintf("%.3f", stats.P50Latency),
-			fmt.Sprintf("%.3f", stats.P95Latency),
-			fmt.Sprintf("%.3f", stats.P99Latency),
-			fmt.Sprintf("%.3f", stats.MinLatency),
-			fmt.Sprintf("%.3f", stats.MaxLatency),
-			fmt.Sprintf("%.0f", throughput),
-		})
-	}
-
-	// Write overall aggregated metrics
-	aggregated := c.GetAggregatedStats()
-	if aggregated.Count > 0 {
-		throughput := float64(aggregated.Count - aggregated.ErrorCount) // ops per second
-
-		c.csvWriter.Write([]string{
-			timestamp,
-			"AGGREGATED",
-			fmt.Sprintf("%d", aggregated.Count),
-			fmt.Sprintf("%d", aggregated.Count-aggregated.ErrorCount),
-			fmt.Sprintf("%d", aggregated.ErrorCount),
-			fmt.Sprintf("%.2f", aggregated.ErrorRate),
-			fmt.Sprintf("%.3f", aggregated.AvgLatency),
-			fmt.Sprintf("%.3f", aggregated.P50Latency),
-			fmt.Sprintf("%.3f", aggregated.P95Latency),
-			fmt.Sprintf("%.3f", aggregated.P99Latency),
-			fmt.Sprintf("%.3f", aggregated.MinLatency),
-			fmt.Sprintf("%.3f", aggregated.MaxLatency),
-			fmt.Sprintf("%.0f", throughput),
-		})
+		methods = append(methods, statsToProto(stats))
+	}
+
+	aggregated := statsToProto(c.GetAggregatedStats())
+	if err := c.archive.WriteSummary(time.Now(), methods, aggregated); err != nil {
+		log.Printf("Warning: %v", err)
+	}
+}
+
+// statsToProto converts statistics into their compact archive representation
+func statsToProto(stats Stats) *apiv1.MethodStats {
+	return &apiv1.MethodStats{
+		Method:       stats.Method,
+		Count:        stats.Count,
+		ErrorCount:   stats.ErrorCount,
+		AvgLatencyMs: stats.AvgLatency,
+		MinLatencyMs: stats.MinLatency,
+		MaxLatencyMs: stats.MaxLatency,
+		P50LatencyMs: stats.P50Latency,
+		P95LatencyMs: stats.P95Latency,
+		P99LatencyMs: stats.P99Latency,
 	}
 }
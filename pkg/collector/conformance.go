@@ -0,0 +1,78 @@
+package collector
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// AllMethods is the method key of a latency SLO applying to every method
+// without one of its own
+const AllMethods = "*"
+
+// ParseLatencySLOs parses per-method latency thresholds such as
+// "Get=5ms,Put=20ms,*=10ms" into milliseconds by method
+func ParseLatencySLOs(list string) (map[string]float64, error) {
+	thresholds := make(map[string]float64)
+	for _, item := range strings.Split(list, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		method, value, ok := strings.Cut(item, "=")
+		if !ok || method == "" {
+			return nil, fmt.Errorf("invalid latency SLO %q (expected method=duration)", item)
+		}
+		d, err := time.ParseDuration(value)
+		if err != nil || d <= 0 {
+			return nil, fmt.Errorf("latency SLO %q: invalid threshold %q", item, value)
+		}
+		thresholds[method] = float64(d) / float64(time.Millisecond)
+	}
+	return thresholds, nil
+}
+
+// sloThreshold returns the latency threshold of a method, 0 for none
+func (c *Collector) sloThreshold(method string) float64 {
+	if t, ok := c.sloThresholds[method]; ok {
+		return t
+	}
+	return c.sloThresholds[AllMethods]
+}
+
+// HasLatencySLOs reports whether latency thresholds are set
+func (c *Collector) HasLatencySLOs() bool {
+	return len(c.sloThresholds) > 0
+}
+
+// newMethodMetrics creates the metrics of a method with the collector's
+// percentile engine, percentiles and latency SLO; the caller holds c.mu
+func (c *Collector) newMethodMetrics(method string) *Metrics {
+	m := newMetrics(method, c.engine, c.pcts, c.latencyMax)
+	m.sloThreshold = c.sloThreshold(method)
+	m.trimPct = c.trimPct
+	return m
+}
+
+// countSLO counts a result against the latency threshold; failed operations
+// are violations. The caller holds m.mu.
+func (m *Metrics) countSLO(result *BenchmarkResult) {
+	if m.sloThreshold <= 0 {
+		return
+	}
+	if result.Error == nil && result.LatencyMs <= m.sloThreshold {
+		m.SLOMet++
+	} else {
+		m.SLOViolations++
+	}
+}
+
+// SLOConformance returns the percentage of operations that met their latency
+// SLO, or -1 when none was counted
+func (s Stats) SLOConformance() float64 {
+	counted := s.SLOMet + s.SLOViolations
+	if counted == 0 {
+		return -1
+	}
+	return float64(s.SLOMet) / float64(counted) * 100
+}
@@ -0,0 +1,60 @@
+package collector
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"kvstore-benchmarker/pkg/latency"
+)
+
+// CSVFormat controls how values are rendered in the CSV output
+type CSVFormat struct {
+	Delimiter  rune   // Field delimiter, ',' when unset
+	Precision  int    // Decimal places of latency and rate columns, negative for the defaults
+	Timestamps string // "iso" (RFC 3339, default), "epoch" (Unix seconds) or "epoch-ms"
+}
+
+// ParseCSVDelimiter parses a delimiter option, accepting "tab" for a tab character
+func ParseCSVDelimiter(value string) (rune, error) {
+	switch value {
+	case "", ",":
+		return ',', nil
+	case "tab", "\\t", "\t":
+		return '\t', nil
+	}
+	runes := []rune(value)
+	if len(runes) != 1 || runes[0] == '"' || runes[0] == '\r' || runes[0] == '\n' {
+		return 0, fmt.Errorf("invalid CSV delimiter %q", value)
+	}
+	return runes[0], nil
+}
+
+// timestamp renders a timestamp column
+func (f CSVFormat) timestamp(t time.Time) string {
+	switch f.Timestamps {
+	case "epoch":
+		return strconv.FormatInt(t.Unix(), 10)
+	case "epoch-ms":
+		return strconv.FormatInt(t.UnixMilli(), 10)
+	default:
+		return t.Format(time.RFC3339Nano)
+	}
+}
+
+// latency renders a latency column given in milliseconds
+func (f CSVFormat) latency(unit latency.Unit, ms float64) string {
+	if f.Precision < 0 {
+		return unit.Value(ms)
+	}
+	return unit.ValuePrecision(ms, f.Precision)
+}
+
+// rate renders a percentage column
+func (f CSVFormat) rate(value float64) string {
+	precision := f.Precision
+	if precision < 0 {
+		precision = 2
+	}
+	return strconv.FormatFloat(value, 'f', precision, 64)
+}
@@ -0,0 +1,324 @@
+package collector
+
+import (
+	"bufio"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"kvstore-benchmarker/pkg/latency"
+)
+
+// csvSink writes the results CSV: one row per method and an aggregated row,
+// either for the whole run or, in interval mode, every report interval. The
+// header is written right away and, outside interval mode, the rows are
+// rewritten in place with the statistics so far every report interval, so
+// that a crashed or killed run leaves the results up to its last interval.
+type csvSink struct {
+	file      *os.File
+	body      int64 // Offset of the first row, -1 if the file cannot be rewritten (stdout, pipes)
+	writer    *csv.Writer
+	format    CSVFormat
+	unit      latency.Unit
+	pcts      []float64
+	slo       bool
+	intervals bool
+	tagged    bool
+	runID     string // Value of the run_id column, none if empty
+	labels    []Label
+}
+
+// newCSVSink creates the results CSV at path, "-" for stdout, and writes its
+// header. With CSVAppend the rows are added to an existing file instead,
+// whose header must match.
+func newCSVSink(path string, opts Options, unit latency.Unit) (*csvSink, error) {
+	s := &csvSink{
+		format:    opts.CSVFormat,
+		unit:      unit,
+		pcts:      opts.Percentiles,
+		slo:       len(opts.LatencySLOs) > 0,
+		intervals: opts.CSVIntervals,
+		tagged:    opts.Tagged,
+		runID:     opts.CSVRunID,
+		labels:    opts.Labels,
+	}
+	header, err := s.header()
+	if err != nil {
+		return nil, err
+	}
+
+	file := os.Stdout
+	appending := false
+	if path != "-" {
+		if opts.CSVAppend {
+			file, err = os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0o644)
+		} else {
+			file, err = os.Create(path)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to create CSV file: %w", err)
+		}
+		if opts.CSVAppend {
+			if appending, err = checkCSVHeader(file, header); err != nil {
+				file.Close()
+				return nil, err
+			}
+		}
+	}
+	s.file = file
+	s.writer = csv.NewWriter(file)
+	s.writer.Comma = opts.CSVFormat.Delimiter
+
+	// Comments lead the file, so runs appended later go without them
+	if !appending {
+		for _, line := range opts.CSVComments {
+			fmt.Fprintf(file, "# %s\n", line)
+		}
+		if _, err := file.WriteString(header); err != nil {
+			s.Close()
+			return nil, fmt.Errorf("failed to write CSV header: %w", err)
+		}
+	}
+	s.body = -1
+	if info, err := file.Stat(); err == nil && info.Mode().IsRegular() {
+		if body, err := file.Seek(0, io.SeekCurrent); err == nil {
+			s.body = body
+		}
+	}
+	return s, nil
+}
+
+// header renders the header line of the CSV
+func (s *csvSink) header() (string, error) {
+	header := []string{"timestamp", "offset_s"}
+	if s.runID != "" {
+		header = append(header, "run_id")
+	}
+	header = append(header, "method")
+	if s.tagged {
+		header = append(header, "tags")
+	}
+	for _, label := range s.labels {
+		header = append(header, label.Name)
+	}
+	header = append(header,
+		"total_ops",
+		"success_ops",
+		"error_ops",
+		"error_rate_pct",
+		"throttled_ops",
+		"throttle_rate_pct",
+		s.unit.Column("avg_latency"),
+		s.unit.Column("trimmed_mean_latency"),
+		s.unit.Column("mad_latency"),
+	)
+	for _, p := range s.pcts {
+		header = append(header, s.unit.Column(percentileColumn(p)))
+	}
+	header = append(header,
+		s.unit.Column("min_latency"),
+		s.unit.Column("max_latency"),
+		"throughput_ops_per_sec",
+		"read_mb_per_sec",
+		"write_mb_per_sec",
+		"wire_sent_bytes_per_op",
+		"wire_recv_bytes_per_op",
+		"dropped_ops",
+		"dropped_pct",
+		"overflow_ops",
+		s.unit.Column("error_avg_latency"),
+		s.unit.Column("error_p99_latency"),
+		s.unit.Column("error_max_latency"),
+	)
+	if s.slo {
+		header = append(header, "slo_met_pct", "slo_violations")
+	}
+
+	var b strings.Builder
+	w := csv.NewWriter(&b)
+	w.Comma = s.format.Delimiter
+	w.Write(append(header, "error_codes"))
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return "", fmt.Errorf("failed to write CSV header: %w", err)
+	}
+	return b.String(), nil
+}
+
+// checkCSVHeader checks that the CSV a run is appended to has the given
+// header, skipping comment lines, and moves to its end. It reports whether
+// the file has a header, false if it is empty.
+func checkCSVHeader(file *os.File, header string) (bool, error) {
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(nil, 1<<20)
+	var existing string
+	found := false
+	for scanner.Scan() {
+		if line := scanner.Text(); !strings.HasPrefix(line, "#") {
+			existing, found = line, true
+			break
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return false, fmt.Errorf("failed to read CSV header: %w", err)
+	}
+	if found && existing != strings.TrimSuffix(header, "\n") {
+		return false, fmt.Errorf("cannot append to %s: its columns differ from those of this run (percentiles, labels, tags, SLOs and the latency unit must match)", file.Name())
+	}
+	if _, err := file.Seek(0, io.SeekEnd); err != nil {
+		return false, fmt.Errorf("failed to append to CSV file: %w", err)
+	}
+	return found, nil
+}
+
+// Start does nothing; the header is written when the file is created
+func (s *csvSink) Start(time.Time) error {
+	return nil
+}
+
+// RecordInterval writes the rows of an interval in interval mode
+func (s *csvSink) RecordInterval(interval SinkStats) error {
+	if !s.intervals {
+		return nil
+	}
+	return s.write(interval)
+}
+
+// RecordProgress replaces the summary rows with those of the run so far,
+// unless in interval mode or the file cannot be rewritten
+func (s *csvSink) RecordProgress(sofar SinkStats) error {
+	if s.intervals || s.body < 0 {
+		return nil
+	}
+	return s.rewrite(sofar)
+}
+
+// RecordFinal writes the summary rows unless in interval mode, replacing
+// those of the run so far. Throughput is in successful ops/sec over the
+// measured window of the run.
+func (s *csvSink) RecordFinal(final SinkStats) error {
+	if s.intervals {
+		return nil
+	}
+	if s.body < 0 {
+		return s.write(final)
+	}
+	return s.rewrite(final)
+}
+
+// rewrite truncates the file after the header and writes the rows anew
+func (s *csvSink) rewrite(stats SinkStats) error {
+	if err := s.file.Truncate(s.body); err != nil {
+		return fmt.Errorf("failed to rewrite CSV rows: %w", err)
+	}
+	if _, err := s.file.Seek(s.body, io.SeekStart); err != nil {
+		return fmt.Errorf("failed to rewrite CSV rows: %w", err)
+	}
+	return s.write(stats)
+}
+
+// write writes a row per method and the aggregated row, stamped with the end
+// of the span as wall-clock time and run offset, then those of every tag
+// combination
+func (s *csvSink) write(stats SinkStats) error {
+	if len(stats.Methods) == 0 {
+		return nil
+	}
+	timestamp := []string{s.format.timestamp(stats.End), fmt.Sprintf("%.3f", stats.Offset.Seconds())}
+	for _, method := range stats.Methods {
+		s.writer.Write(s.row(timestamp, "", method, stats.Throughput(method)))
+	}
+	s.writer.Write(s.row(timestamp, "", stats.Aggregated, stats.Throughput(stats.Aggregated)))
+	for _, group := range stats.Tagged {
+		for _, method := range group.Methods {
+			s.writer.Write(s.row(timestamp, group.Tags, method, stats.Throughput(method)))
+		}
+		s.writer.Write(s.row(timestamp, group.Tags, group.Aggregated, stats.Throughput(group.Aggregated)))
+	}
+	s.writer.Flush()
+	if err := s.writer.Error(); err != nil {
+		return fmt.Errorf("failed to write CSV rows: %w", err)
+	}
+	return nil
+}
+
+// row renders statistics as a row of the results CSV, with the tag
+// combination in the tags column if tagged and a column per run label.
+// Bandwidth follows from the throughput of successful operations and their
+// average payload.
+func (s *csvSink) row(timestamp []string, tags string, stats Stats, throughput float64) []string {
+	var readMBps, writeMBps float64
+	wireSent, wireRecv := stats.WirePerOp()
+	if success := stats.Successes(); success > 0 {
+		readMBps = float64(stats.BytesRecv) / float64(success) * throughput / bytesPerMB
+		writeMBps = float64(stats.BytesSent) / float64(success) * throughput / bytesPerMB
+	}
+
+	row := append([]string(nil), timestamp...)
+	if s.runID != "" {
+		row = append(row, s.runID)
+	}
+	row = append(row, stats.Method)
+	if s.tagged {
+		row = append(row, tags)
+	}
+	for _, label := range s.labels {
+		row = append(row, label.Value)
+	}
+	row = append(row,
+		fmt.Sprintf("%d", stats.Count),
+		fmt.Sprintf("%d", stats.Successes()),
+		fmt.Sprintf("%d", stats.ErrorCount),
+		s.format.rate(stats.ErrorRate),
+		fmt.Sprintf("%d", stats.Throttled),
+		s.format.rate(stats.ThrottleRate()),
+		s.format.latency(s.unit, stats.AvgLatency),
+		s.format.latency(s.unit, stats.TrimmedMean),
+		s.format.latency(s.unit, stats.MAD),
+	)
+	for i := range s.pcts {
+		row = append(row, s.format.latency(s.unit, stats.Percentile(i)))
+	}
+	row = append(row,
+		s.format.latency(s.unit, stats.MinLatency),
+		s.format.latency(s.unit, stats.MaxLatency),
+		fmt.Sprintf("%.0f", throughput),
+		s.format.rate(readMBps),
+		s.format.rate(writeMBps),
+		fmt.Sprintf("%.0f", wireSent),
+		fmt.Sprintf("%.0f", wireRecv),
+		fmt.Sprintf("%d", stats.Dropped),
+		s.format.rate(stats.DroppedPct()),
+		fmt.Sprintf("%d", stats.Overflow),
+		s.format.latency(s.unit, stats.ErrorAvgLatency),
+		s.format.latency(s.unit, stats.ErrorP99Latency),
+		s.format.latency(s.unit, stats.ErrorMaxLatency),
+	)
+	// Conformance columns are empty for methods without a latency SLO
+	if s.slo {
+		if conformance := stats.SLOConformance(); conformance >= 0 {
+			row = append(row, s.format.rate(conformance), fmt.Sprintf("%d", stats.SLOViolations))
+		} else {
+			row = append(row, "", "")
+		}
+	}
+	return append(row, formatErrorCodes(stats.ErrorCodes))
+}
+
+// Close flushes the CSV and closes the file unless it is stdout
+func (s *csvSink) Close() error {
+	s.writer.Flush()
+	err := s.writer.Error()
+	if s.file != os.Stdout {
+		if closeErr := s.file.Close(); err == nil {
+			err = closeErr
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("failed to close CSV file: %w", err)
+	}
+	return nil
+}
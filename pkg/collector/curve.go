@@ -0,0 +1,80 @@
+package collector
+
+import (
+	"encoding/csv"
+	"fmt"
+	"os"
+	"time"
+)
+
+// CurveLog writes one CSV row per report interval pairing the achieved
+// throughput with the latency percentiles of all methods combined, so a
+// throughput/latency curve can be plotted straight from a ramped run
+type CurveLog struct {
+	file   *os.File
+	writer *csv.Writer
+	start  time.Time
+}
+
+// NewCurveLog creates a throughput/latency curve file
+func NewCurveLog(path string) (*CurveLog, error) {
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create throughput/latency log: %w", err)
+	}
+	return &CurveLog{file: file, writer: csv.NewWriter(file)}, nil
+}
+
+// beginCurve writes the curve header; offsets are relative to start. The
+// caller holds c.mu.
+func (c *Collector) beginCurve(start time.Time) error {
+	c.curve.start = start
+	header := []string{"offset_s", "start", "end", "throughput_ops_per_sec", "total_ops", "error_rate_pct", c.unit.Column("avg_latency")}
+	for _, p := range c.pcts {
+		header = append(header, c.unit.Column(percentileColumn(p)))
+	}
+	header = append(header, c.unit.Column("max_latency"))
+	if err := c.curve.writer.Write(header); err != nil {
+		return fmt.Errorf("failed to write throughput/latency header: %w", err)
+	}
+	return nil
+}
+
+// writeCurve writes the row of the interval from..to; the caller holds c.mu
+func (c *Collector) writeCurve(from, to time.Time, stats Stats) error {
+	var throughput float64
+	if seconds := to.Sub(from).Seconds(); seconds > 0 {
+		throughput = float64(stats.Successes()) / seconds
+	}
+
+	row := []string{
+		fmt.Sprintf("%.3f", to.Sub(c.curve.start).Seconds()),
+		c.csvFormat.timestamp(from),
+		c.csvFormat.timestamp(to),
+		fmt.Sprintf("%.0f", throughput),
+		fmt.Sprintf("%d", stats.Count),
+		c.csvFormat.rate(stats.ErrorRate),
+		c.csvFormat.latency(c.unit, stats.AvgLatency),
+	}
+	for i := range c.pcts {
+		row = append(row, c.csvFormat.latency(c.unit, stats.Percentile(i)))
+	}
+	row = append(row, c.csvFormat.latency(c.unit, stats.MaxLatency))
+
+	c.curve.writer.Write(row)
+	c.curve.writer.Flush()
+	if err := c.curve.writer.Error(); err != nil {
+		return fmt.Errorf("failed to write throughput/latency row: %w", err)
+	}
+	return nil
+}
+
+// Close flushes and closes the curve file
+func (l *CurveLog) Close() error {
+	l.writer.Flush()
+	if err := l.writer.Error(); err != nil {
+		l.file.Close()
+		return fmt.Errorf("failed to flush throughput/latency log: %w", err)
+	}
+	return l.file.Close()
+}
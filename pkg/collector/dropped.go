@@ -0,0 +1,61 @@
+package collector
+
+// DroppedPct returns the share of results dropped before reaching the
+// statistics, as a percentage of all results produced
+func (s Stats) DroppedPct() float64 {
+	if s.Count+s.Dropped == 0 {
+		return 0
+	}
+	return float64(s.Dropped) / float64(s.Count+s.Dropped) * 100.0
+}
+
+// MeasuredDropped returns the number of measured results dropped, the sum of
+// DroppedByMethod; unlike Dropped it leaves out warm-up results
+func (c *Collector) MeasuredDropped() int64 {
+	c.droppedMu.Lock()
+	defer c.droppedMu.Unlock()
+
+	var total int64
+	for _, n := range c.droppedBy {
+		total += n
+	}
+	return total
+}
+
+// DroppedByMethod returns the number of measured results dropped by method
+func (c *Collector) DroppedByMethod() map[string]int64 {
+	c.droppedMu.Lock()
+	defer c.droppedMu.Unlock()
+
+	dropped := make(map[string]int64, len(c.droppedBy))
+	for method, n := range c.droppedBy {
+		dropped[method] = n
+	}
+	return dropped
+}
+
+// droppedSince returns the results dropped by method since base, and the
+// current counts to use as the next base
+func (c *Collector) droppedSince(base map[string]int64) (delta, current map[string]int64) {
+	current = c.DroppedByMethod()
+	delta = make(map[string]int64, len(current))
+	for method, n := range current {
+		if n > base[method] {
+			delta[method] = n - base[method]
+		}
+	}
+	return delta, current
+}
+
+// setDropped sets the dropped counts of the methods of s and their total on
+// the aggregated statistics
+func (s *SinkStats) setDropped(dropped map[string]int64) {
+	var total int64
+	for i := range s.Methods {
+		s.Methods[i].Dropped = dropped[s.Methods[i].Method]
+	}
+	for _, n := range dropped {
+		total += n
+	}
+	s.Aggregated.Dropped = total
+}
@@ -0,0 +1,72 @@
+package collector
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// agentErrorCode extracts the code from a gRPC error message reported by an
+// external agent, e.g. "rpc error: code = Unavailable desc = ..."
+var agentErrorCode = regexp.MustCompile(`code = (\w+)`)
+
+// ErrorCode classifies an operation error by its gRPC status code name, e.g.
+// "DeadlineExceeded" or "Unavailable". Errors without a status map to
+// "Unknown" unless they are context errors.
+func ErrorCode(err error) string {
+	if s, ok := status.FromError(err); ok {
+		return s.Code().String()
+	}
+	if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, context.Canceled) {
+		return status.FromContextError(err).Code().String()
+	}
+	if m := agentErrorCode.FindStringSubmatch(err.Error()); m != nil {
+		return m[1]
+	}
+	return codes.Unknown.String()
+}
+
+// mergeErrorCodes adds the counts of src to dst, allocating dst when needed
+func mergeErrorCodes(dst, src map[string]int64) map[string]int64 {
+	if len(src) == 0 {
+		return dst
+	}
+	if dst == nil {
+		dst = make(map[string]int64, len(src))
+	}
+	for code, n := range src {
+		dst[code] += n
+	}
+	return dst
+}
+
+// SortedErrorCodes returns the codes of a breakdown ordered by descending count
+func SortedErrorCodes(counts map[string]int64) []string {
+	codes := make([]string, 0, len(counts))
+	for code := range counts {
+		codes = append(codes, code)
+	}
+	sort.Slice(codes, func(i, j int) bool {
+		if counts[codes[i]] != counts[codes[j]] {
+			return counts[codes[i]] > counts[codes[j]]
+		}
+		return codes[i] < codes[j]
+	})
+	return codes
+}
+
+// formatErrorCodes renders a breakdown as "Code=count" pairs separated by
+// semicolons, most frequent first
+func formatErrorCodes(counts map[string]int64) string {
+	parts := make([]string, 0, len(counts))
+	for _, code := range SortedErrorCodes(counts) {
+		parts = append(parts, fmt.Sprintf("%s=%d", code, counts[code]))
+	}
+	return strings.Join(parts, ";")
+}
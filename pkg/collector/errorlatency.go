@@ -0,0 +1,47 @@
+package collector
+
+// errorLatency tracks the latency distribution of failed operations apart
+// from successes, so that slow failures such as deadline expiries show up
+// without skewing the success percentiles
+type errorLatency struct {
+	recorder latencyRecorder
+	total    float64
+	max      float64
+}
+
+// recordError adds the latency of a failed operation, creating the
+// distribution on the first failure; the caller holds m.mu
+func (m *Metrics) recordError(ms float64) {
+	if m.errors == nil {
+		m.errors = &errorLatency{recorder: newLatencyRecorder(m.engine, m.latencyMax)}
+	}
+	m.errors.total += ms
+	m.errors.max = max(m.errors.max, ms)
+	m.errors.recorder.Record(min(ms, m.latencyMax))
+}
+
+// newErrorLatency creates an empty failure distribution to merge into
+func (c *Collector) newErrorLatency() *errorLatency {
+	return &errorLatency{recorder: newLatencyRecorder(c.engine, c.latencyMax)}
+}
+
+// merge adds the failures of other, which may be nil
+func (e *errorLatency) merge(other *errorLatency) {
+	if other == nil {
+		return
+	}
+	e.recorder.Merge(other.recorder)
+	e.total += other.total
+	e.max = max(e.max, other.max)
+}
+
+// fill sets the failed-operation latencies of stats with count failures
+func (e *errorLatency) fill(stats *Stats, count int64) {
+	if e == nil || count == 0 {
+		return
+	}
+	stats.ErrorAvgLatency = e.total / float64(count)
+	stats.ErrorP50Latency = e.recorder.Percentile(50)
+	stats.ErrorP99Latency = e.recorder.Percentile(99)
+	stats.ErrorMaxLatency = e.max
+}
@@ -0,0 +1,131 @@
+package collector
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+)
+
+// exactSampleCap is the number of latencies an exact recorder keeps per
+// method, 8 MB of samples. Beyond it the recorder moves all samples into an
+// HDR histogram, trading exactness for constant memory.
+const exactSampleCap = 1 << 20
+
+// exactRecorder keeps every latency and computes exact nearest-rank
+// percentiles until it holds exactSampleCap samples
+type exactRecorder struct {
+	samples []float64
+	sorted  bool
+	maxMs   float64      // Latency bound of the histogram spilled into
+	spilled *hdrRecorder // All samples once the cap was exceeded
+}
+
+// newExactRecorder creates an empty exact recorder that spills into a
+// histogram sized for latencies up to maxMs milliseconds
+func newExactRecorder(maxMs float64) *exactRecorder {
+	return &exactRecorder{sorted: true, maxMs: maxMs}
+}
+
+// Record records a latency in milliseconds
+func (r *exactRecorder) Record(ms float64) {
+	if r.spilled != nil {
+		r.spilled.Record(ms)
+		return
+	}
+	r.samples = append(r.samples, ms)
+	r.sorted = false
+	if len(r.samples) > exactSampleCap {
+		r.spill()
+	}
+}
+
+// spill moves the samples into an HDR histogram that records from now on
+func (r *exactRecorder) spill() {
+	r.spilled = newHDRRecorder(r.maxMs)
+	for _, ms := range r.samples {
+		r.spilled.Record(ms)
+	}
+	r.samples = nil
+	r.sorted = true
+}
+
+// Percentile returns the latency in milliseconds at the given percentile, the
+// smallest sample with at least p percent of samples at or below it
+func (r *exactRecorder) Percentile(p float64) float64 {
+	if r.spilled != nil {
+		return r.spilled.Percentile(p)
+	}
+	if len(r.samples) == 0 {
+		return 0
+	}
+	if !r.sorted {
+		sort.Float64s(r.samples)
+		r.sorted = true
+	}
+	rank := int(math.Ceil(p/100*float64(len(r.samples)))) - 1
+	return r.samples[min(max(rank, 0), len(r.samples)-1)]
+}
+
+// Merge adds the samples of another exact recorder, spilling into a
+// histogram when either has spilled or the cap is exceeded
+func (r *exactRecorder) Merge(other latencyRecorder) {
+	o, ok := other.(*exactRecorder)
+	if !ok {
+		return
+	}
+	if o.spilled != nil && r.spilled == nil {
+		r.spill()
+	}
+	if r.spilled != nil {
+		if o.spilled != nil {
+			r.spilled.Merge(o.spilled)
+		}
+		for _, ms := range o.samples {
+			r.spilled.Record(ms)
+		}
+		return
+	}
+	r.samples = append(r.samples, o.samples...)
+	r.sorted = false
+	if len(r.samples) > exactSampleCap {
+		r.spill()
+	}
+}
+
+// exactEncoding is the checkpoint encoding of an exact recorder
+type exactEncoding struct {
+	Samples []float64 `json:"samples,omitempty"`
+	Spilled []byte    `json:"spilled,omitempty"` // Encoded HDR histogram once spilled
+}
+
+// Marshal encodes the samples, or the histogram once spilled; the histogram
+// encoding keeps its range
+func (r *exactRecorder) Marshal() ([]byte, error) {
+	enc := exactEncoding{Samples: r.samples}
+	if r.spilled != nil {
+		data, err := r.spilled.Marshal()
+		if err != nil {
+			return nil, err
+		}
+		enc.Spilled = data
+	}
+	return json.Marshal(enc)
+}
+
+// unmarshalExactRecorder decodes a recorder encoded by Marshal
+func unmarshalExactRecorder(data []byte, maxMs float64) (*exactRecorder, error) {
+	var enc exactEncoding
+	if err := json.Unmarshal(data, &enc); err != nil {
+		return nil, fmt.Errorf("failed to decode exact samples: %w", err)
+	}
+	r := &exactRecorder{samples: enc.Samples, maxMs: maxMs}
+	if enc.Spilled != nil {
+		spilled, err := unmarshalHDRRecorder(enc.Spilled)
+		if err != nil {
+			return nil, err
+		}
+		r.spilled = spilled
+	}
+	return r, nil
+}
@@ -0,0 +1,65 @@
+package collector
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+)
+
+// intervalPusher calls push at every interval and once more when stopped
+type intervalPusher struct {
+	name     string
+	interval time.Duration
+	align    bool // Push at wall-clock multiples of the interval
+	push     func(now time.Time) error
+	done     chan struct{}
+	wg       sync.WaitGroup
+}
+
+// newIntervalPusher creates a pusher; name is used in warning messages
+func newIntervalPusher(name string, interval time.Duration, align bool, push func(now time.Time) error) *intervalPusher {
+	return &intervalPusher{
+		name:     name,
+		interval: interval,
+		align:    align,
+		push:     push,
+		done:     make(chan struct{}),
+	}
+}
+
+// start starts the push loop
+func (p *intervalPusher) start(ctx context.Context) {
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+
+		ticker := NewIntervalTicker(p.interval, p.align)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-p.done:
+				return
+			case now := <-ticker.C:
+				p.pushOnce(now)
+			}
+		}
+	}()
+}
+
+// stop stops the push loop and performs a final push
+func (p *intervalPusher) stop() {
+	close(p.done)
+	p.wg.Wait()
+	p.pushOnce(time.Now())
+}
+
+// pushOnce performs a single push and logs failures
+func (p *intervalPusher) pushOnce(now time.Time) {
+	if err := p.push(now); err != nil {
+		log.Printf("Warning: %s push failed: %v", p.name, err)
+	}
+}
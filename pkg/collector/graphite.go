@@ -0,0 +1,146 @@
+package collector
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// DefaultGraphiteTemplate is the default metric path template
+const DefaultGraphiteTemplate = "kvbench.{method}.{metric}"
+
+// GraphiteExporter pushes per-method statistics over TCP in the Graphite
+// plaintext protocol every interval. Metric paths are built from a template
+// with the placeholders {method}, {metric}, {run_id} and {host}; run labels
+// are appended as Graphite tags (";name=value").
+type GraphiteExporter struct {
+	collector *Collector
+	addr      string
+	template  string
+	runID     string
+	host      string
+	pusher    *intervalPusher
+	last      map[string]Stats // Statistics at the previous push, for interval deltas
+	lastPush  time.Time
+}
+
+// NewGraphiteExporter creates an exporter pushing to the Carbon plaintext
+// listener at addr (host:port) every interval
+func NewGraphiteExporter(c *Collector, addr, template, runID string, interval time.Duration) *GraphiteExporter {
+	host, _ := os.Hostname()
+	e := &GraphiteExporter{
+		collector: c,
+		addr:      addr,
+		template:  template,
+		runID:     runID,
+		host:      host,
+		last:      make(map[string]Stats),
+		lastPush:  time.Now(),
+	}
+	e.pusher = newIntervalPusher("Graphite", interval, c.alignIntervals, e.push)
+	return e
+}
+
+// Start starts the push loop
+func (e *GraphiteExporter) Start(ctx context.Context) {
+	e.pusher.start(ctx)
+}
+
+// Stop stops the push loop and pushes the final interval
+func (e *GraphiteExporter) Stop() {
+	e.pusher.stop()
+}
+
+// push sends one line per method and metric. Operation and error counts and
+// the throughput cover the interval since the previous push; latencies are
+// cumulative.
+func (e *GraphiteExporter) push(now time.Time) error {
+	stats := e.collector.GetStats()
+	seconds := now.Sub(e.lastPush).Seconds()
+	e.lastPush = now
+
+	methods := make([]string, 0, len(stats))
+	for method, stat := range stats {
+		if stat.Count > 0 {
+			methods = append(methods, method)
+		}
+	}
+	sort.Strings(methods)
+
+	var buf bytes.Buffer
+	ts := now.Unix()
+	for _, method := range methods {
+		stat := stats[method]
+		prev := e.last[method]
+		e.last[method] = stat
+
+		ops := stat.Count - prev.Count
+		var throughput float64
+		if seconds > 0 {
+			throughput = float64(ops) / seconds
+		}
+
+		values := []struct {
+			metric string
+			value  float64
+		}{
+			{"ops", float64(ops)},
+			{"errors", float64(stat.ErrorCount - prev.ErrorCount)},
+			{"throughput", throughput},
+			{"latency.avg_ms", stat.AvgLatency},
+			{"latency.p50_ms", stat.P50Latency},
+			{"latency.p95_ms", stat.P95Latency},
+			{"latency.p99_ms", stat.P99Latency},
+			{"latency.p999_ms", stat.P999Latency},
+			{"latency.max_ms", stat.MaxLatency},
+		}
+		for _, v := range values {
+			fmt.Fprintf(&buf, "%s %g %d\n", e.path(method, v.metric), v.value, ts)
+		}
+	}
+	if buf.Len() == 0 {
+		return nil
+	}
+
+	conn, err := net.DialTimeout("tcp", e.addr, 5*time.Second)
+	if err != nil {
+		return fmt.Errorf("failed to connect to %s: %w", e.addr, err)
+	}
+	defer conn.Close()
+
+	conn.SetWriteDeadline(time.Now().Add(5 * time.Second))
+	if _, err := conn.Write(buf.Bytes()); err != nil {
+		return fmt.Errorf("failed to send metrics: %w", err)
+	}
+	return nil
+}
+
+// path expands the template for a method and metric and appends the run labels
+func (e *GraphiteExporter) path(method, metric string) string {
+	path := strings.NewReplacer(
+		"{method}", graphiteNode(strings.ToLower(method)),
+		"{metric}", metric,
+		"{run_id}", graphiteNode(e.runID),
+		"{host}", graphiteNode(e.host),
+	).Replace(e.template)
+	for _, label := range e.collector.Labels() {
+		path += ";" + label.Name + "=" + label.Value
+	}
+	return path
+}
+
+// graphiteNode makes a value safe to use as a single path node
+func graphiteNode(value string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case '.', ' ', '/', '\t', '\n':
+			return '_'
+		}
+		return r
+	}, value)
+}
@@ -0,0 +1,112 @@
+package collector
+
+import (
+	"encoding/csv"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// heatmapBounds are the upper bounds in milliseconds of the heatmap latency
+// buckets, a 1-2-5 series from 10µs to 10s; a last bucket holds the rest
+var heatmapBounds = []float64{
+	0.01, 0.02, 0.05,
+	0.1, 0.2, 0.5,
+	1, 2, 5,
+	10, 20, 50,
+	100, 200, 500,
+	1000, 2000, 5000,
+	10000,
+}
+
+// Heatmap writes a latency heatmap as CSV: one row per report interval and
+// method, plus an AGGREGATED row, holding the number of operations in every
+// latency bucket. Unlike percentiles, the matrix keeps shifts between latency
+// modes visible.
+type Heatmap struct {
+	file   *os.File
+	writer *csv.Writer
+	counts map[string][]int64
+}
+
+// NewHeatmap creates a heatmap CSV file and writes its header
+func NewHeatmap(path string) (*Heatmap, error) {
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create heatmap: %w", err)
+	}
+
+	h := &Heatmap{file: file, writer: csv.NewWriter(file), counts: make(map[string][]int64)}
+	header := []string{"start", "end", "method"}
+	for _, bound := range heatmapBounds {
+		header = append(header, "le_"+strconv.FormatFloat(bound, 'f', -1, 64)+"ms")
+	}
+	header = append(header, "gt_"+strconv.FormatFloat(heatmapBounds[len(heatmapBounds)-1], 'f', -1, 64)+"ms")
+	if err := h.writer.Write(header); err != nil {
+		file.Close()
+		return nil, fmt.Errorf("failed to write heatmap header: %w", err)
+	}
+	return h, nil
+}
+
+// add counts a result in the bucket of its latency
+func (h *Heatmap) add(result *BenchmarkResult) {
+	counts, ok := h.counts[result.Method]
+	if !ok {
+		counts = make([]int64, len(heatmapBounds)+1)
+		h.counts[result.Method] = counts
+	}
+	counts[sort.SearchFloat64s(heatmapBounds, result.LatencyMs)]++
+}
+
+// writeInterval writes the rows of the interval from..to and starts counting
+// the next one
+func (h *Heatmap) writeInterval(from, to time.Time) error {
+	if len(h.counts) == 0 {
+		return nil
+	}
+
+	methods := make([]string, 0, len(h.counts))
+	for method := range h.counts {
+		methods = append(methods, method)
+	}
+	sort.Strings(methods)
+
+	start := from.UTC().Format(time.RFC3339Nano)
+	end := to.UTC().Format(time.RFC3339Nano)
+	total := make([]int64, len(heatmapBounds)+1)
+	row := func(method string, counts []int64) []string {
+		fields := []string{start, end, method}
+		for _, n := range counts {
+			fields = append(fields, strconv.FormatInt(n, 10))
+		}
+		return fields
+	}
+	for _, method := range methods {
+		counts := h.counts[method]
+		for i, n := range counts {
+			total[i] += n
+		}
+		h.writer.Write(row(method, counts))
+	}
+	h.writer.Write(row("AGGREGATED", total))
+
+	h.counts = make(map[string][]int64)
+	h.writer.Flush()
+	if err := h.writer.Error(); err != nil {
+		return fmt.Errorf("failed to write heatmap: %w", err)
+	}
+	return nil
+}
+
+// Close flushes and closes the heatmap file
+func (h *Heatmap) Close() error {
+	h.writer.Flush()
+	if err := h.writer.Error(); err != nil {
+		h.file.Close()
+		return fmt.Errorf("failed to flush heatmap: %w", err)
+	}
+	return h.file.Close()
+}
@@ -0,0 +1,85 @@
+package collector
+
+import (
+	"encoding/base64"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/HdrHistogram/hdrhistogram-go"
+)
+
+// Latency histograms record nanoseconds, the HdrHistogram log convention,
+// from 1ns up to the collector's latency bound (one hour by default) with
+// three significant digits, which keeps memory constant regardless of sample
+// count
+const (
+	histogramMinValue = 1
+	histogramSigFigs  = 3
+)
+
+// DefaultLatencyMax is the default highest latency recorded as measured;
+// slower operations are counted as overflow and recorded at the bound
+const DefaultLatencyMax = time.Hour
+
+// hdrRecorder records latencies into an HdrHistogram
+type hdrRecorder struct {
+	histogram *hdrhistogram.Histogram
+}
+
+// newHDRRecorder creates an empty HDR histogram recorder tracking latencies
+// up to maxMs milliseconds
+func newHDRRecorder(maxMs float64) *hdrRecorder {
+	maxValue := max(int64(math.Round(maxMs*float64(time.Millisecond))), 2*histogramMinValue)
+	return &hdrRecorder{histogram: hdrhistogram.New(histogramMinValue, maxValue, histogramSigFigs)}
+}
+
+// empty creates an empty HDR recorder with the same range
+func (r *hdrRecorder) empty() *hdrRecorder {
+	return &hdrRecorder{histogram: hdrhistogram.New(histogramMinValue, r.histogram.HighestTrackableValue(), histogramSigFigs)}
+}
+
+// Record records a latency in milliseconds, clamping values outside the trackable range
+func (r *hdrRecorder) Record(ms float64) {
+	value := int64(math.Round(ms * float64(time.Millisecond)))
+	if value < 0 {
+		value = 0
+	}
+	if highest := r.histogram.HighestTrackableValue(); value > highest {
+		value = highest
+	}
+	r.histogram.RecordValue(value)
+}
+
+// Percentile returns the latency in milliseconds at the given percentile
+func (r *hdrRecorder) Percentile(p float64) float64 {
+	if r.histogram.TotalCount() == 0 {
+		return 0
+	}
+	return float64(r.histogram.ValueAtQuantile(p)) / float64(time.Millisecond)
+}
+
+// Merge adds the counts of another HDR recorder
+func (r *hdrRecorder) Merge(other latencyRecorder) {
+	if o, ok := other.(*hdrRecorder); ok {
+		r.histogram.Merge(o.histogram)
+	}
+}
+
+// Marshal encodes the histogram in the compressed HdrHistogram V2 format
+func (r *hdrRecorder) Marshal() ([]byte, error) {
+	encoded, err := r.histogram.Encode(hdrhistogram.V2CompressedEncodingCookieBase)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode histogram: %w", err)
+	}
+	return base64.StdEncoding.DecodeString(string(encoded))
+}
+
+// unmarshalHDRRecorder decodes a histogram encoded by Marshal
+func unmarshalHDRRecorder(data []byte) (*hdrRecorder, error) {
+	h, err := hdrhistogram.Decode([]byte(base64.StdEncoding.EncodeToString(data)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode histogram: %w", err)
+	}
+	return &hdrRecorder{histogram: h}, nil
+}
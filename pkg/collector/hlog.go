@@ -0,0 +1,106 @@
+package collector
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/HdrHistogram/hdrhistogram-go"
+)
+
+// HistogramLog writes interval histograms in the HdrHistogram log format
+// (.hlog) understood by HistogramLogAnalyzer, hdr-plot and similar tools.
+// Values are nanoseconds; each interval gets one line per method, tagged with
+// the method name, plus an untagged line for all methods combined.
+type HistogramLog struct {
+	file  *os.File
+	buf   *bufio.Writer
+	start time.Time
+}
+
+// NewHistogramLog creates a histogram log file
+func NewHistogramLog(path string) (*HistogramLog, error) {
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create histogram log: %w", err)
+	}
+	return &HistogramLog{file: file, buf: bufio.NewWriter(file)}, nil
+}
+
+// begin writes the log header; interval timestamps are relative to start
+func (l *HistogramLog) begin(start time.Time) error {
+	l.start = start
+	_, err := fmt.Fprintf(l.buf,
+		"#[Logged with kvstore-benchmarker]\n"+
+			"#[Histogram log format version %s]\n"+
+			"#[StartTime: %.3f (seconds since epoch), %s]\n"+
+			"\"StartTimestamp\",\"Interval_Length\",\"Interval_Max\",\"Interval_Compressed_Histogram\"\n",
+		hdrhistogram.HISTOGRAM_LOG_FORMAT_VERSION,
+		float64(start.UnixMilli())/1000.0,
+		start.Format(time.RFC3339))
+	return err
+}
+
+// writeInterval writes an interval line for a histogram, tagged unless tag is empty
+func (l *HistogramLog) writeInterval(tag string, from, to time.Time, h *hdrhistogram.Histogram) error {
+	payload, err := h.Encode(hdrhistogram.V2CompressedEncodingCookieBase)
+	if err != nil {
+		return fmt.Errorf("failed to encode interval histogram: %w", err)
+	}
+
+	if tag != "" {
+		tag = "Tag=" + tag + ","
+	}
+	_, err = fmt.Fprintf(l.buf, "%s%.3f,%.3f,%.3f,%s\n",
+		tag,
+		from.Sub(l.start).Seconds(),
+		to.Sub(from).Seconds(),
+		float64(h.Max())/float64(time.Millisecond),
+		payload)
+	return err
+}
+
+// comment writes a comment line, which log readers skip
+func (l *HistogramLog) comment(text string) error {
+	_, err := fmt.Fprintf(l.buf, "#[%s]\n", text)
+	return err
+}
+
+// writeIntervals writes the lines of one interval from per-method metrics
+func (l *HistogramLog) writeIntervals(from, to time.Time, metricsByMethod map[string]*Metrics) error {
+	methods := make([]string, 0, len(metricsByMethod))
+	for method := range metricsByMethod {
+		methods = append(methods, method)
+	}
+	sort.Strings(methods)
+
+	var all *hdrRecorder
+	for _, method := range methods {
+		rec, ok := metricsByMethod[method].recorder.(*hdrRecorder)
+		if !ok {
+			return fmt.Errorf("histogram log requires the hdr percentile engine")
+		}
+		if all == nil {
+			all = rec.empty()
+		}
+		if err := l.writeInterval(method, from, to, rec.histogram); err != nil {
+			return err
+		}
+		all.Merge(rec)
+	}
+	if err := l.writeInterval("", from, to, all.histogram); err != nil {
+		return err
+	}
+	return l.buf.Flush()
+}
+
+// Close flushes and closes the histogram log
+func (l *HistogramLog) Close() error {
+	if err := l.buf.Flush(); err != nil {
+		l.file.Close()
+		return fmt.Errorf("failed to flush histogram log: %w", err)
+	}
+	return l.file.Close()
+}
@@ -0,0 +1,180 @@
+package collector
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	apiv1 "kvstore-benchmarker/api/v1"
+)
+
+// StartIntervals begins the measured window of the run and per-interval
+// statistics. The latter are only tracked when a sink, archive, histogram
+// log, histogram store, heatmap or throughput/latency log is attached.
+func (c *Collector) StartIntervals(now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.windowStart = now
+	c.runStart = now
+	c.startPaused = c.pausedTotal()
+	if len(c.sinks) == 0 && c.archive == nil && c.hlog == nil && c.histStore == nil && c.heatmap == nil && c.curve == nil {
+		return
+	}
+	c.interval = make(map[string]*Metrics)
+	c.intervalStart = now
+	c.intervalDropped = c.DroppedByMethod()
+	if c.tagged != nil {
+		c.intervalTagged = make(map[string]map[string]*Metrics)
+	}
+
+	if c.hlog != nil {
+		if err := c.hlog.begin(now); err != nil {
+			log.Printf("Warning: failed to write histogram log header: %v", err)
+		}
+	}
+	if c.curve != nil {
+		if err := c.beginCurve(now); err != nil {
+			log.Printf("Warning: %v", err)
+		}
+	}
+	for _, s := range c.sinks {
+		if err := s.Start(now); err != nil {
+			log.Printf("Warning: %v", err)
+		}
+	}
+}
+
+// EndInterval emits the interval ending at now to every output and starts the next one
+func (c *Collector) EndInterval(now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.interval == nil {
+		return
+	}
+
+	methods := make([]string, 0, len(c.interval))
+	for method := range c.interval {
+		methods = append(methods, method)
+	}
+	sort.Strings(methods)
+
+	var progress *SinkStats
+	for _, s := range c.sinks {
+		p, ok := s.(ProgressSink)
+		if !ok {
+			continue
+		}
+		if progress == nil {
+			stats := c.progressStats(now)
+			progress = &stats
+		}
+		if err := p.RecordProgress(*progress); err != nil {
+			log.Printf("Warning: %v", err)
+		}
+	}
+
+	interval := c.sinkStats(c.interval, c.intervalStart, now, now.Sub(c.intervalStart), true)
+	interval.Tagged = c.tagStats(c.intervalTagged, true)
+	var dropped map[string]int64
+	dropped, c.intervalDropped = c.droppedSince(c.intervalDropped)
+	interval.setDropped(dropped)
+	for _, s := range c.sinks {
+		if err := s.RecordInterval(interval); err != nil {
+			log.Printf("Warning: %v", err)
+		}
+	}
+
+	var frames []*apiv1.MethodStats
+	for _, stats := range interval.Methods {
+		frames = append(frames, statsToProto(stats))
+	}
+
+	if c.archive != nil && len(frames) > 0 {
+		if err := c.archive.WriteInterval(c.intervalStart, now, frames); err != nil {
+			log.Printf("Warning: %v", err)
+		}
+	}
+
+	if c.hlog != nil && len(methods) > 0 {
+		if err := c.hlog.writeIntervals(c.intervalStart, now, c.interval); err != nil {
+			log.Printf("Warning: %v", err)
+		}
+	}
+
+	if c.histStore != nil && len(methods) > 0 {
+		if err := c.appendHistogramStore(methods, now); err != nil {
+			log.Printf("Warning: %v", err)
+		}
+	}
+
+	if c.heatmap != nil {
+		if err := c.heatmap.writeInterval(c.intervalStart, now); err != nil {
+			log.Printf("Warning: %v", err)
+		}
+	}
+
+	if c.curve != nil && len(methods) > 0 {
+		if err := c.writeCurve(c.intervalStart, now, interval.Aggregated); err != nil {
+			log.Printf("Warning: %v", err)
+		}
+	}
+
+	c.interval = make(map[string]*Metrics)
+	if c.intervalTagged != nil {
+		c.intervalTagged = make(map[string]map[string]*Metrics)
+	}
+	c.intervalStart = now
+}
+
+// appendHistogramStore appends the interval histogram of each method to the
+// histogram store and flushes it; the caller holds c.mu
+func (c *Collector) appendHistogramStore(methods []string, now time.Time) error {
+	for _, method := range methods {
+		rec, ok := c.interval[method].recorder.(*hdrRecorder)
+		if !ok {
+			return fmt.Errorf("histogram store requires the hdr percentile engine")
+		}
+		if err := c.histStore.Append(method, c.intervalStart, now, rec.histogram); err != nil {
+			return err
+		}
+	}
+	return c.histStore.Flush()
+}
+
+// MarkRampEnd marks the end of the worker ramp-up window: the current interval
+// is closed early so that no interval straddles the ramp, and the histogram log
+// records the offset of the boundary
+func (c *Collector) MarkRampEnd(now time.Time) {
+	c.EndInterval(now)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.hlog != nil && c.interval != nil {
+		text := fmt.Sprintf("Ramp-up ended at %.3f", now.Sub(c.hlog.start).Seconds())
+		if err := c.hlog.comment(text); err != nil {
+			log.Printf("Warning: failed to write histogram log comment: %v", err)
+		}
+	}
+}
+
+// addIntervalResult adds a result to the current interval; the caller holds c.mu
+func (c *Collector) addIntervalResult(result *BenchmarkResult) {
+	if c.interval == nil {
+		return
+	}
+
+	metrics, exists := c.interval[result.Method]
+	if !exists {
+		metrics = c.newMethodMetrics(result.Method)
+		c.interval[result.Method] = metrics
+	}
+	metrics.AddResult(result)
+	c.addTaggedResult(c.intervalTagged, result)
+	if c.heatmap != nil {
+		c.heatmap.add(result)
+	}
+}
@@ -0,0 +1,140 @@
+package collector
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+)
+
+// kllK is the accuracy parameter of KLL sketches. At 200 the normalized rank
+// error stays below about 1.65% with 99% confidence, uniformly across
+// percentiles, while a sketch keeps about 600 samples (a few kilobytes)
+// however many latencies it summarizes. Being a rank error, it shows as a
+// larger latency error in steep tails than in the body of the distribution.
+const kllK = 200
+
+// kllRecorder records latencies into a KLL quantile sketch: a stack of
+// compactors where level h holds samples of weight 2^h. A full level is
+// sorted and every other sample, from a random offset, is promoted to the
+// next level with twice the weight.
+type kllRecorder struct {
+	levels [][]float64
+	count  int64
+}
+
+// newKLLRecorder creates an empty KLL recorder
+func newKLLRecorder() *kllRecorder {
+	return &kllRecorder{levels: make([][]float64, 1)}
+}
+
+// capacity returns the capacity of level h, shrinking geometrically below
+// the top level
+func (r *kllRecorder) capacity(h int) int {
+	depth := len(r.levels) - 1 - h
+	return max(2, int(math.Ceil(kllK*math.Pow(2.0/3.0, float64(depth)))))
+}
+
+// Record records a latency in milliseconds
+func (r *kllRecorder) Record(ms float64) {
+	r.levels[0] = append(r.levels[0], ms)
+	r.count++
+	r.compress()
+}
+
+// compress compacts full levels until every level is within its capacity
+func (r *kllRecorder) compress() {
+	for h := 0; h < len(r.levels); h++ {
+		if len(r.levels[h]) < r.capacity(h) {
+			continue
+		}
+		if h == len(r.levels)-1 {
+			r.levels = append(r.levels, nil)
+		}
+
+		level := r.levels[h]
+		sort.Float64s(level)
+		// An odd sample out stays at its level
+		var kept []float64
+		if len(level)%2 == 1 {
+			kept = []float64{level[len(level)-1]}
+			level = level[:len(level)-1]
+		}
+		for i := rand.Intn(2); i < len(level); i += 2 {
+			r.levels[h+1] = append(r.levels[h+1], level[i])
+		}
+		r.levels[h] = kept
+	}
+}
+
+// Percentile returns the estimated latency in milliseconds at the given percentile
+func (r *kllRecorder) Percentile(p float64) float64 {
+	if r.count == 0 {
+		return 0
+	}
+
+	type weighted struct {
+		value  float64
+		weight int64
+	}
+	var items []weighted
+	var total int64
+	for h, level := range r.levels {
+		for _, v := range level {
+			items = append(items, weighted{v, 1 << h})
+			total += 1 << h
+		}
+	}
+	sort.Slice(items, func(i, j int) bool { return items[i].value < items[j].value })
+
+	target := int64(math.Ceil(p / 100 * float64(total)))
+	var cumulative int64
+	for _, item := range items {
+		cumulative += item.weight
+		if cumulative >= target {
+			return item.value
+		}
+	}
+	return items[len(items)-1].value
+}
+
+// Merge adds the samples of another KLL recorder level by level
+func (r *kllRecorder) Merge(other latencyRecorder) {
+	o, ok := other.(*kllRecorder)
+	if !ok {
+		return
+	}
+	for len(r.levels) < len(o.levels) {
+		r.levels = append(r.levels, nil)
+	}
+	for h, level := range o.levels {
+		r.levels[h] = append(r.levels[h], level...)
+	}
+	r.count += o.count
+	r.compress()
+}
+
+// kllEncoding is the checkpoint encoding of a KLL sketch
+type kllEncoding struct {
+	Levels [][]float64 `json:"levels"`
+	Count  int64       `json:"count"`
+}
+
+// Marshal encodes the levels of the sketch
+func (r *kllRecorder) Marshal() ([]byte, error) {
+	return json.Marshal(kllEncoding{Levels: r.levels, Count: r.count})
+}
+
+// unmarshalKLLRecorder decodes a sketch encoded by Marshal
+func unmarshalKLLRecorder(data []byte) (*kllRecorder, error) {
+	var enc kllEncoding
+	if err := json.Unmarshal(data, &enc); err != nil {
+		return nil, fmt.Errorf("failed to decode KLL sketch: %w", err)
+	}
+	r := &kllRecorder{levels: enc.Levels, count: enc.Count}
+	if len(r.levels) == 0 {
+		r.levels = make([][]float64, 1)
+	}
+	return r, nil
+}
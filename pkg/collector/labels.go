@@ -0,0 +1,102 @@
+package collector
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// labelName matches label names accepted by every exporter, Prometheus being
+// the strictest
+var labelName = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+// reservedLabels are names already used by CSV columns or exporter labels
+var reservedLabels = map[string]bool{
+	"timestamp":              true,
+	"offset_s":               true,
+	"method":                 true,
+	"tags":                   true,
+	"quantile":               true,
+	"job":                    true,
+	"run_id":                 true, // Set from --run-id
+	"total_ops":              true,
+	"success_ops":            true,
+	"error_ops":              true,
+	"error_rate_pct":         true,
+	"throttled_ops":          true,
+	"throttle_rate_pct":      true,
+	"throughput_ops_per_sec": true,
+	"read_mb_per_sec":        true,
+	"write_mb_per_sec":       true,
+	"wire_sent_bytes_per_op": true,
+	"wire_recv_bytes_per_op": true,
+	"dropped_ops":            true,
+	"dropped_pct":            true,
+	"overflow_ops":           true,
+	"slo_met_pct":            true,
+	"slo_violations":         true,
+	"error_codes":            true,
+}
+
+// latencyColumn matches the latency columns of the CSV in any unit, e.g.
+// avg_latency_ms or p99_9_latency_us
+var latencyColumn = regexp.MustCompile(`_latency_(ms|us|ns)$`)
+
+// Label is a static name=value dimension of the run, attached to every
+// emitted metric and result row
+type Label struct {
+	Name  string
+	Value string
+}
+
+// ParseLabels parses a comma-separated list of name=value run labels, e.g.
+// "cluster=prod,region=eu-west-1", sorted by name
+func ParseLabels(list string) ([]Label, error) {
+	var labels []Label
+	seen := make(map[string]bool)
+	for _, entry := range strings.Split(list, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		name, value, ok := strings.Cut(entry, "=")
+		name, value = strings.TrimSpace(name), strings.TrimSpace(value)
+		if !ok || name == "" || value == "" {
+			return nil, fmt.Errorf("invalid label %q (expected name=value)", entry)
+		}
+		if !labelName.MatchString(name) {
+			return nil, fmt.Errorf("invalid label name %q (letters, digits and underscores, not starting with a digit)", name)
+		}
+		if reservedLabels[name] || latencyColumn.MatchString(name) || strings.HasPrefix(name, "__") {
+			return nil, fmt.Errorf("label name %q is reserved", name)
+		}
+		if strings.ContainsAny(value, tagSeparators+"~ \t") {
+			return nil, fmt.Errorf("invalid label %q: values cannot contain whitespace or any of %s~", entry, tagSeparators)
+		}
+		if seen[name] {
+			return nil, fmt.Errorf("duplicate label %q", name)
+		}
+		seen[name] = true
+		labels = append(labels, Label{Name: name, Value: value})
+	}
+	sort.Slice(labels, func(i, j int) bool { return labels[i].Name < labels[j].Name })
+	return labels, nil
+}
+
+// Labels returns the run labels, sorted by name
+func (c *Collector) Labels() []Label {
+	return c.labels
+}
+
+// labelMap returns labels as a map, nil if there are none
+func labelMap(labels []Label) map[string]string {
+	if len(labels) == 0 {
+		return nil
+	}
+	m := make(map[string]string, len(labels))
+	for _, label := range labels {
+		m[label.Name] = label.Value
+	}
+	return m
+}
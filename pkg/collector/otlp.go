@@ -0,0 +1,189 @@
+package collector
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	collectormetricspb "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	metricspb "go.opentelemetry.io/proto/otlp/metrics/v1"
+	resourcepb "go.opentelemetry.io/proto/otlp/resource/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+// OTLPExporter periodically pushes collector statistics to an OpenTelemetry
+// collector using OTLP/HTTP with protobuf encoding
+type OTLPExporter struct {
+	collector *Collector
+	url       string
+	runID     string
+	start     time.Time
+	client    *http.Client
+	pusher    *intervalPusher
+}
+
+// NewOTLPExporter creates an exporter pushing to endpoint every interval. The
+// endpoint is the collector base URL (e.g. http://otel:4318); the standard
+// /v1/metrics path is appended unless already present.
+func NewOTLPExporter(c *Collector, endpoint, runID string, interval time.Duration) *OTLPExporter {
+	url := strings.TrimSuffix(endpoint, "/")
+	if !strings.HasSuffix(url, "/v1/metrics") {
+		url += "/v1/metrics"
+	}
+
+	e := &OTLPExporter{
+		collector: c,
+		url:       url,
+		runID:     runID,
+		start:     time.Now(),
+		client:    &http.Client{Timeout: 10 * time.Second},
+	}
+	e.pusher = newIntervalPusher("OTLP", interval, c.alignIntervals, e.push)
+	return e
+}
+
+// Start starts the push loop
+func (e *OTLPExporter) Start(ctx context.Context) {
+	e.pusher.start(ctx)
+}
+
+// Stop stops the push loop and pushes the final statistics
+func (e *OTLPExporter) Stop() {
+	e.pusher.stop()
+}
+
+// push sends the current statistics as a single export request
+func (e *OTLPExporter) push(now time.Time) error {
+	stats := e.collector.GetStats()
+	if len(stats) == 0 {
+		return nil
+	}
+
+	data, err := proto.Marshal(e.request(stats, now))
+	if err != nil {
+		return fmt.Errorf("failed to encode export request: %w", err)
+	}
+
+	httpReq, err := http.NewRequest(http.MethodPost, e.url, bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/x-protobuf")
+
+	resp, err := e.client.Do(httpReq)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("endpoint returned %s: %s", resp.Status, bytes.TrimSpace(body))
+	}
+	return nil
+}
+
+// request builds an export request with cumulative operation and error
+// counters and a latency summary per method
+func (e *OTLPExporter) request(stats map[string]Stats, now time.Time) *collectormetricspb.ExportMetricsServiceRequest {
+	methods := make([]string, 0, len(stats))
+	for method, stat := range stats {
+		if stat.Count > 0 {
+			methods = append(methods, method)
+		}
+	}
+	sort.Strings(methods)
+
+	start := uint64(e.start.UnixNano())
+	ts := uint64(now.UnixNano())
+
+	var operations, errors []*metricspb.NumberDataPoint
+	var latencies []*metricspb.SummaryDataPoint
+	for _, method := range methods {
+		s := stats[method]
+		attrs := []*commonpb.KeyValue{stringAttr("method", method)}
+
+		operations = append(operations, &metricspb.NumberDataPoint{
+			Attributes:        attrs,
+			StartTimeUnixNano: start,
+			TimeUnixNano:      ts,
+			Value:             &metricspb.NumberDataPoint_AsInt{AsInt: s.Count},
+		})
+		errors = append(errors, &metricspb.NumberDataPoint{
+			Attributes:        attrs,
+			StartTimeUnixNano: start,
+			TimeUnixNano:      ts,
+			Value:             &metricspb.NumberDataPoint_AsInt{AsInt: s.ErrorCount},
+		})
+		latencies = append(latencies, &metricspb.SummaryDataPoint{
+			Attributes:        attrs,
+			StartTimeUnixNano: start,
+			TimeUnixNano:      ts,
+			Count:             uint64(s.Successes()),
+			Sum:               s.AvgLatency * float64(s.Successes()),
+			QuantileValues: []*metricspb.SummaryDataPoint_ValueAtQuantile{
+				{Quantile: 0, Value: s.MinLatency},
+				{Quantile: 0.5, Value: s.P50Latency},
+				{Quantile: 0.95, Value: s.P95Latency},
+				{Quantile: 0.99, Value: s.P99Latency},
+				{Quantile: 0.999, Value: s.P999Latency},
+				{Quantile: 1, Value: s.MaxLatency},
+			},
+		})
+	}
+
+	counter := func(name, description string, points []*metricspb.NumberDataPoint) *metricspb.Metric {
+		return &metricspb.Metric{
+			Name:        name,
+			Description: description,
+			Unit:        "{operation}",
+			Data: &metricspb.Metric_Sum{Sum: &metricspb.Sum{
+				DataPoints:             points,
+				AggregationTemporality: metricspb.AggregationTemporality_AGGREGATION_TEMPORALITY_CUMULATIVE,
+				IsMonotonic:            true,
+			}},
+		}
+	}
+
+	return &collectormetricspb.ExportMetricsServiceRequest{
+		ResourceMetrics: []*metricspb.ResourceMetrics{{
+			Resource: &resourcepb.Resource{Attributes: e.resourceAttributes()},
+			ScopeMetrics: []*metricspb.ScopeMetrics{{
+				Scope: &commonpb.InstrumentationScope{Name: "kvstore-benchmarker/pkg/collector"},
+				Metrics: []*metricspb.Metric{
+					counter("kvbench.operations", "Total operations issued.", operations),
+					counter("kvbench.errors", "Total failed operations.", errors),
+					{
+						Name:        "kvbench.latency",
+						Description: "Latency of successful operations.",
+						Unit:        "ms",
+						Data:        &metricspb.Metric_Summary{Summary: &metricspb.Summary{DataPoints: latencies}},
+					},
+				},
+			}},
+		}},
+	}
+}
+
+// stringAttr builds a string-valued OTLP attribute
+func stringAttr(key, value string) *commonpb.KeyValue {
+	return &commonpb.KeyValue{Key: key, Value: &commonpb.AnyValue{Value: &commonpb.AnyValue_StringValue{StringValue: value}}}
+}
+
+// resourceAttributes describes the run: the service, run ID and run labels
+func (e *OTLPExporter) resourceAttributes() []*commonpb.KeyValue {
+	attrs := []*commonpb.KeyValue{
+		stringAttr("service.name", "kvstore-benchmarker"),
+		stringAttr("kvbench.run_id", e.runID),
+	}
+	for _, label := range e.collector.Labels() {
+		attrs = append(attrs, stringAttr(label.Name, label.Value))
+	}
+	return attrs
+}
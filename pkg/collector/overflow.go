@@ -0,0 +1,25 @@
+package collector
+
+import "time"
+
+// msOf converts a duration into milliseconds
+func msOf(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
+
+// record adds a successful latency to the distribution. Latencies above the
+// bound are counted as overflow and recorded at the bound, so that outliers
+// neither exceed the histogram range nor vanish from the percentiles. The
+// caller holds m.mu.
+func (m *Metrics) record(ms float64) {
+	if m.latencyMax > 0 && ms > m.latencyMax {
+		m.Overflow++
+		ms = m.latencyMax
+	}
+	m.recorder.Record(ms)
+}
+
+// LatencyMax returns the highest latency recorded as measured
+func (c *Collector) LatencyMax() time.Duration {
+	return time.Duration(c.latencyMax * float64(time.Millisecond))
+}
@@ -0,0 +1,187 @@
+package collector
+
+import (
+	"fmt"
+	"time"
+
+	"kvstore-benchmarker/pkg/parquet"
+)
+
+// ParquetSink writes the per-method and aggregated statistics of every
+// report interval as rows of a Parquet file, one column per statistic.
+// Latencies are always in milliseconds.
+type ParquetSink struct {
+	writer *parquet.Writer
+	pcts   []float64
+	labels []Label
+}
+
+// NewParquetSink creates the interval statistics Parquet file at path, with
+// a latency column per percentile and a column per run label
+func NewParquetSink(path string, percentiles []float64, labels []Label) (*ParquetSink, error) {
+	columns := []parquet.Column{
+		{Name: "timestamp", Type: parquet.Timestamp},
+		{Name: "offset_s", Type: parquet.Double},
+		{Name: "method", Type: parquet.String},
+	}
+	for _, label := range labels {
+		columns = append(columns, parquet.Column{Name: label.Name, Type: parquet.String})
+	}
+	columns = append(columns,
+		parquet.Column{Name: "total_ops", Type: parquet.Int64},
+		parquet.Column{Name: "success_ops", Type: parquet.Int64},
+		parquet.Column{Name: "error_ops", Type: parquet.Int64},
+		parquet.Column{Name: "throttled_ops", Type: parquet.Int64},
+		parquet.Column{Name: "dropped_ops", Type: parquet.Int64},
+		parquet.Column{Name: "overflow_ops", Type: parquet.Int64},
+		parquet.Column{Name: "throughput_ops_per_sec", Type: parquet.Double},
+		parquet.Column{Name: "avg_latency_ms", Type: parquet.Double},
+	)
+	for _, p := range percentiles {
+		columns = append(columns, parquet.Column{Name: percentileColumn(p) + "_ms", Type: parquet.Double})
+	}
+	columns = append(columns,
+		parquet.Column{Name: "min_latency_ms", Type: parquet.Double},
+		parquet.Column{Name: "max_latency_ms", Type: parquet.Double},
+		parquet.Column{Name: "bytes_sent", Type: parquet.Int64},
+		parquet.Column{Name: "bytes_recv", Type: parquet.Int64},
+	)
+
+	w, err := parquet.Create(path, columns)
+	if err != nil {
+		return nil, err
+	}
+	return &ParquetSink{writer: w, pcts: percentiles, labels: labels}, nil
+}
+
+// Start does nothing; rows are written per interval
+func (s *ParquetSink) Start(time.Time) error {
+	return nil
+}
+
+// RecordInterval writes a row per method and the aggregated row of an interval
+func (s *ParquetSink) RecordInterval(interval SinkStats) error {
+	if len(interval.Methods) == 0 {
+		return nil
+	}
+	for _, stats := range interval.Methods {
+		if err := s.write(interval, stats); err != nil {
+			return err
+		}
+	}
+	return s.write(interval, interval.Aggregated)
+}
+
+// write writes the row of the statistics of a method in an interval
+func (s *ParquetSink) write(interval SinkStats, stats Stats) error {
+	if err := s.writer.Write(s.row(interval, stats)...); err != nil {
+		return fmt.Errorf("failed to write Parquet interval row: %w", err)
+	}
+	return nil
+}
+
+// row returns the column values of the statistics of a method in an interval
+func (s *ParquetSink) row(interval SinkStats, stats Stats) []any {
+	row := []any{interval.End, interval.Offset.Seconds(), stats.Method}
+	for _, label := range s.labels {
+		row = append(row, label.Value)
+	}
+	row = append(row,
+		stats.Count,
+		stats.Successes(),
+		stats.ErrorCount,
+		stats.Throttled,
+		stats.Dropped,
+		stats.Overflow,
+		interval.Throughput(stats),
+		stats.AvgLatency,
+	)
+	for i := range s.pcts {
+		row = append(row, stats.Percentile(i))
+	}
+	return append(row, stats.MinLatency, stats.MaxLatency, stats.BytesSent, stats.BytesRecv)
+}
+
+// RecordFinal does nothing; the file holds interval rows only
+func (s *ParquetSink) RecordFinal(SinkStats) error {
+	return nil
+}
+
+// Close writes the remaining rows and the file footer
+func (s *ParquetSink) Close() error {
+	if err := s.writer.Close(); err != nil {
+		return fmt.Errorf("failed to close Parquet intervals: %w", err)
+	}
+	return nil
+}
+
+// ParquetRawLog writes operation results as rows of a Parquet file, every
+// result or one in every sampleEvery, like the JSON lines raw log
+type ParquetRawLog struct {
+	writer      *parquet.Writer
+	sampleEvery int64
+	seen        int64
+	labels      []Label
+}
+
+// NewParquetRawLog creates a raw result Parquet file at path that keeps one
+// result in every sampleEvery (every result if below 2), with a column per
+// run label
+func NewParquetRawLog(path string, sampleEvery int, labels []Label) (*ParquetRawLog, error) {
+	columns := []parquet.Column{
+		{Name: "timestamp", Type: parquet.Timestamp},
+		{Name: "offset_ns", Type: parquet.Int64},
+		{Name: "method", Type: parquet.String},
+		{Name: "latency_ns", Type: parquet.Int64},
+		{Name: "error", Type: parquet.String},
+		{Name: "worker", Type: parquet.Int64},
+		{Name: "agent", Type: parquet.String},
+	}
+	for _, label := range labels {
+		columns = append(columns, parquet.Column{Name: label.Name, Type: parquet.String})
+	}
+
+	w, err := parquet.Create(path, columns)
+	if err != nil {
+		return nil, err
+	}
+	return &ParquetRawLog{writer: w, sampleEvery: int64(max(sampleEvery, 1)), labels: labels}, nil
+}
+
+// Write appends a result, issued offset after the start of the run, unless it
+// is sampled out; it is called from the collector goroutine only
+func (l *ParquetRawLog) Write(result *BenchmarkResult, offset time.Duration) error {
+	l.seen++
+	if (l.seen-1)%l.sampleEvery != 0 {
+		return nil
+	}
+
+	var errText string
+	if result.Error != nil {
+		errText = result.Error.Error()
+	}
+	row := []any{
+		result.Timestamp,
+		offset.Nanoseconds(),
+		result.Method,
+		int64(result.LatencyMs * float64(time.Millisecond)),
+		errText,
+		result.Worker,
+		result.Agent,
+	}
+	for _, label := range l.labels {
+		row = append(row, label.Value)
+	}
+	if err := l.writer.Write(row...); err != nil {
+		return fmt.Errorf("failed to write Parquet raw result: %w", err)
+	}
+	return nil
+}
+
+// Close writes the remaining rows and the file footer
+func (l *ParquetRawLog) Close() error {
+	if err := l.writer.Close(); err != nil {
+		return fmt.Errorf("failed to close Parquet raw log: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,55 @@
+package collector
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// DefaultPercentiles are the percentiles reported when none are configured
+var DefaultPercentiles = []float64{50, 95, 99, 99.9}
+
+// ParsePercentiles parses a comma-separated list of percentiles such as
+// "50,90,99,99.9,99.99"; they are reported in the order given
+func ParsePercentiles(list string) ([]float64, error) {
+	var percentiles []float64
+	seen := make(map[float64]bool)
+	for _, item := range strings.Split(list, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		p, err := strconv.ParseFloat(item, 64)
+		if err != nil || p <= 0 || p > 100 {
+			return nil, fmt.Errorf("invalid percentile %q (expected a number in (0, 100])", item)
+		}
+		if seen[p] {
+			return nil, fmt.Errorf("duplicate percentile %q", item)
+		}
+		seen[p] = true
+		percentiles = append(percentiles, p)
+	}
+	if len(percentiles) == 0 {
+		return nil, fmt.Errorf("at least one percentile is required")
+	}
+	return percentiles, nil
+}
+
+// PercentileLabel renders a percentile for display, e.g. "P99.9"
+func PercentileLabel(p float64) string {
+	return "P" + strconv.FormatFloat(p, 'f', -1, 64)
+}
+
+// percentileColumn names the CSV column of a percentile, e.g. "p99_9_latency"
+func percentileColumn(p float64) string {
+	return "p" + strings.ReplaceAll(strconv.FormatFloat(p, 'f', -1, 64), ".", "_") + "_latency"
+}
+
+// percentilesOf queries a recorder at each of the percentiles
+func percentilesOf(recorder latencyRecorder, percentiles []float64) []float64 {
+	values := make([]float64, len(percentiles))
+	for i, p := range percentiles {
+		values[i] = recorder.Percentile(p)
+	}
+	return values
+}
@@ -0,0 +1,60 @@
+package collector
+
+import "time"
+
+// phaseMetrics holds the metrics of results completed within a named phase
+type phaseMetrics struct {
+	name    string
+	start   time.Time
+	metrics map[string]*Metrics
+}
+
+// SetPhases splits subsequent results into named phases: phase i covers the
+// results completed from starts[i] until the next phase starts. Starts must
+// be in ascending order; results before the first start belong to no phase.
+func (c *Collector) SetPhases(names []string, starts []time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.phases = make([]*phaseMetrics, len(names))
+	for i, name := range names {
+		c.phases[i] = &phaseMetrics{name: name, start: starts[i], metrics: make(map[string]*Metrics)}
+	}
+}
+
+// addPhaseResult adds a result to the phase it completed in; the caller holds c.mu
+func (c *Collector) addPhaseResult(result *BenchmarkResult) {
+	for i := len(c.phases) - 1; i >= 0; i-- {
+		phase := c.phases[i]
+		if result.Timestamp.Before(phase.start) {
+			continue
+		}
+
+		metrics, exists := phase.metrics[result.Method]
+		if !exists {
+			metrics = c.newMethodMetrics(result.Method)
+			phase.metrics[result.Method] = metrics
+		}
+		metrics.AddResult(result)
+		return
+	}
+}
+
+// GetPhaseStats returns the per-method and aggregated statistics of a phase,
+// and false if no such phase was set
+func (c *Collector) GetPhaseStats(name string) (map[string]Stats, Stats, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	for _, phase := range c.phases {
+		if phase.name != name {
+			continue
+		}
+		stats := make(map[string]Stats, len(phase.metrics))
+		for method, metrics := range phase.metrics {
+			stats[method] = metrics.getStats(c.final())
+		}
+		return stats, c.aggregateMetrics(phase.metrics, c.final()), true
+	}
+	return nil, Stats{}, false
+}
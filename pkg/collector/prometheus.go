@@ -0,0 +1,58 @@
+package collector
+
+import "sort"
+
+// promSample is a single Prometheus metric value derived from method statistics
+type promSample struct {
+	name     string
+	help     string
+	kind     string // Prometheus metric type: counter or gauge
+	method   string
+	quantile string
+	value    float64
+}
+
+// prometheusSamples converts per-method statistics into Prometheus samples,
+// ordered by metric name and method
+func prometheusSamples(stats map[string]Stats) []promSample {
+	methods := make([]string, 0, len(stats))
+	for method, stat := range stats {
+		if stat.Count > 0 {
+			methods = append(methods, method)
+		}
+	}
+	sort.Strings(methods)
+
+	var samples []promSample
+	add := func(name, help, kind, quantile string, value func(Stats) float64) {
+		for _, method := range methods {
+			samples = append(samples, promSample{
+				name:     name,
+				help:     help,
+				kind:     kind,
+				method:   method,
+				quantile: quantile,
+				value:    value(stats[method]),
+			})
+		}
+	}
+
+	add("kvbench_operations_total", "Total operations issued.", "counter", "",
+		func(s Stats) float64 { return float64(s.Count) })
+	add("kvbench_errors_total", "Total failed operations.", "counter", "",
+		func(s Stats) float64 { return float64(s.ErrorCount) })
+	add("kvbench_latency_avg_ms", "Average latency of successful operations in milliseconds.", "gauge", "",
+		func(s Stats) float64 { return s.AvgLatency })
+	add("kvbench_latency_min_ms", "Minimum latency of successful operations in milliseconds.", "gauge", "",
+		func(s Stats) float64 { return s.MinLatency })
+	add("kvbench_latency_max_ms", "Maximum latency of successful operations in milliseconds.", "gauge", "",
+		func(s Stats) float64 { return s.MaxLatency })
+	add("kvbench_latency_ms", "Latency percentiles of successful operations in milliseconds.", "gauge", "0.5",
+		func(s Stats) float64 { return s.P50Latency })
+	add("kvbench_latency_ms", "Latency percentiles of successful operations in milliseconds.", "gauge", "0.95",
+		func(s Stats) float64 { return s.P95Latency })
+	add("kvbench_latency_ms", "Latency percentiles of successful operations in milliseconds.", "gauge", "0.99",
+		func(s Stats) float64 { return s.P99Latency })
+
+	return samples
+}
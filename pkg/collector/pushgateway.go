@@ -0,0 +1,67 @@
+package collector
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// PushToGateway pushes the final per-method statistics to a Prometheus
+// Pushgateway, grouped by job and run ID and labeled with the run labels. An
+// existing group with the same labels is replaced.
+func PushToGateway(c *Collector, gatewayURL, job, runID string) error {
+	body := formatExposition(prometheusSamples(c.GetStats()), c.Labels())
+
+	target := strings.TrimRight(gatewayURL, "/") + "/metrics/job/" + url.PathEscape(job)
+	if runID != "" {
+		target += "/run_id/" + url.PathEscape(runID)
+	}
+
+	req, err := http.NewRequest(http.MethodPut, target, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "text/plain; version=0.0.4")
+
+	client := &http.Client{Timeout: 10 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to push to gateway: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("gateway returned %s: %s", resp.Status, bytes.TrimSpace(msg))
+	}
+	return nil
+}
+
+// formatExposition renders samples in the Prometheus text exposition format,
+// with the given labels on every sample
+func formatExposition(samples []promSample, labels []Label) []byte {
+	var extra strings.Builder
+	for _, label := range labels {
+		fmt.Fprintf(&extra, ",%s=%q", label.Name, label.Value)
+	}
+
+	var buf bytes.Buffer
+	lastName := ""
+	for _, sample := range samples {
+		if sample.name != lastName {
+			fmt.Fprintf(&buf, "# HELP %s %s\n", sample.name, sample.help)
+			fmt.Fprintf(&buf, "# TYPE %s %s\n", sample.name, sample.kind)
+			lastName = sample.name
+		}
+		if sample.quantile != "" {
+			fmt.Fprintf(&buf, "%s{method=%q,quantile=%q%s} %g\n", sample.name, sample.method, sample.quantile, extra.String(), sample.value)
+		} else {
+			fmt.Fprintf(&buf, "%s{method=%q%s} %g\n", sample.name, sample.method, extra.String(), sample.value)
+		}
+	}
+	return buf.Bytes()
+}
@@ -0,0 +1,86 @@
+package collector
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+)
+
+// RawLog streams operation results as JSON lines, every result or one in
+// every sampleEvery
+type RawLog struct {
+	file        *os.File
+	buf         *bufio.Writer
+	enc         *json.Encoder
+	sampleEvery int64
+	seen        int64
+	labels      map[string]string
+}
+
+// rawRecord is the JSON representation of a single operation result
+type rawRecord struct {
+	Timestamp string            `json:"timestamp"`
+	OffsetNs  int64             `json:"offset_ns"` // Since the start of the run, on the monotonic clock
+	Method    string            `json:"method"`
+	LatencyNs int64             `json:"latency_ns"`
+	Error     string            `json:"error,omitempty"`
+	Worker    int               `json:"worker"`
+	Agent     string            `json:"agent,omitempty"`
+	Labels    map[string]string `json:"labels,omitempty"`
+}
+
+// NewRawLog creates a raw result log writing to path, or stdout if path is "-",
+// that keeps one result in every sampleEvery (every result if below 2) and
+// attaches the run labels to every record. path may be a named pipe.
+func NewRawLog(path string, sampleEvery int, labels []Label) (*RawLog, error) {
+	file := os.Stdout
+	if path != "-" {
+		var err error
+		file, err = os.Create(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create raw result log: %w", err)
+		}
+	}
+
+	buf := bufio.NewWriterSize(file, 64*1024)
+	return &RawLog{file: file, buf: buf, enc: json.NewEncoder(buf), sampleEvery: int64(max(sampleEvery, 1)), labels: labelMap(labels)}, nil
+}
+
+// Write appends a result, issued offset after the start of the run, unless it
+// is sampled out; it is called from the collector goroutine only
+func (l *RawLog) Write(result *BenchmarkResult, offset time.Duration) error {
+	l.seen++
+	if (l.seen-1)%l.sampleEvery != 0 {
+		return nil
+	}
+
+	record := rawRecord{
+		Timestamp: result.Timestamp.UTC().Format(time.RFC3339Nano),
+		OffsetNs:  offset.Nanoseconds(),
+		Method:    result.Method,
+		LatencyNs: int64(result.LatencyMs * float64(time.Millisecond)),
+		Worker:    result.Worker,
+		Agent:     result.Agent,
+		Labels:    l.labels,
+	}
+	if result.Error != nil {
+		record.Error = result.Error.Error()
+	}
+	if err := l.enc.Encode(&record); err != nil {
+		return fmt.Errorf("failed to write raw result: %w", err)
+	}
+	return nil
+}
+
+// Close flushes buffered records and closes the output
+func (l *RawLog) Close() error {
+	if err := l.buf.Flush(); err != nil {
+		return fmt.Errorf("failed to flush raw result log: %w", err)
+	}
+	if l.file == os.Stdout {
+		return nil
+	}
+	return l.file.Close()
+}
@@ -0,0 +1,70 @@
+package collector
+
+import "fmt"
+
+// Percentile engines selectable for latency recording
+const (
+	EngineHDR       = "hdr"
+	EngineTDigest   = "tdigest"
+	EngineExact     = "exact"
+	EngineKLL       = "kll"
+	EngineReservoir = "reservoir"
+)
+
+// latencyRecorder tracks the latency distribution of successful operations.
+// Implementations are not safe for concurrent use.
+type latencyRecorder interface {
+	// Record adds a latency in milliseconds
+	Record(ms float64)
+	// Percentile returns the latency in milliseconds at percentile p (0-100)
+	Percentile(p float64) float64
+	// Merge adds all latencies of another recorder of the same engine
+	Merge(other latencyRecorder)
+	// Marshal encodes the recorded distribution for checkpoints
+	Marshal() ([]byte, error)
+}
+
+// ParseEngine validates a percentile engine name
+func ParseEngine(name string) (string, error) {
+	switch name {
+	case "", EngineHDR:
+		return EngineHDR, nil
+	case EngineTDigest, EngineExact, EngineKLL, EngineReservoir:
+		return name, nil
+	default:
+		return "", fmt.Errorf("unknown percentile engine %q (expected hdr, tdigest, exact, kll or reservoir)", name)
+	}
+}
+
+// newLatencyRecorder creates an empty recorder for the given engine; maxMs
+// is the latency bound in milliseconds that histograms are sized for
+func newLatencyRecorder(engine string, maxMs float64) latencyRecorder {
+	switch engine {
+	case EngineTDigest:
+		return newTDigestRecorder()
+	case EngineExact:
+		return newExactRecorder(maxMs)
+	case EngineKLL:
+		return newKLLRecorder()
+	case EngineReservoir:
+		return newReservoirRecorder()
+	default:
+		return newHDRRecorder(maxMs)
+	}
+}
+
+// unmarshalRecorder decodes a distribution encoded by Marshal
+func unmarshalRecorder(engine string, data []byte, maxMs float64) (latencyRecorder, error) {
+	switch engine {
+	case EngineTDigest:
+		return unmarshalTDigestRecorder(data)
+	case EngineExact:
+		return unmarshalExactRecorder(data, maxMs)
+	case EngineKLL:
+		return unmarshalKLLRecorder(data)
+	case EngineReservoir:
+		return unmarshalReservoirRecorder(data)
+	default:
+		return unmarshalHDRRecorder(data)
+	}
+}
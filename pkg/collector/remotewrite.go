@@ -0,0 +1,107 @@
+package collector
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/golang/snappy"
+	"google.golang.org/protobuf/proto"
+
+	"kvstore-benchmarker/internal/prompb"
+)
+
+// RemoteWriteExporter periodically pushes collector statistics to a
+// Prometheus remote-write endpoint
+type RemoteWriteExporter struct {
+	collector *Collector
+	url       string
+	client    *http.Client
+	pusher    *intervalPusher
+}
+
+// NewRemoteWriteExporter creates an exporter pushing to url every interval
+func NewRemoteWriteExporter(c *Collector, url string, interval time.Duration) *RemoteWriteExporter {
+	e := &RemoteWriteExporter{
+		collector: c,
+		url:       url,
+		client:    &http.Client{Timeout: 10 * time.Second},
+	}
+	e.pusher = newIntervalPusher("remote-write", interval, c.alignIntervals, e.push)
+	return e
+}
+
+// Start starts the push loop
+func (e *RemoteWriteExporter) Start(ctx context.Context) {
+	e.pusher.start(ctx)
+}
+
+// Stop stops the push loop and pushes the final statistics
+func (e *RemoteWriteExporter) Stop() {
+	e.pusher.stop()
+}
+
+// push sends the current statistics as a single remote-write request
+func (e *RemoteWriteExporter) push(now time.Time) error {
+	stats := e.collector.GetStats()
+	if len(stats) == 0 {
+		return nil
+	}
+
+	timestamp := now.UnixMilli()
+	req := &prompb.WriteRequest{}
+	for _, sample := range prometheusSamples(stats) {
+		req.Timeseries = append(req.Timeseries, series(sample, timestamp, e.collector.Labels()))
+	}
+
+	data, err := proto.Marshal(req)
+	if err != nil {
+		return fmt.Errorf("failed to encode write request: %w", err)
+	}
+
+	httpReq, err := http.NewRequest(http.MethodPost, e.url, bytes.NewReader(snappy.Encode(nil, data)))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	httpReq.Header.Set("Content-Encoding", "snappy")
+	httpReq.Header.Set("Content-Type", "application/x-protobuf")
+	httpReq.Header.Set("X-Prometheus-Remote-Write-Version", "0.1.0")
+
+	resp, err := e.client.Do(httpReq)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("endpoint returned %s: %s", resp.Status, bytes.TrimSpace(body))
+	}
+	return nil
+}
+
+// series builds a single-sample remote-write time series carrying the run labels
+func series(sample promSample, timestamp int64, runLabels []Label) *prompb.TimeSeries {
+	labels := []*prompb.Label{
+		{Name: "__name__", Value: sample.name},
+		{Name: "job", Value: "kvstore-benchmarker"},
+		{Name: "method", Value: sample.method},
+	}
+	if sample.quantile != "" {
+		labels = append(labels, &prompb.Label{Name: "quantile", Value: sample.quantile})
+	}
+	for _, label := range runLabels {
+		labels = append(labels, &prompb.Label{Name: label.Name, Value: label.Value})
+	}
+	// Labels must be sorted by name
+	sort.Slice(labels, func(i, j int) bool { return labels[i].Name < labels[j].Name })
+
+	return &prompb.TimeSeries{
+		Labels:  labels,
+		Samples: []*prompb.Sample{{Value: sample.value, Timestamp: timestamp}},
+	}
+}
@@ -0,0 +1,126 @@
+package collector
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+)
+
+// reservoirSize is the number of latencies a reservoir recorder keeps per
+// method, 512 KB of samples. Every latency of the run has the same chance of
+// being kept, so the standard rank error of a percentile p is at most
+// sqrt(p(1-p)/reservoirSize): about 0.2% at the median and 0.04% at p99.
+const reservoirSize = 1 << 16
+
+// reservoirRecorder keeps a uniform random sample of all latencies recorded,
+// using Vitter's algorithm R: the first reservoirSize latencies are kept and
+// the n-th latency after them replaces a random sample with probability
+// reservoirSize/n. Unlike keeping the most recent latencies, percentiles are
+// not biased toward the end of the run.
+type reservoirRecorder struct {
+	samples []float64
+	count   int64 // Latencies recorded, kept or not
+	sorted  bool
+}
+
+// newReservoirRecorder creates an empty reservoir recorder
+func newReservoirRecorder() *reservoirRecorder {
+	return &reservoirRecorder{sorted: true}
+}
+
+// Record records a latency in milliseconds
+func (r *reservoirRecorder) Record(ms float64) {
+	r.count++
+	if len(r.samples) < reservoirSize {
+		r.samples = append(r.samples, ms)
+		r.sorted = false
+		return
+	}
+	if i := rand.Int63n(r.count); i < reservoirSize {
+		r.samples[i] = ms
+		r.sorted = false
+	}
+}
+
+// Percentile returns the estimated latency in milliseconds at the given
+// percentile, the nearest-rank percentile of the sample
+func (r *reservoirRecorder) Percentile(p float64) float64 {
+	if len(r.samples) == 0 {
+		return 0
+	}
+	// Slots are chosen at random, so the order of the samples is free
+	if !r.sorted {
+		sort.Float64s(r.samples)
+		r.sorted = true
+	}
+	rank := int(math.Ceil(p/100*float64(len(r.samples)))) - 1
+	return r.samples[min(max(rank, 0), len(r.samples)-1)]
+}
+
+// Merge combines the sample of another reservoir recorder into a uniform
+// sample of both. Each slot is drawn from either sample in proportion to the
+// latencies it still stands for, so a reservoir that saw more latencies
+// contributes more samples.
+func (r *reservoirRecorder) Merge(other latencyRecorder) {
+	o, ok := other.(*reservoirRecorder)
+	if !ok || o.count == 0 {
+		return
+	}
+	total := r.count + o.count
+	if len(r.samples)+len(o.samples) <= reservoirSize && r.count == int64(len(r.samples)) && o.count == int64(len(o.samples)) {
+		// Neither has discarded a latency yet: the union is exact
+		r.samples = append(r.samples, o.samples...)
+		r.count = total
+		r.sorted = false
+		return
+	}
+
+	a, b := shuffled(r.samples), shuffled(o.samples)
+	weightA := float64(r.count) / float64(max(len(a), 1))
+	weightB := float64(o.count) / float64(max(len(b), 1))
+	remainingA, remainingB := float64(r.count), float64(o.count)
+	merged := make([]float64, 0, min(reservoirSize, len(a)+len(b)))
+	for len(merged) < cap(merged) && (len(a) > 0 || len(b) > 0) {
+		if len(b) == 0 || (len(a) > 0 && rand.Float64()*(remainingA+remainingB) < remainingA) {
+			merged = append(merged, a[0])
+			a = a[1:]
+			remainingA -= weightA
+		} else {
+			merged = append(merged, b[0])
+			b = b[1:]
+			remainingB -= weightB
+		}
+	}
+	r.samples = merged
+	r.count = total
+	r.sorted = false
+}
+
+// shuffled returns a randomly permuted copy of samples
+func shuffled(samples []float64) []float64 {
+	out := append([]float64(nil), samples...)
+	rand.Shuffle(len(out), func(i, j int) { out[i], out[j] = out[j], out[i] })
+	return out
+}
+
+// reservoirEncoding is the checkpoint encoding of a reservoir recorder
+type reservoirEncoding struct {
+	Samples []float64 `json:"samples,omitempty"`
+	Count   int64     `json:"count"`
+}
+
+// Marshal encodes the sample and the number of latencies it stands for
+func (r *reservoirRecorder) Marshal() ([]byte, error) {
+	return json.Marshal(reservoirEncoding{Samples: r.samples, Count: r.count})
+}
+
+// unmarshalReservoirRecorder decodes a recorder encoded by Marshal
+func unmarshalReservoirRecorder(data []byte) (*reservoirRecorder, error) {
+	var enc reservoirEncoding
+	if err := json.Unmarshal(data, &enc); err != nil {
+		return nil, fmt.Errorf("failed to decode reservoir sample: %w", err)
+	}
+	return &reservoirRecorder{samples: enc.Samples, count: enc.Count}, nil
+}
@@ -0,0 +1,44 @@
+package collector
+
+import "sort"
+
+// DefaultTrimPct is the default share of operations, in percent, trimmed
+// from each end of the distribution for the trimmed mean
+const DefaultTrimPct = 5.0
+
+// robustPoints is the number of quantiles the robust statistics are
+// evaluated at, resolving the distribution in steps of 0.5%
+const robustPoints = 200
+
+// robustStats returns the trimmed mean of a latency distribution, leaving out
+// trimPct percent of the operations at each end, and its median absolute
+// deviation. Both are evaluated from evenly spaced quantiles, so they work
+// with every percentile engine at the engine's accuracy; a single blip of a
+// few seconds moves neither.
+func robustStats(r latencyRecorder, trimPct float64) (trimmedMean, mad float64) {
+	step := (100 - 2*trimPct) / robustPoints
+	var sum float64
+	for i := 0; i < robustPoints; i++ {
+		sum += r.Percentile(trimPct + (float64(i)+0.5)*step)
+	}
+	trimmedMean = sum / robustPoints
+
+	median := r.Percentile(50)
+	deviations := make([]float64, robustPoints)
+	for i := range deviations {
+		deviation := r.Percentile((float64(i)+0.5)*100/robustPoints) - median
+		if deviation < 0 {
+			deviation = -deviation
+		}
+		deviations[i] = deviation
+	}
+	sort.Float64s(deviations)
+	mad = (deviations[robustPoints/2-1] + deviations[robustPoints/2]) / 2
+	return trimmedMean, mad
+}
+
+// TrimPct returns the share of operations trimmed from each end for the
+// trimmed mean, in percent
+func (c *Collector) TrimPct() float64 {
+	return c.trimPct
+}
@@ -0,0 +1,120 @@
+package collector
+
+import (
+	"log"
+	"sort"
+	"time"
+)
+
+// Sink receives the statistics of a run as the collector produces them:
+// Start when the measured window begins, RecordInterval at the end of every
+// report interval, RecordFinal once with the statistics of the whole run and
+// Close when the collector stops. Calls are never concurrent.
+type Sink interface {
+	Start(start time.Time) error
+	RecordInterval(interval SinkStats) error
+	RecordFinal(final SinkStats) error
+	Close() error
+}
+
+// ProgressSink is a Sink that also receives the statistics of the whole run
+// so far at the end of every report interval, before RecordInterval
+type ProgressSink interface {
+	RecordProgress(sofar SinkStats) error
+}
+
+// SinkStats are the per-method and aggregated statistics of a span of the run
+type SinkStats struct {
+	Start      time.Time
+	End        time.Time
+	Elapsed    time.Duration // Measured time of the span, excluding pauses between resumed sessions
+	Offset     time.Duration // End of the span relative to the start of the run, on the monotonic clock
+	Methods    []Stats       // Methods with operations, sorted by name
+	Aggregated Stats         // All methods combined
+	Tagged     []TagStats    // Statistics by tag combination, nil for untagged operations
+}
+
+// Throughput returns the successful ops/sec of stats over the elapsed time
+func (s SinkStats) Throughput(stats Stats) float64 {
+	if s.Elapsed <= 0 {
+		return 0
+	}
+	return float64(stats.Successes()) / s.Elapsed.Seconds()
+}
+
+// AddSink attaches a sink that receives the interval and final statistics
+func (c *Collector) AddSink(s Sink) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.sinks = append(c.sinks, s)
+}
+
+// sinkStats collects the statistics of the given metrics, with the trimmed
+// mean and MAD if robust; the caller holds c.mu
+func (c *Collector) sinkStats(metrics map[string]*Metrics, start, end time.Time, elapsed time.Duration, robust bool) SinkStats {
+	methods := make([]string, 0, len(metrics))
+	for method := range metrics {
+		methods = append(methods, method)
+	}
+	sort.Strings(methods)
+
+	s := SinkStats{Start: start, End: end, Elapsed: elapsed, Offset: end.Sub(c.origin)}
+	for _, method := range methods {
+		if stats := metrics[method].getStats(robust); stats.Count > 0 {
+			s.Methods = append(s.Methods, stats)
+		}
+	}
+	if len(s.Methods) > 0 {
+		s.Aggregated = c.aggregateMetrics(metrics, robust)
+	}
+	return s
+}
+
+// progressStats collects the statistics of the measured window up to now,
+// as in the final statistics; the caller holds c.mu
+func (c *Collector) progressStats(now time.Time) SinkStats {
+	progress := c.sinkStats(c.metrics, c.runStart, now, c.measured(now), false)
+	progress.Tagged = c.tagStats(c.tagged, false)
+	progress.setDropped(c.DroppedByMethod())
+	return progress
+}
+
+// finishSinks hands the final statistics to every sink and closes it. The
+// final statistics cover the measured window of the run, or the span of all
+// results when no window was marked.
+func (c *Collector) finishSinks() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	start := c.runStart
+	elapsed := c.measured(now)
+	if elapsed <= 0 {
+		var last time.Time
+		for _, metrics := range c.metrics {
+			metrics.mu.RLock()
+			if metrics.Count > 0 {
+				if start.IsZero() || metrics.StartTime.Before(start) {
+					start = metrics.StartTime
+				}
+				if metrics.EndTime.After(last) {
+					last = metrics.EndTime
+				}
+			}
+			metrics.mu.RUnlock()
+		}
+		elapsed = last.Sub(start)
+	}
+	final := c.sinkStats(c.metrics, start, now, elapsed, true)
+	final.Tagged = c.tagStats(c.tagged, true)
+	final.setDropped(c.DroppedByMethod())
+
+	for _, s := range c.sinks {
+		if err := s.RecordFinal(final); err != nil {
+			log.Printf("Warning: %v", err)
+		}
+		if err := s.Close(); err != nil {
+			log.Printf("Warning: %v", err)
+		}
+	}
+}
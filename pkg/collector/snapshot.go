@@ -0,0 +1,163 @@
+package collector
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// Snapshot is the state of a collector's cumulative statistics, saved in
+// checkpoints so that an interrupted run can be resumed
+type Snapshot struct {
+	Engine    string           `json:"engine"`
+	Dropped   int64            `json:"dropped"`
+	DroppedBy map[string]int64 `json:"dropped_by_method,omitempty"` // Dropped measured results by method
+	Measured  time.Duration    `json:"measured,omitempty"`          // Measured time of the run so far
+	Methods   []MethodSnapshot `json:"methods"`
+}
+
+// MethodSnapshot is the saved state of the metrics of one method
+type MethodSnapshot struct {
+	Method        string           `json:"method"`
+	Count         int64            `json:"count"`
+	ErrorCount    int64            `json:"error_count"`
+	TotalLatency  float64          `json:"total_latency_ms"`
+	MinLatency    float64          `json:"min_latency_ms"`
+	MaxLatency    float64          `json:"max_latency_ms"`
+	BytesSent     int64            `json:"bytes_sent"`
+	BytesRecv     int64            `json:"bytes_received"`
+	WireSent      int64            `json:"wire_sent,omitempty"`
+	WireRecv      int64            `json:"wire_received,omitempty"`
+	Overflow      int64            `json:"overflow,omitempty"`
+	SLOMet        int64            `json:"slo_met,omitempty"`
+	SLOViolations int64            `json:"slo_violations,omitempty"`
+	ErrorCodes    map[string]int64 `json:"error_codes,omitempty"`
+	StartTime     time.Time        `json:"start_time,omitempty"`
+	EndTime       time.Time        `json:"end_time,omitempty"`
+	Distribution  []byte           `json:"distribution"`
+
+	// Latency distribution of failures, absent without errors
+	ErrorTotalLatency float64 `json:"error_total_latency_ms,omitempty"`
+	ErrorMaxLatency   float64 `json:"error_max_latency_ms,omitempty"`
+	ErrorDistribution []byte  `json:"error_distribution,omitempty"`
+
+	// Operations throttled by the server, absent without throttles
+	Throttling *Throttling `json:"throttling,omitempty"`
+}
+
+// Snapshot captures the cumulative statistics of all methods
+func (c *Collector) Snapshot() (*Snapshot, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	snapshot := &Snapshot{Engine: c.engine, Dropped: c.dropped.Load(), DroppedBy: c.DroppedByMethod(), Measured: c.measured(time.Now())}
+	for _, m := range c.metrics {
+		// Encoding may compact the recorder, so take the write lock
+		m.mu.Lock()
+		distribution, err := m.recorder.Marshal()
+		method := MethodSnapshot{
+			Method:        m.Method,
+			Count:         m.Count,
+			ErrorCount:    m.ErrorCount,
+			TotalLatency:  m.TotalLatency,
+			MinLatency:    m.MinLatency,
+			MaxLatency:    m.MaxLatency,
+			BytesSent:     m.BytesSent,
+			BytesRecv:     m.BytesRecv,
+			WireSent:      m.WireSent,
+			WireRecv:      m.WireRecv,
+			Overflow:      m.Overflow,
+			SLOMet:        m.SLOMet,
+			SLOViolations: m.SLOViolations,
+			ErrorCodes:    mergeErrorCodes(nil, m.ErrorCodes),
+			StartTime:     m.StartTime,
+			EndTime:       m.EndTime,
+			Distribution:  distribution,
+		}
+		if m.throttling.Count > 0 {
+			throttling := m.throttling
+			method.Throttling = &throttling
+		}
+		if err == nil && m.errors != nil {
+			method.ErrorTotalLatency = m.errors.total
+			method.ErrorMaxLatency = m.errors.max
+			method.ErrorDistribution, err = m.errors.recorder.Marshal()
+		}
+		m.mu.Unlock()
+		if err != nil {
+			return nil, fmt.Errorf("failed to snapshot %s metrics: %w", m.Method, err)
+		}
+		snapshot.Methods = append(snapshot.Methods, method)
+	}
+
+	sort.Slice(snapshot.Methods, func(i, j int) bool {
+		return snapshot.Methods[i].Method < snapshot.Methods[j].Method
+	})
+	return snapshot, nil
+}
+
+// Restore replaces the cumulative statistics with those of a snapshot taken
+// with the same percentile engine
+func (c *Collector) Restore(snapshot *Snapshot) error {
+	if snapshot.Engine != c.engine {
+		return fmt.Errorf("snapshot was recorded with the %s percentile engine, not %s", snapshot.Engine, c.engine)
+	}
+
+	metrics := make(map[string]*Metrics, len(snapshot.Methods))
+	for _, method := range snapshot.Methods {
+		recorder, err := unmarshalRecorder(c.engine, method.Distribution, c.latencyMax)
+		if err != nil {
+			return fmt.Errorf("failed to restore %s metrics: %w", method.Method, err)
+		}
+		var errors *errorLatency
+		if method.ErrorDistribution != nil {
+			errorRecorder, err := unmarshalRecorder(c.engine, method.ErrorDistribution, c.latencyMax)
+			if err != nil {
+				return fmt.Errorf("failed to restore %s failure latencies: %w", method.Method, err)
+			}
+			errors = &errorLatency{recorder: errorRecorder, total: method.ErrorTotalLatency, max: method.ErrorMaxLatency}
+		}
+		m := &Metrics{
+			Method:        method.Method,
+			Count:         method.Count,
+			ErrorCount:    method.ErrorCount,
+			TotalLatency:  method.TotalLatency,
+			MinLatency:    method.MinLatency,
+			MaxLatency:    method.MaxLatency,
+			BytesSent:     method.BytesSent,
+			BytesRecv:     method.BytesRecv,
+			WireSent:      method.WireSent,
+			WireRecv:      method.WireRecv,
+			Overflow:      method.Overflow,
+			SLOMet:        method.SLOMet,
+			SLOViolations: method.SLOViolations,
+			ErrorCodes:    method.ErrorCodes,
+			StartTime:     method.StartTime,
+			EndTime:       method.EndTime,
+			recorder:      recorder,
+			errors:        errors,
+			percentiles:   c.pcts,
+			sloThreshold:  c.sloThreshold(method.Method),
+			engine:        c.engine,
+			latencyMax:    c.latencyMax,
+			trimPct:       c.trimPct,
+		}
+		if method.Throttling != nil {
+			m.throttling = *method.Throttling
+		}
+		metrics[method.Method] = m
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.metrics = metrics
+	c.dropped.Store(snapshot.Dropped)
+	c.droppedMu.Lock()
+	c.droppedBy = make(map[string]int64, len(snapshot.DroppedBy))
+	for method, n := range snapshot.DroppedBy {
+		c.droppedBy[method] = n
+	}
+	c.droppedMu.Unlock()
+	c.priorMeasured = snapshot.Measured
+	return nil
+}
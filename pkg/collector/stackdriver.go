@@ -0,0 +1,164 @@
+package collector
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	gcpMonitoringURL = "https://monitoring.googleapis.com/v3/projects/%s/timeSeries"
+	gcpMetadataToken = "http://metadata.google.internal/computeMetadata/v1/instance/service-accounts/default/token"
+)
+
+// GCPMonitoringExporter periodically writes collector statistics as custom
+// metrics (custom.googleapis.com/kvbench/...) to Google Cloud Monitoring.
+// Credentials come from the GCP_ACCESS_TOKEN environment variable or, on GCE
+// and GKE, from the instance metadata server.
+type GCPMonitoringExporter struct {
+	collector *Collector
+	project   string
+	client    *http.Client
+	pusher    *intervalPusher
+
+	mu          sync.Mutex
+	token       string
+	tokenExpiry time.Time
+}
+
+// NewGCPMonitoringExporter creates an exporter writing to the given project every interval.
+// Cloud Monitoring accepts one point per series every 5 seconds at most.
+func NewGCPMonitoringExporter(c *Collector, project string, interval time.Duration) *GCPMonitoringExporter {
+	e := &GCPMonitoringExporter{
+		collector: c,
+		project:   project,
+		client:    &http.Client{Timeout: 10 * time.Second},
+	}
+	e.pusher = newIntervalPusher("GCP monitoring", interval, c.alignIntervals, e.push)
+	return e
+}
+
+// Start starts the push loop
+func (e *GCPMonitoringExporter) Start(ctx context.Context) {
+	e.pusher.start(ctx)
+}
+
+// Stop stops the push loop and pushes the final statistics
+func (e *GCPMonitoringExporter) Stop() {
+	e.pusher.stop()
+}
+
+// push writes the current statistics as gauge points
+func (e *GCPMonitoringExporter) push(now time.Time) error {
+	samples := prometheusSamples(e.collector.GetStats())
+	if len(samples) == 0 {
+		return nil
+	}
+
+	endTime := now.UTC().Format(time.RFC3339Nano)
+	var series []map[string]interface{}
+	for _, sample := range samples {
+		labels := map[string]string{"method": sample.method}
+		for _, label := range e.collector.Labels() {
+			labels[label.Name] = label.Value
+		}
+		if sample.quantile != "" {
+			labels["quantile"] = sample.quantile
+		}
+		series = append(series, map[string]interface{}{
+			"metric": map[string]interface{}{
+				"type":   "custom.googleapis.com/kvbench/" + strings.TrimPrefix(sample.name, "kvbench_"),
+				"labels": labels,
+			},
+			"resource": map[string]interface{}{
+				"type":   "global",
+				"labels": map[string]string{"project_id": e.project},
+			},
+			"metricKind": "GAUGE",
+			"valueType":  "DOUBLE",
+			"points": []map[string]interface{}{{
+				"interval": map[string]string{"endTime": endTime},
+				"value":    map[string]float64{"doubleValue": sample.value},
+			}},
+		})
+	}
+
+	body, err := json.Marshal(map[string]interface{}{"timeSeries": series})
+	if err != nil {
+		return fmt.Errorf("failed to encode time series: %w", err)
+	}
+
+	token, err := e.accessToken()
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, fmt.Sprintf(gcpMonitoringURL, e.project), bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("monitoring API returned %s: %s", resp.Status, bytes.TrimSpace(msg))
+	}
+	return nil
+}
+
+// accessToken returns a cached OAuth2 access token, refreshing it from the
+// metadata server when it is about to expire
+func (e *GCPMonitoringExporter) accessToken() (string, error) {
+	if token := os.Getenv("GCP_ACCESS_TOKEN"); token != "" {
+		return token, nil
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.token != "" && time.Until(e.tokenExpiry) > time.Minute {
+		return e.token, nil
+	}
+
+	req, err := http.NewRequest(http.MethodGet, gcpMetadataToken, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Metadata-Flavor", "Google")
+
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch access token from metadata server: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("metadata server returned %s", resp.Status)
+	}
+
+	var token struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int    `json:"expires_in"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&token); err != nil {
+		return "", fmt.Errorf("failed to decode access token: %w", err)
+	}
+
+	e.token = token.AccessToken
+	e.tokenExpiry = time.Now().Add(time.Duration(token.ExpiresIn) * time.Second)
+	return e.token, nil
+}
@@ -0,0 +1,139 @@
+package collector
+
+import (
+	"fmt"
+	"log"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// statsdMaxPacket keeps datagrams within a typical Ethernet MTU
+const statsdMaxPacket = 1432
+
+// statsdFlushInterval bounds how long a metric waits in the packet buffer
+const statsdFlushInterval = 100 * time.Millisecond
+
+// StatsDSink emits per-operation timing and counter metrics over UDP in the
+// StatsD line format. Tags are appended in the DogStatsD format, so a plain
+// StatsD server should be used without tags. Lines are batched into datagrams
+// of up to statsdMaxPacket bytes, flushed at least every statsdFlushInterval.
+type StatsDSink struct {
+	conn   net.Conn
+	prefix string
+	tags   string
+	mu     sync.Mutex
+	buf    []byte
+	failed int64
+	done   chan struct{}
+	wg     sync.WaitGroup
+}
+
+// NewStatsDSink creates a sink sending to the StatsD server at addr
+// (host:port). Metric names are prefixed with prefix; tags are "key:value"
+// DogStatsD tags attached to every metric.
+func NewStatsDSink(addr, prefix string, tags []string) (*StatsDSink, error) {
+	conn, err := net.Dial("udp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to StatsD at %s: %w", addr, err)
+	}
+
+	s := &StatsDSink{
+		conn:   conn,
+		prefix: strings.TrimSuffix(prefix, "."),
+		buf:    make([]byte, 0, statsdMaxPacket),
+		done:   make(chan struct{}),
+	}
+	if len(tags) > 0 {
+		s.tags = "|#" + strings.Join(tags, ",")
+	}
+
+	s.wg.Add(1)
+	go s.flushLoop()
+	return s, nil
+}
+
+// Write emits the metrics of a single result: an operation counter, an error
+// counter for failures and a latency timer for successes
+func (s *StatsDSink) Write(result *BenchmarkResult) {
+	method := strings.ToLower(result.Method)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.add(method+".ops", "1", "c")
+	if result.Error != nil {
+		s.add(method+".errors", "1", "c")
+		return
+	}
+	s.add(method+".latency", strconv.FormatFloat(result.LatencyMs, 'f', 3, 64), "ms")
+}
+
+// add appends a metric line, sending the buffered packet first if the line
+// would not fit; the caller holds s.mu
+func (s *StatsDSink) add(name, value, kind string) {
+	line := s.name(name) + ":" + value + "|" + kind + s.tags
+	if len(s.buf) > 0 && len(s.buf)+1+len(line) > statsdMaxPacket {
+		s.send()
+	}
+	if len(s.buf) > 0 {
+		s.buf = append(s.buf, '\n')
+	}
+	s.buf = append(s.buf, line...)
+}
+
+// name returns the fully qualified metric name
+func (s *StatsDSink) name(name string) string {
+	if s.prefix == "" {
+		return name
+	}
+	return s.prefix + "." + name
+}
+
+// send writes the buffered packet; the caller holds s.mu. UDP delivery is best
+// effort, so only the first failure is logged.
+func (s *StatsDSink) send() {
+	if len(s.buf) == 0 {
+		return
+	}
+	if _, err := s.conn.Write(s.buf); err != nil {
+		s.failed++
+		if s.failed == 1 {
+			log.Printf("Warning: failed to send StatsD metrics, further failures are not logged: %v", err)
+		}
+	}
+	s.buf = s.buf[:0]
+}
+
+// flushLoop periodically sends the buffered packet so that metrics arrive in
+// real time at low operation rates
+func (s *StatsDSink) flushLoop() {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(statsdFlushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.done:
+			return
+		case <-ticker.C:
+			s.mu.Lock()
+			s.send()
+			s.mu.Unlock()
+		}
+	}
+}
+
+// Close sends buffered metrics and closes the connection
+func (s *StatsDSink) Close() error {
+	close(s.done)
+	s.wg.Wait()
+
+	s.mu.Lock()
+	s.send()
+	s.mu.Unlock()
+	return s.conn.Close()
+}
@@ -0,0 +1,108 @@
+package collector
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Tag values derived per operation by the runner instead of given statically
+const (
+	TagTenant    = "{tenant}"     // Tenant of the key, as in composite keys
+	TagSizeClass = "{size_class}" // small, medium or large by payload bytes
+)
+
+// tagSeparators are the characters that would make tag combinations ambiguous
+// in outputs
+const tagSeparators = ",=;\""
+
+// Tag is a name=value dimension attached to operations
+type Tag struct {
+	Name  string
+	Value string // Static value, or TagTenant or TagSizeClass
+}
+
+// ParseTags parses a comma-separated list of name=value tags, e.g.
+// "region=eu,tenant={tenant}". Values in braces must be derived tags.
+func ParseTags(list string) ([]Tag, error) {
+	var tags []Tag
+	seen := make(map[string]bool)
+	for _, entry := range strings.Split(list, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		name, value, ok := strings.Cut(entry, "=")
+		name, value = strings.TrimSpace(name), strings.TrimSpace(value)
+		if !ok || name == "" || value == "" {
+			return nil, fmt.Errorf("invalid tag %q (expected name=value)", entry)
+		}
+		if strings.ContainsAny(name, tagSeparators) || strings.ContainsAny(value, tagSeparators) {
+			return nil, fmt.Errorf("invalid tag %q: names and values cannot contain any of %s", entry, tagSeparators)
+		}
+		if strings.HasPrefix(value, "{") && value != TagTenant && value != TagSizeClass {
+			return nil, fmt.Errorf("unknown derived tag value %q (expected %s or %s)", value, TagTenant, TagSizeClass)
+		}
+		if seen[name] {
+			return nil, fmt.Errorf("duplicate tag %q", name)
+		}
+		seen[name] = true
+		tags = append(tags, Tag{Name: name, Value: value})
+	}
+	sort.Slice(tags, func(i, j int) bool { return tags[i].Name < tags[j].Name })
+	return tags, nil
+}
+
+// TagStats are the per-method and aggregated statistics of the operations
+// sharing a tag combination
+type TagStats struct {
+	Tags       string  // Tag combination as sorted name=value pairs separated by commas
+	Methods    []Stats // Methods with operations, sorted by name
+	Aggregated Stats   // All methods of the combination
+}
+
+// addTaggedResult adds a tagged result to the statistics of its tag
+// combination; the caller holds c.mu
+func (c *Collector) addTaggedResult(groups map[string]map[string]*Metrics, result *BenchmarkResult) {
+	if result.Tags == "" || groups == nil {
+		return
+	}
+	group, exists := groups[result.Tags]
+	if !exists {
+		group = make(map[string]*Metrics)
+		groups[result.Tags] = group
+	}
+	metrics, exists := group[result.Method]
+	if !exists {
+		metrics = c.newMethodMetrics(result.Method)
+		group[result.Method] = metrics
+	}
+	metrics.AddResult(result)
+}
+
+// tagStats collects the statistics of tag groups sorted by tag combination,
+// with the trimmed mean and MAD if robust; the caller holds c.mu
+func (c *Collector) tagStats(groups map[string]map[string]*Metrics, robust bool) []TagStats {
+	combinations := make([]string, 0, len(groups))
+	for tags := range groups {
+		combinations = append(combinations, tags)
+	}
+	sort.Strings(combinations)
+
+	var stats []TagStats
+	for _, tags := range combinations {
+		s := c.sinkStats(groups[tags], time.Time{}, time.Time{}, 0, robust)
+		if len(s.Methods) > 0 {
+			stats = append(stats, TagStats{Tags: tags, Methods: s.Methods, Aggregated: s.Aggregated})
+		}
+	}
+	return stats
+}
+
+// GetTaggedStats returns the statistics of every tag combination of the run
+func (c *Collector) GetTaggedStats() []TagStats {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.tagStats(c.tagged, c.final())
+}
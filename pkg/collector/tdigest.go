@@ -0,0 +1,59 @@
+package collector
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/influxdata/tdigest"
+)
+
+// tdigestCompression bounds the number of centroids kept per digest. At 200
+// a digest stays below a few kilobytes while the percentile rank error is
+// roughly ±0.5% at the median and shrinks towards the tails (P99 and above).
+const tdigestCompression = 200
+
+// tdigestRecorder records latencies into a t-digest
+type tdigestRecorder struct {
+	digest *tdigest.TDigest
+}
+
+// newTDigestRecorder creates an empty t-digest recorder
+func newTDigestRecorder() *tdigestRecorder {
+	return &tdigestRecorder{digest: tdigest.NewWithCompression(tdigestCompression)}
+}
+
+// Record records a latency in milliseconds
+func (r *tdigestRecorder) Record(ms float64) {
+	r.digest.Add(ms, 1)
+}
+
+// Percentile returns the estimated latency in milliseconds at the given percentile
+func (r *tdigestRecorder) Percentile(p float64) float64 {
+	if r.digest.Count() == 0 {
+		return 0
+	}
+	return r.digest.Quantile(p / 100.0)
+}
+
+// Merge adds the centroids of another t-digest recorder
+func (r *tdigestRecorder) Merge(other latencyRecorder) {
+	if o, ok := other.(*tdigestRecorder); ok {
+		r.digest.AddCentroidList(o.digest.Centroids())
+	}
+}
+
+// Marshal encodes the centroids of the digest
+func (r *tdigestRecorder) Marshal() ([]byte, error) {
+	return json.Marshal(r.digest.Centroids())
+}
+
+// unmarshalTDigestRecorder decodes a digest encoded by Marshal
+func unmarshalTDigestRecorder(data []byte) (*tdigestRecorder, error) {
+	var centroids tdigest.CentroidList
+	if err := json.Unmarshal(data, &centroids); err != nil {
+		return nil, fmt.Errorf("failed to decode t-digest: %w", err)
+	}
+	r := newTDigestRecorder()
+	r.digest.AddCentroidList(centroids)
+	return r, nil
+}
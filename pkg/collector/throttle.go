@@ -0,0 +1,68 @@
+package collector
+
+import "google.golang.org/grpc/codes"
+
+// IsThrottled reports whether an operation failed because the server's
+// admission control rejected it: a RESOURCE_EXHAUSTED status, the gRPC
+// equivalent of HTTP 429. Throttled operations are counted apart from errors.
+func IsThrottled(err error) bool {
+	return err != nil && ErrorCode(err) == codes.ResourceExhausted.String()
+}
+
+// Throttling counts the operations a server throttled and the retry delays
+// it suggested with them
+type Throttling struct {
+	Count        int64   `json:"count"`
+	Hinted       int64   `json:"hinted,omitempty"` // Throttles suggesting a retry delay
+	RetryAfterMs float64 `json:"retry_after_total_ms,omitempty"`
+	RetryMaxMs   float64 `json:"retry_after_max_ms,omitempty"`
+}
+
+// recordThrottle counts a throttled operation with the retry delay the
+// server suggested in milliseconds, 0 for none; the caller holds m.mu
+func (m *Metrics) recordThrottle(retryAfterMs float64) {
+	m.throttling.Count++
+	if retryAfterMs > 0 {
+		m.throttling.Hinted++
+		m.throttling.RetryAfterMs += retryAfterMs
+		m.throttling.RetryMaxMs = max(m.throttling.RetryMaxMs, retryAfterMs)
+	}
+}
+
+// merge adds the throttles of other
+func (t *Throttling) merge(other Throttling) {
+	t.Count += other.Count
+	t.Hinted += other.Hinted
+	t.RetryAfterMs += other.RetryAfterMs
+	t.RetryMaxMs = max(t.RetryMaxMs, other.RetryMaxMs)
+}
+
+// fill sets the throttling statistics of stats
+func (t Throttling) fill(stats *Stats) {
+	stats.Throttled = t.Count
+	stats.RetryAfterHinted = t.Hinted
+	stats.RetryAfterMax = t.RetryMaxMs
+	if t.Hinted > 0 {
+		stats.RetryAfterAvg = t.RetryAfterMs / float64(t.Hinted)
+	}
+}
+
+// Successes returns the number of operations that neither failed nor were
+// throttled
+func (s Stats) Successes() int64 {
+	return s.Count - s.ErrorCount - s.Throttled
+}
+
+// Admitted returns the number of operations the server did not throttle,
+// failed or not
+func (s Stats) Admitted() int64 {
+	return s.Count - s.Throttled
+}
+
+// ThrottleRate returns the share of operations throttled, in percent
+func (s Stats) ThrottleRate() float64 {
+	if s.Count == 0 {
+		return 0
+	}
+	return float64(s.Throttled) / float64(s.Count) * 100.0
+}
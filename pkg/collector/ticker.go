@@ -0,0 +1,51 @@
+package collector
+
+import "time"
+
+// IntervalTicker delivers a tick every interval, like time.Ticker, or at
+// wall-clock multiples of the interval when aligned
+type IntervalTicker struct {
+	C    <-chan time.Time
+	stop func()
+}
+
+// NewIntervalTicker creates a ticker. Aligned ticks fall on multiples of the
+// interval since midnight UTC (e.g. :00, :05, :10 for 5s) and carry the exact
+// boundary time, so the first interval is usually shorter than the rest.
+// Slow receivers miss ticks, as with time.Ticker.
+func NewIntervalTicker(interval time.Duration, align bool) *IntervalTicker {
+	if !align {
+		ticker := time.NewTicker(interval)
+		return &IntervalTicker{C: ticker.C, stop: ticker.Stop}
+	}
+
+	ticks := make(chan time.Time, 1)
+	done := make(chan struct{})
+	go func() {
+		// Re-arming at every boundary keeps ticks aligned across clock
+		// adjustments, which a fixed period would drift from
+		timer := time.NewTimer(time.Until(time.Now().Truncate(interval).Add(interval)))
+		defer timer.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case now := <-timer.C:
+				// Shifting now keeps its monotonic reading, which
+				// Truncate drops
+				boundary := now.Add(now.Truncate(interval).Sub(now))
+				select {
+				case ticks <- boundary:
+				default:
+				}
+				timer.Reset(time.Until(boundary.Add(interval)))
+			}
+		}
+	}()
+	return &IntervalTicker{C: ticks, stop: func() { close(done) }}
+}
+
+// Stop stops the ticker; no more ticks are delivered
+func (t *IntervalTicker) Stop() {
+	t.stop()
+}
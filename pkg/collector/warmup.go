@@ -0,0 +1,25 @@
+package collector
+
+// addWarmupResult adds a warm-up result to the separate warm-up statistics;
+// the caller holds c.mu
+func (c *Collector) addWarmupResult(result *BenchmarkResult) {
+	metrics, exists := c.warmup[result.Method]
+	if !exists {
+		metrics = c.newMethodMetrics(result.Method)
+		c.warmup[result.Method] = metrics
+	}
+	metrics.AddResult(result)
+}
+
+// GetWarmupStats returns the per-method and aggregated statistics of the
+// warm-up results, which are kept out of every other statistic and output
+func (c *Collector) GetWarmupStats() (map[string]Stats, Stats) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	stats := make(map[string]Stats, len(c.warmup))
+	for method, metrics := range c.warmup {
+		stats[method] = metrics.GetStats()
+	}
+	return stats, c.aggregateMetrics(c.warmup, true)
+}
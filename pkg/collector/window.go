@@ -0,0 +1,60 @@
+package collector
+
+import "time"
+
+// SnapshotAndReset returns the statistics recorded since the last reset and
+// starts over, so that successive calls yield interval-local statistics
+func (m *Metrics) SnapshotAndReset() Stats {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	stats := m.stats(false)
+	m.Count = 0
+	m.ErrorCount = 0
+	m.TotalLatency = 0
+	m.MinLatency = float64(^uint(0) >> 1)
+	m.MaxLatency = 0
+	m.BytesSent = 0
+	m.BytesRecv = 0
+	m.WireSent = 0
+	m.WireRecv = 0
+	m.Overflow = 0
+	m.ErrorCodes = nil
+	m.SLOMet = 0
+	m.SLOViolations = 0
+	m.StartTime = time.Time{}
+	m.EndTime = time.Time{}
+	m.recorder = newLatencyRecorder(m.engine, m.latencyMax)
+	m.errors = nil
+	m.throttling = Throttling{}
+	return stats
+}
+
+// addWindowResult adds a result to the statistics since the last reset; the
+// caller holds c.mu
+func (c *Collector) addWindowResult(result *BenchmarkResult) {
+	metrics, exists := c.window[result.Method]
+	if !exists {
+		metrics = c.newMethodMetrics(result.Method)
+		c.window[result.Method] = metrics
+	}
+	metrics.AddResult(result)
+}
+
+// SnapshotAndReset returns the per-method and aggregated statistics of the
+// results processed since the previous call, and the time they cover, then
+// starts a new window at now. Unlike report intervals, the window is always
+// tracked and has no outputs of its own.
+func (c *Collector) SnapshotAndReset(now time.Time) (map[string]Stats, Stats, time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	aggregated := c.aggregateMetrics(c.window, false)
+	stats := make(map[string]Stats, len(c.window))
+	for method, metrics := range c.window {
+		stats[method] = metrics.SnapshotAndReset()
+	}
+	elapsed := now.Sub(c.windowStart)
+	c.windowStart = now
+	return stats, aggregated, elapsed
+}
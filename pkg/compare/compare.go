@@ -0,0 +1,355 @@
+// Package compare loads the final results of runs from their JSON summaries
+// (--json) or results CSVs (--csv) and computes the per-method changes from a
+// baseline run to another.
+package compare
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"kvstore-benchmarker/pkg/manifest"
+	"kvstore-benchmarker/pkg/results"
+)
+
+// Aggregated names the statistics of all methods combined
+const Aggregated = "AGGREGATED"
+
+// Method is the final statistics of a method of a run
+type Method struct {
+	Name       string
+	Throughput float64            // Successful ops/sec
+	ErrorRate  float64            // Percentage of failed operations
+	Latencies  map[string]float64 // Milliseconds by percentile key, e.g. "p99_9"
+}
+
+// Results is the final statistics of a run
+type Results struct {
+	Path        string
+	RunID       string
+	Notes       string
+	Build       *manifest.Build  // nil when the file does not record it
+	Client      *manifest.Client // nil when the file does not record it
+	Percentiles []string         // Percentile keys in the order of the file
+	Methods     []*Method        // In the order of the file, AGGREGATED last
+}
+
+// Method returns the statistics of the named method, or nil
+func (r *Results) Method(name string) *Method {
+	for _, m := range r.Methods {
+		if m.Name == name {
+			return m
+		}
+	}
+	return nil
+}
+
+// Load reads the results of a run from a JSON summary or a results CSV,
+// telling them apart by their first character
+func Load(path string) (*Results, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open results: %w", err)
+	}
+	defer f.Close()
+
+	// CSVs written with --csv-metadata start with "# " comment lines
+	r := bufio.NewReader(f)
+	var runID, notes string
+	for {
+		first, err := r.Peek(1)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read results %s: %w", path, err)
+		}
+		if first[0] != '#' {
+			break
+		}
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return nil, fmt.Errorf("failed to read results %s: %w", path, err)
+		}
+		if id, ok := strings.CutPrefix(strings.TrimSpace(line), "# run_id: "); ok {
+			runID = id
+		}
+		if config, ok := strings.CutPrefix(strings.TrimSpace(line), "# config: "); ok {
+			var c struct {
+				Notes string `json:"notes"`
+			}
+			json.Unmarshal([]byte(config), &c) // Notes are informational
+			notes = c.Notes
+		}
+	}
+	first, _ := r.Peek(1)
+	var results *Results
+	if first[0] == '{' {
+		results, err = loadJSON(r)
+	} else {
+		results, err = loadCSV(r)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if results.RunID == "" {
+		results.RunID = runID
+	}
+	if results.Notes == "" {
+		results.Notes = notes
+	}
+	if len(results.Methods) == 0 {
+		return nil, fmt.Errorf("%s: no results", path)
+	}
+	results.Path = path
+	return results, nil
+}
+
+// loadJSON reads a JSON summary. Throughput follows from the successful
+// operations and the run duration, like that of results CSVs.
+func loadJSON(r io.Reader) (*Results, error) {
+	summary, err := results.Read(r)
+	if err != nil {
+		return nil, err
+	}
+
+	loaded := &Results{RunID: summary.RunID, Notes: summary.Notes, Percentiles: jsonPercentiles(summary)}
+	// Summaries written before builds and clients were recorded leave them zero
+	if summary.Build.Version != "" {
+		loaded.Build = &summary.Build
+	}
+	if summary.Client.OS != "" {
+		loaded.Client = &summary.Client
+	}
+	method := func(s results.Stats) *Method {
+		m := &Method{Name: s.Method, ErrorRate: s.ErrorRatePct, Latencies: s.PercentilesMs}
+		if summary.DurationS > 0 {
+			m.Throughput = float64(s.Successes()) / summary.DurationS
+		}
+		return m
+	}
+	for _, s := range summary.Methods {
+		loaded.Methods = append(loaded.Methods, method(s))
+	}
+	if summary.Aggregated.Ops > 0 {
+		summary.Aggregated.Method = Aggregated
+		loaded.Methods = append(loaded.Methods, method(summary.Aggregated))
+	}
+	return loaded, nil
+}
+
+// jsonPercentiles returns the percentile keys of a JSON summary, in the
+// order of its --percentiles setting when available
+func jsonPercentiles(summary *results.Summary) []string {
+	var config struct {
+		Percentiles string `json:"percentiles"`
+	}
+	json.Unmarshal(summary.Config, &config) // Unordered percentiles are sorted below
+
+	var keys []string
+	for _, p := range strings.Split(config.Percentiles, ",") {
+		key := "p" + strings.ReplaceAll(strings.TrimSpace(p), ".", "_")
+		if _, ok := summary.Aggregated.PercentilesMs[key]; ok {
+			keys = append(keys, key)
+		}
+	}
+	if len(keys) == len(summary.Aggregated.PercentilesMs) {
+		return keys
+	}
+
+	// Sort the keys by percentile otherwise
+	keys = keys[:0]
+	for key := range summary.Aggregated.PercentilesMs {
+		keys = append(keys, key)
+	}
+	value := func(key string) float64 {
+		v, _ := strconv.ParseFloat(strings.ReplaceAll(strings.TrimPrefix(key, "p"), "_", "."), 64)
+		return v
+	}
+	sort.Slice(keys, func(i, j int) bool { return value(keys[i]) < value(keys[j]) })
+	return keys
+}
+
+// csvUnits converts the latency units of results CSV columns to milliseconds
+var csvUnits = map[string]float64{"ms": 1, "us": 1e-3, "ns": 1e-6}
+
+// loadCSV reads the summary rows of a results CSV, whatever its delimiter,
+// latency unit and labels; rows of tag combinations are skipped. Interval
+// CSVs (--csv-intervals) have no final statistics and are rejected.
+func loadCSV(r *bufio.Reader) (*Results, error) {
+	// The header starts with the timestamp column followed by the delimiter
+	head, err := r.Peek(len("timestamp") + 1)
+	if err != nil || string(head[:len("timestamp")]) != "timestamp" {
+		return nil, fmt.Errorf("neither a JSON summary nor a results CSV")
+	}
+	reader := csv.NewReader(r)
+	reader.Comma = rune(head[len("timestamp")])
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CSV header: %w", err)
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[name] = i
+	}
+	for _, name := range []string{"method", "error_rate_pct", "throughput_ops_per_sec"} {
+		if _, ok := columns[name]; !ok {
+			return nil, fmt.Errorf("CSV has no %s column", name)
+		}
+	}
+
+	type latencyColumn struct {
+		key   string
+		index int
+		scale float64
+	}
+	results := &Results{}
+	var latencies []latencyColumn
+	for i, name := range header {
+		prefix, unit, ok := strings.Cut(name, "_latency_")
+		if scale, known := csvUnits[unit]; ok && known && isPercentileKey(prefix) {
+			latencies = append(latencies, latencyColumn{key: prefix, index: i, scale: scale})
+			results.Percentiles = append(results.Percentiles, prefix)
+		}
+	}
+
+	tags, tagged := columns["tags"]
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CSV: %w", err)
+		}
+		if len(record) != len(header) {
+			return nil, fmt.Errorf("CSV line %d has %d fields, expected %d", line, len(record), len(header))
+		}
+		if tagged && record[tags] != "" {
+			continue
+		}
+
+		name := record[columns["method"]]
+		if results.Method(name) != nil {
+			return nil, fmt.Errorf("CSV has several rows for %s; interval CSVs (--csv-intervals) and CSVs of several runs (--csv-append) cannot be compared", name)
+		}
+		m := &Method{Name: name, Latencies: make(map[string]float64, len(latencies))}
+		if m.Throughput, err = parseFloat(record[columns["throughput_ops_per_sec"]]); err != nil {
+			return nil, fmt.Errorf("CSV line %d: %w", line, err)
+		}
+		if m.ErrorRate, err = parseFloat(record[columns["error_rate_pct"]]); err != nil {
+			return nil, fmt.Errorf("CSV line %d: %w", line, err)
+		}
+		for _, c := range latencies {
+			v, err := parseFloat(record[c.index])
+			if err != nil {
+				return nil, fmt.Errorf("CSV line %d: %w", line, err)
+			}
+			m.Latencies[c.key] = v * c.scale
+		}
+		results.Methods = append(results.Methods, m)
+	}
+	return results, nil
+}
+
+// isPercentileKey reports whether a column prefix is a percentile key such as "p99_9"
+func isPercentileKey(key string) bool {
+	if !strings.HasPrefix(key, "p") {
+		return false
+	}
+	_, err := strconv.ParseFloat(strings.ReplaceAll(key[1:], "_", "."), 64)
+	return err == nil
+}
+
+// parseFloat parses a CSV number, empty fields being zero
+func parseFloat(s string) (float64, error) {
+	if s == "" {
+		return 0, nil
+	}
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid number %q", s)
+	}
+	return v, nil
+}
+
+// Metric is a compared statistic
+type Metric struct {
+	Name    string // "throughput", "error_rate" or a percentile key such as "p99"
+	Latency bool
+	Higher  bool // Whether higher values are better
+}
+
+// Metrics returns the compared statistics: throughput, the latencies at the
+// percentiles both runs report and the error rate
+func Metrics(base, current *Results) []Metric {
+	metrics := []Metric{{Name: "throughput", Higher: true}}
+	for _, key := range base.Percentiles {
+		for _, other := range current.Percentiles {
+			if key == other {
+				metrics = append(metrics, Metric{Name: key, Latency: true})
+				break
+			}
+		}
+	}
+	return append(metrics, Metric{Name: "error_rate"})
+}
+
+// Value returns the statistic of a method
+func (m Metric) Value(method *Method) float64 {
+	switch {
+	case m.Latency:
+		return method.Latencies[m.Name]
+	case m.Name == "throughput":
+		return method.Throughput
+	default:
+		return method.ErrorRate
+	}
+}
+
+// Delta is the change of a statistic of a method from the baseline
+type Delta struct {
+	Method  string
+	Metric  Metric
+	Base    float64
+	Current float64
+	Change  float64 // Relative change in percent; +Inf when rising from zero
+}
+
+// Worse returns how many percent the statistic got worse, negative if it improved
+func (d Delta) Worse() float64 {
+	if d.Metric.Higher {
+		return -d.Change
+	}
+	return d.Change
+}
+
+// Compare returns the changes of every metric of every method present in
+// both runs, in the baseline's method order
+func Compare(base, current *Results) []Delta {
+	var deltas []Delta
+	metrics := Metrics(base, current)
+	for _, b := range base.Methods {
+		c := current.Method(b.Name)
+		if c == nil {
+			continue
+		}
+		for _, metric := range metrics {
+			d := Delta{Method: b.Name, Metric: metric, Base: metric.Value(b), Current: metric.Value(c)}
+			switch {
+			case d.Base != 0:
+				d.Change = (d.Current - d.Base) / d.Base * 100
+			case d.Current > 0:
+				d.Change = math.Inf(1)
+			}
+			deltas = append(deltas, d)
+		}
+	}
+	return deltas
+}
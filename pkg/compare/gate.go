@@ -0,0 +1,79 @@
+package compare
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Threshold is a limit on the change of a statistic from the baseline, e.g.
+// "p99>+10%" fails when P99 latency rose by more than 10% and
+// "throughput<-5%" when throughput fell by more than 5%
+type Threshold struct {
+	Text   string
+	Method string // Method name, empty for all methods combined
+	Metric string // "throughput", "error_rate" or a percentile key such as "p99_9"
+	Op     string // ">" or "<"
+	Pct    float64
+}
+
+// thresholdPattern matches "[method.]metric<op><change>%"
+var thresholdPattern = regexp.MustCompile(`^(?:(\w+)\.)?(\w+)\s*(<|>)\s*([+-]?\d+(?:\.\d+)?)%$`)
+
+// ParseThresholds parses a comma-separated list of thresholds. Metrics are
+// throughput, error_rate and percentiles such as p50, p99 and p99_9 (or p999).
+func ParseThresholds(list string) ([]Threshold, error) {
+	var thresholds []Threshold
+	for _, text := range strings.Split(list, ",") {
+		text = strings.TrimSpace(text)
+		if text == "" {
+			continue
+		}
+
+		m := thresholdPattern.FindStringSubmatch(text)
+		if m == nil {
+			return nil, fmt.Errorf("invalid regression threshold %q (expected [method.]metric>+N%% or [method.]metric<-N%%)", text)
+		}
+		t := Threshold{Text: text, Method: m[1], Metric: m[2], Op: m[3]}
+		if t.Metric == "p999" {
+			t.Metric = "p99_9"
+		}
+		if t.Metric != "throughput" && t.Metric != "error_rate" && !isPercentileKey(t.Metric) {
+			return nil, fmt.Errorf("regression threshold %q: unknown metric %q", text, t.Metric)
+		}
+		t.Pct, _ = strconv.ParseFloat(m[4], 64)
+		thresholds = append(thresholds, t)
+	}
+	return thresholds, nil
+}
+
+// Result is the outcome of checking a threshold
+type Result struct {
+	Threshold Threshold
+	Delta     Delta
+	NoData    bool // The method or percentile is missing from either run
+	Failed    bool
+}
+
+// Check checks the threshold against the change from the baseline. Methods
+// and percentiles missing from either run fail the threshold.
+func (t Threshold) Check(base, current *Results) Result {
+	method := t.Method
+	if method == "" {
+		method = Aggregated
+	}
+	for _, d := range Compare(base, current) {
+		if d.Method != method || d.Metric.Name != t.Metric {
+			continue
+		}
+		result := Result{Threshold: t, Delta: d}
+		if t.Op == ">" {
+			result.Failed = d.Change > t.Pct
+		} else {
+			result.Failed = d.Change < t.Pct
+		}
+		return result
+	}
+	return Result{Threshold: t, NoData: true, Failed: true}
+}
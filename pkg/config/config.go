@@ -5,7 +5,18 @@ import (
 	"flag"
 	"fmt"
 	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"text/template"
 	"time"
+
+	"kvstore-benchmarker/pkg/collector"
+	"kvstore-benchmarker/pkg/compare"
+	"kvstore-benchmarker/pkg/kvclient"
+	"kvstore-benchmarker/pkg/latency"
+	"kvstore-benchmarker/pkg/objstore"
+	"kvstore-benchmarker/pkg/slo"
 )
 
 // BenchmarkConfig holds all benchmark parameters
@@ -20,10 +31,215 @@ type BenchmarkConfig struct {
 	ReadRatio      int           `json:"read_ratio"`
 	WriteRatio     int           `json:"write_ratio"`
 	DeleteRatio    int           `json:"delete_ratio"`
-	ReportInterval time.Duration `json:"report_interval"`
-	OutputCSV      string        `json:"output_csv"`
-	LogRequests    bool          `json:"log_requests"`
-	LogErrors      bool          `json:"log_errors"`
+
+	// Percentages of secondary-index writes (a primary record plus its index
+	// entry) and queries by index, part of the operation mix
+	IndexWriteRatio int `json:"index_write_ratio"`
+	IndexReadRatio  int `json:"index_read_ratio"`
+
+	// Encoding of keys, and the number of tenants of composite keys
+	KeyEncoding string `json:"key_encoding"`
+	KeyTenants  int    `json:"key_tenants"`
+
+	// Tags attached to every operation, static or derived from the key and
+	// payload, that statistics are additionally grouped by
+	OpTags string `json:"op_tags"`
+
+	// Key generator state saved on exit and restored on start, so restarted
+	// sessions keep addressing the same dataset
+	GeneratorState string `json:"generator_state"`
+
+	// Per-operation rate limits in ops/sec, 0 means unlimited
+	GetRateLimit    int `json:"get_rate_limit"`
+	PutRateLimit    int `json:"put_rate_limit"`
+	DeleteRateLimit int `json:"delete_rate_limit"`
+
+	// Wait the retry delay a server suggests when throttling a worker before
+	// its next operation
+	HonorRetryAfter bool `json:"honor_retry_after"`
+
+	// Exponential backoff of a throttled worker: the first delay (0 disables
+	// backoff), doubled on every further consecutive throttle up to the maximum
+	ThrottleBackoff    time.Duration `json:"throttle_backoff"`
+	ThrottleBackoffMax time.Duration `json:"throttle_backoff_max"`
+
+	// Per-operation deadline (0 disables it) and jitter of deadlines and worker start times
+	OpTimeout         time.Duration `json:"op_timeout"`
+	OpTimeoutJitter   time.Duration `json:"op_timeout_jitter"`
+	WorkerStartJitter time.Duration `json:"worker_start_jitter"`
+
+	// Window over which workers are started at the beginning of each phase, 0 starts all at once
+	RampUp time.Duration `json:"ramp_up"`
+
+	// Duty cycle alternating full load and idle windows, disabled when either is 0
+	DutyCycleOn  time.Duration `json:"duty_cycle_on"`
+	DutyCycleOff time.Duration `json:"duty_cycle_off"`
+
+	// Collect warm-up results into separate statistics instead of dropping them
+	CollectWarmup bool `json:"collect_warmup"`
+
+	// Client overhead calibration against a null backend, 0 disables it
+	CalibrateDuration time.Duration `json:"calibrate_duration"`
+	CalibrateSubtract bool          `json:"calibrate_subtract"`
+
+	TimelinePath     string        `json:"timeline_path"`
+	ReportInterval   time.Duration `json:"report_interval"`
+	AlignIntervals   bool          `json:"align_intervals"`
+	OutputCSV        string        `json:"output_csv"`
+	ArchivePath      string        `json:"archive_path"`
+	RawLogPath       string        `json:"raw_log_path"`
+	RawLogSample     int           `json:"raw_log_sample"`
+	ParquetRaw       string        `json:"parquet_raw"`
+	ParquetIntervals string        `json:"parquet_intervals"`
+	HistogramLog     string        `json:"histogram_log"`
+	HistogramStore   string        `json:"histogram_store"`
+	HeatmapPath      string        `json:"heatmap_path"`
+	CurvePath        string        `json:"curve_path"`
+	RemoteWriteURL   string        `json:"remote_write_url"`
+	PushgatewayURL   string        `json:"pushgateway_url"`
+	OTLPEndpoint     string        `json:"otlp_endpoint"`
+	ManifestPath     string        `json:"manifest_path"`
+	PostRun          string        `json:"post_run"`
+	UploadURL        string        `json:"upload_url"`
+	UploadKey        string        `json:"upload_key"`
+	RunID            string        `json:"run_id"`
+	Labels           string        `json:"labels"`
+	Notes            string        `json:"notes"`
+	AgentListen      string        `json:"agent_listen"`
+	AdminAddress     string        `json:"admin_address"`
+	WebAddress       string        `json:"web_address"`
+	LogRequests      bool          `json:"log_requests"`
+	LogErrors        bool          `json:"log_errors"`
+
+	// TLS of the connections to the target, verified against a custom CA
+	// bundle and server name when set
+	TLS           bool   `json:"tls"`
+	TLSCAFile     string `json:"tls_ca_file"`
+	TLSServerName string `json:"tls_server_name"`
+
+	// Keepalive pings of the connections to the target (0 disables them),
+	// the time to wait for their acknowledgement and whether idle connections
+	// without active RPCs are pinged too
+	KeepaliveTime                time.Duration `json:"keepalive_time"`
+	KeepaliveTimeout             time.Duration `json:"keepalive_timeout"`
+	KeepalivePermitWithoutStream bool          `json:"keepalive_permit_without_stream"`
+
+	// Request signing scheme ("" disables signing), the ID of the signing key
+	// and the file holding its secret
+	Sign           string `json:"sign"`
+	SignKeyID      string `json:"sign_key_id"`
+	SignSecretFile string `json:"sign_secret_file"`
+
+	// YAML or JSON file of per-tenant credentials and metadata
+	TenantProfiles string `json:"tenant_profiles"`
+
+	// Bearer token refreshed every TokenRefresh, from a command, a file or an
+	// OAuth2 client credentials grant, and a file of metadata such as API keys
+	// reread as often
+	TokenCommand           string        `json:"token_command"`
+	TokenFile              string        `json:"token_file"`
+	MetadataFile           string        `json:"metadata_file"`
+	OAuth2TokenURL         string        `json:"oauth2_token_url"`
+	OAuth2ClientID         string        `json:"oauth2_client_id"`
+	OAuth2ClientSecretFile string        `json:"oauth2_client_secret_file"`
+	OAuth2Scopes           string        `json:"oauth2_scopes"`
+	TokenRefresh           time.Duration `json:"token_refresh"`
+
+	// Periodic checkpoints of the measured phase, which --resume continues from
+	CheckpointPath     string        `json:"checkpoint_path"`
+	CheckpointInterval time.Duration `json:"checkpoint_interval"`
+	Resume             bool          `json:"resume"`
+
+	// Named phases of the measured run and SLO assertions over them
+	Phases string `json:"phases"`
+	SLOs   string `json:"slos"`
+
+	// Per-method latency thresholds that every operation is counted against
+	LatencySLOs string `json:"latency_slos"`
+
+	// Repetitions of the whole benchmark and the pause between them
+	Repeat   int           `json:"repeat"`
+	CoolDown time.Duration `json:"cool_down"`
+
+	// Results of an earlier run to compare against and the regressions that fail the run
+	Baseline string `json:"baseline"`
+	FailOn   string `json:"fail_on"`
+
+	// Search for the highest rate meeting a latency objective, replacing the fixed-duration measured phase
+	SearchSLO        string        `json:"search_slo"`
+	SearchHold       time.Duration `json:"search_hold"`
+	SearchMinRate    int           `json:"search_min_rate"`
+	SearchMaxRate    int           `json:"search_max_rate"`
+	SearchResolution int           `json:"search_resolution"`
+
+	// Hourly infrastructure cost of the system under test, 0 omits cost reporting
+	CostPerHour float64 `json:"cost_per_hour"`
+
+	// External power/energy sampling command run every report interval
+	EnergyCommand string `json:"energy_command"`
+	EnergyMode    string `json:"energy_mode"`
+
+	// Read-repair stress probe: endpoints written with conflicting versions,
+	// the time between probes and how long a probe waits for convergence
+	ConvergenceEndpoints string        `json:"convergence_endpoints"`
+	ConvergenceInterval  time.Duration `json:"convergence_interval"`
+	ConvergenceTimeout   time.Duration `json:"convergence_timeout"`
+
+	// Results channel backpressure
+	ResultsBufferSize int  `json:"results_buffer_size"`
+	ResultsBlocking   bool `json:"results_blocking"`
+
+	// Cloud monitoring sinks
+	CloudWatchEMF       string `json:"cloudwatch_emf"`
+	CloudWatchNamespace string `json:"cloudwatch_namespace"`
+	GCPProject          string `json:"gcp_project"`
+
+	// Grafana annotations of the run's phases, with a service account token
+	GrafanaURL       string `json:"grafana_url"`
+	GrafanaTokenFile string `json:"grafana_token_file"`
+
+	// Webhook notified when the run ends, with the URL in a file since
+	// webhook URLs such as Slack's carry their credentials
+	NotifyURLFile string `json:"notify_url_file"`
+	NotifyFormat  string `json:"notify_format"`
+
+	// Graphite plaintext protocol sink
+	GraphiteAddress  string `json:"graphite_address"`
+	GraphiteTemplate string `json:"graphite_template"`
+
+	// StatsD/DogStatsD sink receiving every result
+	StatsDAddress string `json:"statsd_address"`
+	StatsDPrefix  string `json:"statsd_prefix"`
+	StatsDTags    string `json:"statsd_tags"`
+
+	// Latency recording and output formatting
+	PercentileEngine string        `json:"percentile_engine"`
+	LatencyMax       time.Duration `json:"latency_max"`
+	TrimPct          float64       `json:"trim_pct"`
+	LatencyBreakdown bool          `json:"latency_breakdown"`
+	Percentiles      string        `json:"percentiles"`
+	LatencyUnit      string        `json:"latency_unit"`
+	Color            string        `json:"color"`
+	TUI              bool          `json:"tui"`
+	ProgressFormat   string        `json:"progress_format"`
+	OutputFormat     string        `json:"output_format"`
+	ReportTemplate   string        `json:"report_template"`
+	ReportOutput     string        `json:"report_output"`
+	JSONSummary      string        `json:"json_summary"`
+	YAMLSummary      string        `json:"yaml_summary"`
+	MarkdownReport   string        `json:"markdown_report"`
+	HTMLReport       string        `json:"html_report"`
+	ChartsDir        string        `json:"charts_dir"`
+	ChartFormat      string        `json:"chart_format"`
+	SQLitePath       string        `json:"sqlite_path"`
+
+	// CSV output formatting
+	CSVDelimiter  string `json:"csv_delimiter"`
+	CSVPrecision  int    `json:"csv_precision"`
+	CSVTimestamps string `json:"csv_timestamps"`
+	CSVIntervals  bool   `json:"csv_intervals"`
+	CSVMetadata   bool   `json:"csv_metadata"`
+	CSVAppend     bool   `json:"csv_append"`
 }
 
 // DefaultConfig returns a default configuration
@@ -39,37 +255,324 @@ func DefaultConfig() *BenchmarkConfig {
 		ReadRatio:      70,
 		WriteRatio:     25,
 		DeleteRatio:    5,
-		ReportInterval: 5 * time.Second,
-		OutputCSV:      "",
-		LogRequests:    false,
-		LogErrors:      false,
+
+		KeyEncoding: "random",
+		KeyTenants:  16,
+
+		OpTags: "",
+
+		GeneratorState: "",
+
+		GetRateLimit:    0,
+		PutRateLimit:    0,
+		DeleteRateLimit: 0,
+
+		HonorRetryAfter: false,
+
+		ThrottleBackoff:    0,
+		ThrottleBackoffMax: time.Second,
+
+		OpTimeout:         0,
+		OpTimeoutJitter:   0,
+		WorkerStartJitter: 0,
+
+		RampUp: 0,
+
+		DutyCycleOn:  0,
+		DutyCycleOff: 0,
+
+		CollectWarmup: false,
+
+		CalibrateDuration: 0,
+		CalibrateSubtract: false,
+
+		TimelinePath:     "",
+		ReportInterval:   5 * time.Second,
+		AlignIntervals:   false,
+		OutputCSV:        "",
+		ArchivePath:      "",
+		RawLogPath:       "",
+		RawLogSample:     1,
+		ParquetRaw:       "",
+		ParquetIntervals: "",
+		HistogramLog:     "",
+		HeatmapPath:      "",
+		CurvePath:        "",
+		HistogramStore:   "",
+		RemoteWriteURL:   "",
+		PushgatewayURL:   "",
+		OTLPEndpoint:     "",
+		ManifestPath:     "",
+		PostRun:          "",
+		UploadURL:        "",
+		UploadKey:        "{{.RunID}}/{{.File}}",
+		RunID:            "",
+		Labels:           "",
+		Notes:            "",
+		AgentListen:      "",
+		AdminAddress:     "",
+		WebAddress:       "",
+		LogRequests:      false,
+		LogErrors:        false,
+
+		TLS:           false,
+		TLSCAFile:     "",
+		TLSServerName: "",
+
+		KeepaliveTime:                0,
+		KeepaliveTimeout:             20 * time.Second,
+		KeepalivePermitWithoutStream: false,
+
+		Sign:           "",
+		SignKeyID:      "",
+		SignSecretFile: "",
+
+		TenantProfiles: "",
+
+		TokenCommand:           "",
+		TokenFile:              "",
+		MetadataFile:           "",
+		OAuth2TokenURL:         "",
+		OAuth2ClientID:         "",
+		OAuth2ClientSecretFile: "",
+		OAuth2Scopes:           "",
+		TokenRefresh:           10 * time.Minute,
+
+		CheckpointPath:     "",
+		CheckpointInterval: time.Minute,
+		Resume:             false,
+
+		Phases: "",
+		SLOs:   "",
+
+		LatencySLOs: "",
+
+		Repeat:   1,
+		CoolDown: 10 * time.Second,
+
+		Baseline: "",
+		FailOn:   "",
+
+		SearchSLO:        "",
+		SearchHold:       5 * time.Minute,
+		SearchMinRate:    0,
+		SearchMaxRate:    0,
+		SearchResolution: 0,
+
+		CostPerHour: 0,
+
+		EnergyCommand: "",
+		EnergyMode:    "energy",
+
+		ConvergenceEndpoints: "",
+		ConvergenceInterval:  time.Second,
+		ConvergenceTimeout:   10 * time.Second,
+
+		ResultsBufferSize: 10000,
+		ResultsBlocking:   false,
+
+		CloudWatchEMF:       "",
+		CloudWatchNamespace: "KVBench",
+		GCPProject:          "",
+
+		GrafanaURL:       "",
+		GrafanaTokenFile: "",
+
+		NotifyURLFile: "",
+		NotifyFormat:  "json",
+
+		GraphiteAddress:  "",
+		GraphiteTemplate: collector.DefaultGraphiteTemplate,
+
+		StatsDAddress: "",
+		StatsDPrefix:  "kvbench",
+		StatsDTags:    "",
+
+		PercentileEngine: "hdr",
+		LatencyMax:       collector.DefaultLatencyMax,
+		TrimPct:          collector.DefaultTrimPct,
+		LatencyBreakdown: false,
+		Percentiles:      "50,95,99,99.9",
+		LatencyUnit:      "ms",
+		Color:            "auto",
+		TUI:              false,
+		ProgressFormat:   "text",
+		OutputFormat:     "none",
+		ReportTemplate:   "",
+		ReportOutput:     "-",
+		JSONSummary:      "",
+		YAMLSummary:      "",
+		MarkdownReport:   "",
+		HTMLReport:       "",
+		ChartsDir:        "",
+		ChartFormat:      "svg",
+		SQLitePath:       "",
+
+		CSVDelimiter:  ",",
+		CSVPrecision:  -1,
+		CSVTimestamps: "iso",
+		CSVIntervals:  false,
+		CSVMetadata:   false,
+		CSVAppend:     false,
 	}
 }
 
 // ParseFlags parses command line flags and returns a config
 func ParseFlags() *BenchmarkConfig {
 	config := DefaultConfig()
-
-	flag.StringVar(&config.TargetAddress, "target", config.TargetAddress, "gRPC server address")
-	flag.IntVar(&config.NumConnections, "connections", config.NumConnections, "Number of gRPC connections")
-	flag.IntVar(&config.NumWorkers, "workers", config.NumWorkers, "Number of concurrent workers")
-	flag.DurationVar(&config.Duration, "duration", config.Duration, "Benchmark duration")
-	flag.DurationVar(&config.WarmupDuration, "warmup", config.WarmupDuration, "Warm-up duration")
-	flag.IntVar(&config.KeySpace, "keyspace", config.KeySpace, "Number of unique keys")
-	flag.IntVar(&config.ValueSize, "valuesize", config.ValueSize, "Size of values in bytes")
-	flag.IntVar(&config.ReadRatio, "read", config.ReadRatio, "Percentage of read operations")
-	flag.IntVar(&config.WriteRatio, "write", config.WriteRatio, "Percentage of write operations")
-	flag.IntVar(&config.DeleteRatio, "delete", config.DeleteRatio, "Percentage of delete operations")
-	flag.DurationVar(&config.ReportInterval, "report-interval", config.ReportInterval, "Report interval")
-	flag.StringVar(&config.OutputCSV, "csv", config.OutputCSV, "Output CSV file path")
-	flag.BoolVar(&config.LogRequests, "log-requests", config.LogRequests, "Log all requests")
-	flag.BoolVar(&config.LogErrors, "log-errors", config.LogErrors, "Log error requests")
-
+	config.RegisterFlags(flag.CommandLine)
 	flag.Parse()
-
 	return config
 }
 
+// RegisterFlags defines a flag for every setting on fs, defaulting to the
+// current values, so that flags override e.g. a configuration file
+func (config *BenchmarkConfig) RegisterFlags(fs *flag.FlagSet) {
+	config.RegisterClientFlags(fs)
+	fs.IntVar(&config.NumConnections, "connections", config.NumConnections, "Number of gRPC connections")
+	fs.IntVar(&config.NumWorkers, "workers", config.NumWorkers, "Number of concurrent workers")
+	fs.DurationVar(&config.Duration, "duration", config.Duration, "Benchmark duration")
+	fs.DurationVar(&config.WarmupDuration, "warmup", config.WarmupDuration, "Warm-up duration")
+	fs.BoolVar(&config.CollectWarmup, "collect-warmup", config.CollectWarmup, "Report warm-up results separately instead of discarding them")
+	fs.DurationVar(&config.CalibrateDuration, "calibrate", config.CalibrateDuration, "Measure the client's own overhead against an in-process null backend for this long before the run (0 = off)")
+	fs.BoolVar(&config.CalibrateSubtract, "calibrate-subtract", config.CalibrateSubtract, "Also print the final results with the calibrated client overhead subtracted")
+	fs.IntVar(&config.KeySpace, "keyspace", config.KeySpace, "Number of unique keys")
+	fs.IntVar(&config.ValueSize, "valuesize", config.ValueSize, "Size of values in bytes")
+	fs.IntVar(&config.ReadRatio, "read", config.ReadRatio, "Percentage of read operations")
+	fs.IntVar(&config.WriteRatio, "write", config.WriteRatio, "Percentage of write operations")
+	fs.IntVar(&config.DeleteRatio, "delete", config.DeleteRatio, "Percentage of delete operations")
+	fs.IntVar(&config.IndexWriteRatio, "index-write", config.IndexWriteRatio, "Percentage of secondary-index writes (primary record plus index entry)")
+	fs.IntVar(&config.IndexReadRatio, "index-read", config.IndexReadRatio, "Percentage of queries by secondary index")
+	fs.StringVar(&config.KeyEncoding, "key-encoding", config.KeyEncoding, "Key encoding: random, raw (big-endian index), hashed, ordered (zero-padded) or composite (tenant/id)")
+	fs.IntVar(&config.KeyTenants, "key-tenants", config.KeyTenants, "Number of tenants of composite keys")
+	fs.StringVar(&config.OpTags, "op-tags", config.OpTags, "Comma-separated name=value tags attached to operations and grouped by in the results, values {tenant} and {size_class} are derived per operation (e.g. region=eu,tenant={tenant})")
+	fs.StringVar(&config.GeneratorState, "generator-state", config.GeneratorState, "Restore the key generator (keys, written keys, counters) from this file and save it back on exit")
+	fs.IntVar(&config.GetRateLimit, "get-rate", config.GetRateLimit, "Maximum Get operations per second (0 = unlimited)")
+	fs.IntVar(&config.PutRateLimit, "put-rate", config.PutRateLimit, "Maximum Put operations per second (0 = unlimited)")
+	fs.IntVar(&config.DeleteRateLimit, "delete-rate", config.DeleteRateLimit, "Maximum Delete operations per second (0 = unlimited)")
+	fs.BoolVar(&config.HonorRetryAfter, "honor-retry-after", config.HonorRetryAfter, "Make a throttled worker wait the retry delay the server suggests (google.rpc.RetryInfo) before its next operation")
+	fs.DurationVar(&config.ThrottleBackoff, "throttle-backoff", config.ThrottleBackoff, "Make a throttled worker back off before its next operation, starting with this delay and doubling it on consecutive throttles (0 disables backoff)")
+	fs.DurationVar(&config.ThrottleBackoffMax, "throttle-backoff-max", config.ThrottleBackoffMax, "Longest --throttle-backoff delay")
+	fs.DurationVar(&config.OpTimeout, "op-timeout", config.OpTimeout, "Deadline of a single operation (0 for none)")
+	fs.DurationVar(&config.OpTimeoutJitter, "op-timeout-jitter", config.OpTimeoutJitter, "Random extra time added to each operation deadline, up to this value")
+	fs.DurationVar(&config.WorkerStartJitter, "worker-start-jitter", config.WorkerStartJitter, "Delay each worker's first operation by a random time up to this value")
+	fs.DurationVar(&config.RampUp, "ramp-up", config.RampUp, "Start workers evenly over this window at the start of each phase (0 starts all at once)")
+	fs.DurationVar(&config.DutyCycleOn, "duty-on", config.DutyCycleOn, "Duty cycle load window (e.g. 5s, requires --duty-off)")
+	fs.DurationVar(&config.DutyCycleOff, "duty-off", config.DutyCycleOff, "Duty cycle idle window (e.g. 5s, requires --duty-on)")
+	fs.StringVar(&config.TimelinePath, "timeline", config.TimelinePath, "Load profile timeline file (CSV or YAML of offset, rate, workers)")
+	fs.DurationVar(&config.ReportInterval, "report-interval", config.ReportInterval, "Report interval")
+	fs.BoolVar(&config.AlignIntervals, "align-intervals", config.AlignIntervals, "Align report intervals and exporter pushes to wall-clock multiples of the report interval (e.g. :00, :05 for 5s)")
+	fs.StringVar(&config.OutputCSV, "csv", config.OutputCSV, "Output CSV file path (- for stdout)")
+	fs.StringVar(&config.ArchivePath, "archive", config.ArchivePath, "Binary result archive file path")
+	fs.StringVar(&config.HistogramLog, "hlog", config.HistogramLog, "Write interval histograms in HdrHistogram log format (.hlog) to this file")
+	fs.StringVar(&config.CurvePath, "throughput-latency", config.CurvePath, "Write achieved throughput and latency percentiles of every report interval as CSV to this file")
+	fs.StringVar(&config.HeatmapPath, "heatmap", config.HeatmapPath, "Write a latency heatmap (operations per latency bucket per report interval) as CSV to this file")
+	fs.StringVar(&config.HistogramStore, "histogram-store", config.HistogramStore, "Append interval histograms to this binary store for post-run percentile queries")
+	fs.StringVar(&config.RawLogPath, "raw-log", config.RawLogPath, "Stream every operation result as JSON lines to this file or pipe (- for stdout)")
+	fs.IntVar(&config.RawLogSample, "raw-log-sample", config.RawLogSample, "Write one in every N results to the raw log and --parquet-raw (all results still feed the statistics)")
+	fs.StringVar(&config.ParquetRaw, "parquet-raw", config.ParquetRaw, "Write operation results, sampled by --raw-log-sample, as a Parquet file")
+	fs.StringVar(&config.ParquetIntervals, "parquet-intervals", config.ParquetIntervals, "Write per-method and aggregated statistics of every report interval as a Parquet file")
+	fs.StringVar(&config.RemoteWriteURL, "remote-write", config.RemoteWriteURL, "Prometheus remote-write URL to push metrics to every report interval")
+	fs.StringVar(&config.OTLPEndpoint, "otlp-endpoint", config.OTLPEndpoint, "OpenTelemetry collector OTLP/HTTP endpoint to push metrics to every report interval (e.g. http://localhost:4318)")
+	fs.StringVar(&config.PostRun, "post-run", config.PostRun, "Command run at the end with the path of the JSON run manifest as its last argument (e.g. \"./upload.sh --team kv\")")
+	fs.StringVar(&config.UploadURL, "upload", config.UploadURL, "Upload the run's output files to this bucket at the end (s3://bucket/prefix or gs://bucket/prefix)")
+	fs.StringVar(&config.UploadKey, "upload-key", config.UploadKey, "Go text/template of the object key of each uploaded file under the --upload prefix, with .RunID, .File, .Start and .Labels")
+	fs.StringVar(&config.ManifestPath, "manifest", config.ManifestPath, "Write a JSON run manifest with the configuration, summary and client hardware to this file")
+	fs.StringVar(&config.PushgatewayURL, "pushgateway", config.PushgatewayURL, "Prometheus Pushgateway URL to push final metrics to")
+	fs.StringVar(&config.TenantProfiles, "tenant-profiles", config.TenantProfiles, "YAML or JSON file of per-tenant gRPC metadata and signing keys sent with the requests on each tenant's keys (tenants as in --key-tenants)")
+	fs.StringVar(&config.CheckpointPath, "checkpoint", config.CheckpointPath, "Periodically save collector state and phase position to this file so the run can be resumed")
+	fs.DurationVar(&config.CheckpointInterval, "checkpoint-interval", config.CheckpointInterval, "Interval between checkpoints")
+	fs.BoolVar(&config.Resume, "resume", config.Resume, "Resume the run saved in the --checkpoint file")
+	fs.StringVar(&config.Phases, "phases", config.Phases, "Named phases of the measured run as name=offset pairs (e.g. steady=0s,failover=5m,recovery=6m)")
+	fs.StringVar(&config.SLOs, "slo", config.SLOs, "Comma-separated SLO assertions [phase:][method.]metric<value (e.g. steady:p99<10ms,failover:p99<500ms,error_rate<1)")
+	fs.StringVar(&config.LatencySLOs, "latency-slo", config.LatencySLOs, "Per-method latency thresholds to report conformance against, e.g. Get=5ms,Put=20ms (* for all other methods)")
+	fs.IntVar(&config.Repeat, "repeat", config.Repeat, "Run the whole benchmark this many times and report statistics across the repetitions")
+	fs.DurationVar(&config.CoolDown, "cool-down", config.CoolDown, "Pause between --repeat repetitions")
+	fs.StringVar(&config.Baseline, "baseline", config.Baseline, "JSON summary or results CSV of an earlier run to compare the results against")
+	fs.StringVar(&config.FailOn, "fail-on", config.FailOn, "Comma-separated regression thresholds versus the --baseline that fail the run, [method.]metric>+N% or <-N% (e.g. p99>+10%,throughput<-5%)")
+	fs.StringVar(&config.SearchSLO, "search-slo", config.SearchSLO, "Search for the highest rate meeting this objective, e.g. p99<=5ms (replaces --duration)")
+	fs.DurationVar(&config.SearchHold, "search-hold", config.SearchHold, "Time each search rate must meet the objective for")
+	fs.IntVar(&config.SearchMinRate, "search-min-rate", config.SearchMinRate, "Lowest rate in ops/sec the search tries")
+	fs.IntVar(&config.SearchMaxRate, "search-max-rate", config.SearchMaxRate, "Highest rate in ops/sec the search tries")
+	fs.IntVar(&config.SearchResolution, "search-resolution", config.SearchResolution, "Stop searching once the rate is known to within this many ops/sec (0 for 1% of --search-max-rate)")
+	fs.Float64Var(&config.CostPerHour, "cost-per-hour", config.CostPerHour, "Hourly infrastructure cost of the system under test, to report the cost per million operations")
+	fs.StringVar(&config.EnergyCommand, "energy-command", config.EnergyCommand, "Shell command printing the energy used (or power drawn) by the system under test, sampled every report interval")
+	fs.StringVar(&config.EnergyMode, "energy-mode", config.EnergyMode, "What --energy-command prints: energy (a cumulative joule counter, e.g. RAPL) or power (watts, e.g. IPMI)")
+	fs.StringVar(&config.ConvergenceEndpoints, "convergence-endpoints", config.ConvergenceEndpoints, "Comma-separated endpoints (host:port) to write conflicting versions through and probe for convergence")
+	fs.DurationVar(&config.ConvergenceInterval, "convergence-interval", config.ConvergenceInterval, "Time between convergence probes")
+	fs.DurationVar(&config.ConvergenceTimeout, "convergence-timeout", config.ConvergenceTimeout, "How long a convergence probe waits for the endpoints to agree")
+	fs.IntVar(&config.ResultsBufferSize, "results-buffer", config.ResultsBufferSize, "Capacity of the results channel between workers and the collector")
+	fs.BoolVar(&config.ResultsBlocking, "results-block", config.ResultsBlocking, "Block workers instead of dropping results when the results channel is full")
+	fs.StringVar(&config.CloudWatchEMF, "cloudwatch-emf", config.CloudWatchEMF, "Write CloudWatch EMF metric lines to this file every report interval (- for stdout)")
+	fs.StringVar(&config.CloudWatchNamespace, "cloudwatch-namespace", config.CloudWatchNamespace, "CloudWatch metric namespace")
+	fs.StringVar(&config.GCPProject, "gcp-project", config.GCPProject, "Google Cloud project to write Cloud Monitoring metrics to")
+	fs.StringVar(&config.GrafanaURL, "grafana-url", config.GrafanaURL, "Grafana base URL to post annotations of the run's start and phases to (e.g. http://grafana:3000)")
+	fs.StringVar(&config.GrafanaTokenFile, "grafana-token-file", config.GrafanaTokenFile, "File holding the Grafana service account token for --grafana-url")
+	fs.StringVar(&config.NotifyURLFile, "notify-url-file", config.NotifyURLFile, "File holding a webhook URL a summary of the run is posted to when it ends or aborts")
+	fs.StringVar(&config.NotifyFormat, "notify-format", config.NotifyFormat, "Body of --notify-url-file posts: json (the results schema) or slack (a Slack incoming webhook message)")
+	fs.StringVar(&config.GraphiteAddress, "graphite", config.GraphiteAddress, "Graphite/Carbon plaintext address (host:port) to push metrics to every report interval")
+	fs.StringVar(&config.GraphiteTemplate, "graphite-template", config.GraphiteTemplate, "Graphite metric path template with {method}, {metric}, {run_id} and {host} placeholders")
+	fs.StringVar(&config.StatsDAddress, "statsd", config.StatsDAddress, "StatsD/DogStatsD UDP address (host:port) to send per-operation metrics to")
+	fs.StringVar(&config.StatsDPrefix, "statsd-prefix", config.StatsDPrefix, "Prefix of StatsD metric names")
+	fs.StringVar(&config.StatsDTags, "statsd-tags", config.StatsDTags, "Comma-separated DogStatsD tags attached to every metric (e.g. env:staging,team:kv)")
+	fs.StringVar(&config.PercentileEngine, "percentile-engine", config.PercentileEngine, "Latency percentile engine: hdr (HDR histogram), tdigest, exact (all samples, up to 1M per method), kll (KLL sketch) or reservoir (uniform sample of 64K per method)")
+	fs.DurationVar(&config.LatencyMax, "latency-max", config.LatencyMax, "Highest latency recorded as measured; slower operations are counted as overflow and recorded at this bound")
+	fs.BoolVar(&config.LatencyBreakdown, "latency-breakdown", config.LatencyBreakdown, "Split operation latencies into client queueing, send, wait (network and server) and receive time, plus server time reported in a server-timing header or trailer")
+	fs.Float64Var(&config.TrimPct, "trim-pct", config.TrimPct, "Percentage of operations trimmed from each end of the latency distribution for the trimmed mean")
+	fs.StringVar(&config.Percentiles, "percentiles", config.Percentiles, "Comma-separated percentiles reported in the results table, progress lines and CSV (e.g. 50,90,99,99.9,99.99)")
+	fs.StringVar(&config.LatencyUnit, "latency-unit", config.LatencyUnit, "Unit of latencies in log and CSV output (ms, us or ns)")
+	fs.StringVar(&config.Color, "color", config.Color, "Color the final results table: auto, always or never")
+	fs.BoolVar(&config.TUI, "tui", config.TUI, "Show a live dashboard in the terminal instead of progress log lines")
+	fs.StringVar(&config.ProgressFormat, "progress-format", config.ProgressFormat, "Format of progress reports: text (log lines on stderr) or json (JSON lines on stdout)")
+	fs.StringVar(&config.ReportTemplate, "report-template", config.ReportTemplate, "Go text/template file rendered with the final results into --report-output")
+	fs.StringVar(&config.ReportOutput, "report-output", config.ReportOutput, "File the --report-template is rendered to (- for stdout)")
+	fs.StringVar(&config.JSONSummary, "json", config.JSONSummary, "Write a JSON summary with the configuration, per-method and aggregated statistics and run metadata to this file at the end (- for stdout)")
+	fs.StringVar(&config.YAMLSummary, "yaml", config.YAMLSummary, "Write the --json summary as YAML, for results kept in Git repositories, to this file at the end (- for stdout)")
+	fs.StringVar(&config.MarkdownReport, "report-md", config.MarkdownReport, "Write the final results as Markdown tables, for PR descriptions and wiki pages, to this file at the end (- for stdout)")
+	fs.StringVar(&config.HTMLReport, "report-html", config.HTMLReport, "Write a self-contained HTML report with latency and throughput charts, results tables and the configuration to this file at the end (- for stdout)")
+	fs.StringVar(&config.ChartsDir, "charts", config.ChartsDir, "Render the latency, throughput and throughput vs. latency charts of the run as image files in this directory at the end")
+	fs.StringVar(&config.ChartFormat, "chart-format", config.ChartFormat, "Comma-separated image formats of --charts: svg and/or png (png requires rsvg-convert)")
+	fs.StringVar(&config.SQLitePath, "sqlite", config.SQLitePath, "Append the configuration, per-method statistics and interval series of the run to this SQLite database (requires the sqlite3 shell)")
+	fs.StringVar(&config.OutputFormat, "format", config.OutputFormat, "Machine-readable summary written to stdout at the end: none, kv or tsv")
+	fs.StringVar(&config.CSVDelimiter, "csv-delimiter", config.CSVDelimiter, "CSV field delimiter (a single character, or tab)")
+	fs.IntVar(&config.CSVPrecision, "csv-precision", config.CSVPrecision, "Decimal places of CSV latency and rate columns (-1 for the unit default)")
+	fs.StringVar(&config.CSVTimestamps, "csv-timestamps", config.CSVTimestamps, "CSV timestamp format: iso, epoch or epoch-ms")
+	fs.BoolVar(&config.CSVIntervals, "csv-intervals", config.CSVIntervals, "Write one CSV row per method per report interval instead of a final summary")
+	fs.BoolVar(&config.CSVAppend, "csv-append", config.CSVAppend, "Append the rows to an existing --csv file with a run_id column instead of overwriting it, so that several runs accumulate in one CSV")
+	fs.BoolVar(&config.CSVMetadata, "csv-metadata", config.CSVMetadata, "Start the CSV with # comment lines describing the run: build, client, run ID and configuration")
+	fs.StringVar(&config.RunID, "run-id", config.RunID, "Run identifier attached to exported results (default: start timestamp)")
+	fs.StringVar(&config.Notes, "notes", config.Notes, "Free-form description of the run's purpose, kept in the manifest (e.g. \"testing new compaction settings\")")
+	fs.StringVar(&config.Labels, "labels", config.Labels, "Comma-separated name=value run labels attached to every CSV row, raw log record, manifest and exported metric (e.g. cluster=prod,version=1.4)")
+	fs.StringVar(&config.AgentListen, "agent-listen", config.AgentListen, "Address to accept results from external load agents (e.g. :7000)")
+	fs.StringVar(&config.AdminAddress, "admin", config.AdminAddress, "Address of the HTTP admin endpoint (e.g. :8081)")
+	fs.StringVar(&config.WebAddress, "web", config.WebAddress, "Address of the read-only live web dashboard (e.g. :8080)")
+	fs.BoolVar(&config.LogRequests, "log-requests", config.LogRequests, "Log all requests")
+	fs.BoolVar(&config.LogErrors, "log-errors", config.LogErrors, "Log error requests")
+}
+
+// RegisterClientFlags defines the flags of the connections to the target and
+// the credentials of requests on fs, shared by the benchmark and the shell
+func (config *BenchmarkConfig) RegisterClientFlags(fs *flag.FlagSet) {
+	fs.StringVar(&config.TargetAddress, "target", config.TargetAddress, "gRPC server address")
+	fs.BoolVar(&config.TLS, "tls", config.TLS, "Connect to the target with TLS")
+	fs.StringVar(&config.TLSCAFile, "tls-ca", config.TLSCAFile, "PEM bundle of the CAs to verify the target's certificate with, instead of the system roots")
+	fs.StringVar(&config.TLSServerName, "tls-server-name", config.TLSServerName, "Name to verify the target's certificate against, instead of the host of --target")
+	fs.DurationVar(&config.KeepaliveTime, "keepalive-time", config.KeepaliveTime, "Ping connections to the target after this long without activity, so proxies and load balancers keep them open (0 = off, at least 10s)")
+	fs.DurationVar(&config.KeepaliveTimeout, "keepalive-timeout", config.KeepaliveTimeout, "Close a connection whose keepalive ping is not acknowledged within this time")
+	fs.BoolVar(&config.KeepalivePermitWithoutStream, "keepalive-permit-without-stream", config.KeepalivePermitWithoutStream, "Also ping connections without active RPCs, e.g. during pauses and low-rate phases")
+	fs.StringVar(&config.Sign, "sign", config.Sign, "Sign every request with this scheme: hmac (HMAC-SHA256 over method, key and time) or a scheme registered by the embedding program")
+	fs.StringVar(&config.SignKeyID, "sign-key-id", config.SignKeyID, "ID of the signing key sent with signed requests")
+	fs.StringVar(&config.SignSecretFile, "sign-secret-file", config.SignSecretFile, "File holding the request signing secret")
+	fs.StringVar(&config.TokenCommand, "token-command", config.TokenCommand, "Shell command printing a bearer token, or an OAuth2 token response, sent with every request and rerun every --token-refresh")
+	fs.StringVar(&config.TokenFile, "token-file", config.TokenFile, "File holding a bearer token, or an OAuth2 token response, sent with every request and reread every --token-refresh")
+	fs.StringVar(&config.MetadataFile, "metadata-file", config.MetadataFile, "File of \"name: value\" lines sent as gRPC metadata with every request (e.g. x-api-key: ...), reread every --token-refresh")
+	fs.StringVar(&config.OAuth2TokenURL, "oauth2-token-url", config.OAuth2TokenURL, "OAuth2 token endpoint to obtain bearer tokens from with the client credentials grant")
+	fs.StringVar(&config.OAuth2ClientID, "oauth2-client-id", config.OAuth2ClientID, "OAuth2 client ID")
+	fs.StringVar(&config.OAuth2ClientSecretFile, "oauth2-client-secret-file", config.OAuth2ClientSecretFile, "File holding the OAuth2 client secret")
+	fs.StringVar(&config.OAuth2Scopes, "oauth2-scopes", config.OAuth2Scopes, "Comma-separated OAuth2 scopes to request")
+	fs.DurationVar(&config.TokenRefresh, "token-refresh", config.TokenRefresh, "Interval between bearer token refreshes and --metadata-file rereads; tokens reporting a lifetime are refreshed after 80% of it if sooner")
+}
+
 // LoadFromFile loads configuration from a JSON file
 func LoadFromFile(filename string) (*BenchmarkConfig, error) {
 	data, err := os.ReadFile(filename)
@@ -87,8 +590,8 @@ func LoadFromFile(filename string) (*BenchmarkConfig, error) {
 
 // Validate checks if the configuration is valid
 func (c *BenchmarkConfig) Validate() error {
-	if c.TargetAddress == "" {
-		return fmt.Errorf("target address cannot be empty")
+	if err := c.ValidateClient(); err != nil {
+		return err
 	}
 	if c.NumConnections <= 0 {
 		return fmt.Errorf("number of connections must be positive")
@@ -105,16 +608,378 @@ func (c *BenchmarkConfig) Validate() error {
 	if c.ValueSize <= 0 {
 		return fmt.Errorf("value size must be positive")
 	}
-	if c.ReadRatio < 0 || c.WriteRatio < 0 || c.DeleteRatio < 0 {
+	switch c.KeyEncoding {
+	case "random", "raw", "hashed", "ordered", "composite":
+	default:
+		return fmt.Errorf("unknown key encoding %q (expected random, raw, hashed, ordered or composite)", c.KeyEncoding)
+	}
+	if c.KeyEncoding == "composite" && c.KeyTenants <= 0 {
+		return fmt.Errorf("composite keys require a positive number of tenants")
+	}
+	if _, err := collector.ParseTags(c.OpTags); err != nil {
+		return err
+	}
+	if _, err := collector.ParseLabels(c.Labels); err != nil {
+		return err
+	}
+	if c.ReadRatio < 0 || c.WriteRatio < 0 || c.DeleteRatio < 0 || c.IndexWriteRatio < 0 || c.IndexReadRatio < 0 {
 		return fmt.Errorf("operation ratios cannot be negative")
 	}
-	if c.ReadRatio+c.WriteRatio+c.DeleteRatio != 100 {
+	if c.ReadRatio+c.WriteRatio+c.DeleteRatio+c.IndexWriteRatio+c.IndexReadRatio != 100 {
 		return fmt.Errorf("operation ratios must sum to 100")
 	}
+	if c.GetRateLimit < 0 || c.PutRateLimit < 0 || c.DeleteRateLimit < 0 {
+		return fmt.Errorf("operation rate limits cannot be negative")
+	}
+	if c.CalibrateDuration < 0 {
+		return fmt.Errorf("calibration duration cannot be negative")
+	}
+	if c.CalibrateSubtract && c.CalibrateDuration == 0 {
+		return fmt.Errorf("--calibrate-subtract requires --calibrate")
+	}
+	if c.CostPerHour < 0 {
+		return fmt.Errorf("cost per hour cannot be negative")
+	}
+	switch c.EnergyMode {
+	case "energy", "power":
+	default:
+		return fmt.Errorf("unknown energy mode %q (expected energy or power)", c.EnergyMode)
+	}
+	if c.ConvergenceEndpoints != "" && (c.ConvergenceInterval <= 0 || c.ConvergenceTimeout <= 0) {
+		return fmt.Errorf("convergence interval and timeout must be positive")
+	}
+	if c.RawLogSample <= 0 {
+		return fmt.Errorf("raw log sample rate must be positive")
+	}
+	if c.AlignIntervals && (c.ReportInterval <= 0 || (24*time.Hour)%c.ReportInterval != 0) {
+		return fmt.Errorf("aligned report intervals must divide a day evenly (e.g. 1s, 5s, 1m)")
+	}
+	if c.ResultsBufferSize <= 0 {
+		return fmt.Errorf("results buffer size must be positive")
+	}
+	if _, err := collector.ParseEngine(c.PercentileEngine); err != nil {
+		return err
+	}
+	if _, err := collector.ParsePercentiles(c.Percentiles); err != nil {
+		return err
+	}
+	if c.LatencyMax < time.Microsecond {
+		return fmt.Errorf("latency bound must be at least 1us")
+	}
+	if c.TrimPct < 0 || c.TrimPct >= 50 {
+		return fmt.Errorf("trim percentage must be in [0, 50)")
+	}
+	if c.HistogramLog != "" && c.PercentileEngine != collector.EngineHDR {
+		return fmt.Errorf("histogram log requires the hdr percentile engine")
+	}
+	if c.GrafanaTokenFile != "" && c.GrafanaURL == "" {
+		return fmt.Errorf("--grafana-token-file requires --grafana-url")
+	}
+	switch c.NotifyFormat {
+	case "json", "slack":
+	default:
+		return fmt.Errorf("unknown notification format %q (expected json or slack)", c.NotifyFormat)
+	}
+	if c.PostRun != "" && len(strings.Fields(c.PostRun)) == 0 {
+		return fmt.Errorf("--post-run command cannot be blank")
+	}
+	if c.UploadURL != "" {
+		if _, err := objstore.Parse(c.UploadURL); err != nil {
+			return err
+		}
+		if _, err := template.New("upload-key").Parse(c.UploadKey); err != nil {
+			return fmt.Errorf("invalid upload key template: %w", err)
+		}
+	}
+	if c.GraphiteAddress != "" && (!strings.Contains(c.GraphiteTemplate, "{method}") || !strings.Contains(c.GraphiteTemplate, "{metric}")) {
+		return fmt.Errorf("graphite template must contain the {method} and {metric} placeholders")
+	}
+	if c.HistogramStore != "" && c.PercentileEngine != collector.EngineHDR {
+		return fmt.Errorf("histogram store requires the hdr percentile engine")
+	}
+	if _, err := latency.ParseUnit(c.LatencyUnit); err != nil {
+		return err
+	}
+	switch c.Color {
+	case "auto", "always", "never":
+	default:
+		return fmt.Errorf("unknown color mode %q (expected auto, always or never)", c.Color)
+	}
+	for _, format := range strings.Split(c.ChartFormat, ",") {
+		switch strings.TrimSpace(format) {
+		case "svg", "png":
+		default:
+			return fmt.Errorf("unknown chart format %q (expected svg or png)", format)
+		}
+	}
+	switch c.ProgressFormat {
+	case "text":
+	case "json":
+		if c.TUI {
+			return fmt.Errorf("--tui cannot be combined with --progress-format=json")
+		}
+	default:
+		return fmt.Errorf("unknown progress format %q (expected text or json)", c.ProgressFormat)
+	}
+	switch c.OutputFormat {
+	case "none", "kv", "tsv":
+	default:
+		return fmt.Errorf("unknown output format %q (expected none, kv or tsv)", c.OutputFormat)
+	}
+	if _, err := collector.ParseCSVDelimiter(c.CSVDelimiter); err != nil {
+		return err
+	}
+	if c.CSVPrecision > 9 {
+		return fmt.Errorf("CSV precision cannot exceed 9 decimal places")
+	}
+	if c.CSVAppend && (c.OutputCSV == "" || c.OutputCSV == "-") {
+		return fmt.Errorf("--csv-append requires a --csv file")
+	}
+	switch c.CSVTimestamps {
+	case "iso", "epoch", "epoch-ms":
+	default:
+		return fmt.Errorf("unknown CSV timestamp format %q (expected iso, epoch or epoch-ms)", c.CSVTimestamps)
+	}
+	if writers := c.StdoutWriters(); len(writers) > 1 {
+		return fmt.Errorf("only one output can be written to stdout, got %s", strings.Join(writers, ", "))
+	}
+	if c.OpTimeout < 0 || c.OpTimeoutJitter < 0 || c.WorkerStartJitter < 0 {
+		return fmt.Errorf("operation timeout and jitter cannot be negative")
+	}
+	if c.OpTimeoutJitter > 0 && c.OpTimeout == 0 {
+		return fmt.Errorf("operation timeout jitter requires an operation timeout")
+	}
+	if c.ThrottleBackoff < 0 || c.ThrottleBackoffMax < 0 {
+		return fmt.Errorf("throttle backoff cannot be negative")
+	}
+	if c.ThrottleBackoff > 0 && c.ThrottleBackoffMax < c.ThrottleBackoff {
+		return fmt.Errorf("maximum throttle backoff %v is shorter than the initial backoff %v", c.ThrottleBackoffMax, c.ThrottleBackoff)
+	}
+	if c.CheckpointPath != "" && c.CheckpointInterval <= 0 {
+		return fmt.Errorf("checkpoint interval must be positive")
+	}
+	if c.Resume && c.CheckpointPath == "" {
+		return fmt.Errorf("resume requires a checkpoint file")
+	}
+	if c.RampUp < 0 {
+		return fmt.Errorf("ramp-up window cannot be negative")
+	}
+	if c.RampUp >= c.Duration {
+		return fmt.Errorf("ramp-up window must be shorter than the benchmark duration")
+	}
+	if c.RampUp > 0 && c.TimelinePath != "" {
+		return fmt.Errorf("ramp-up cannot be combined with a timeline (use timeline steps to ramp workers)")
+	}
+	phases, err := slo.ParsePhases(c.Phases)
+	if err != nil {
+		return err
+	}
+	if len(phases) > 0 && phases[len(phases)-1].Offset >= c.Duration {
+		return fmt.Errorf("phase %q must start before the end of the benchmark", phases[len(phases)-1].Name)
+	}
+	assertions, err := slo.ParseAssertions(c.SLOs)
+	if err != nil {
+		return err
+	}
+	if err := slo.Validate(assertions, phases); err != nil {
+		return err
+	}
+	if _, err := collector.ParseLatencySLOs(c.LatencySLOs); err != nil {
+		return err
+	}
+	if c.Repeat < 1 {
+		return fmt.Errorf("repeat count must be at least 1")
+	}
+	if c.CoolDown < 0 {
+		return fmt.Errorf("cool-down cannot be negative")
+	}
+	if c.Repeat > 1 && c.Resume {
+		return fmt.Errorf("repeated runs cannot be resumed")
+	}
+	if _, err := compare.ParseThresholds(c.FailOn); err != nil {
+		return err
+	}
+	if c.FailOn != "" && c.Baseline == "" {
+		return fmt.Errorf("--fail-on requires --baseline")
+	}
+	if err := c.validateSearch(); err != nil {
+		return err
+	}
+	if c.DutyCycleOn < 0 || c.DutyCycleOff < 0 {
+		return fmt.Errorf("duty cycle windows cannot be negative")
+	}
+	if (c.DutyCycleOn > 0) != (c.DutyCycleOff > 0) {
+		return fmt.Errorf("duty cycle requires both on and off windows")
+	}
 
 	return nil
 }
 
+// ValidateClient checks the settings of the connections to the target and the
+// credentials of requests
+func (c *BenchmarkConfig) ValidateClient() error {
+	if c.TargetAddress == "" {
+		return fmt.Errorf("target address cannot be empty")
+	}
+	if !c.TLS && (c.TLSCAFile != "" || c.TLSServerName != "") {
+		return fmt.Errorf("--tls-ca and --tls-server-name require --tls")
+	}
+	if c.KeepaliveTime < 0 {
+		return fmt.Errorf("keepalive time cannot be negative")
+	}
+	if c.KeepaliveTime > 0 && c.KeepaliveTime < 10*time.Second {
+		return fmt.Errorf("keepalive time must be at least 10s, the minimum gRPC allows")
+	}
+	if c.KeepaliveTime > 0 && c.KeepaliveTimeout <= 0 {
+		return fmt.Errorf("keepalive timeout must be positive")
+	}
+	if c.KeepalivePermitWithoutStream && c.KeepaliveTime == 0 {
+		return fmt.Errorf("--keepalive-permit-without-stream requires --keepalive-time")
+	}
+	if c.Sign != "" && !slices.Contains(kvclient.Signers(), c.Sign) {
+		return fmt.Errorf("unknown request signing scheme %q (expected one of %v)", c.Sign, kvclient.Signers())
+	}
+	if c.Sign == "" && (c.SignKeyID != "" || c.SignSecretFile != "") {
+		return fmt.Errorf("signing key ID and secret require a signing scheme")
+	}
+	if (c.TokenCommand != "" && c.TokenFile != "") || ((c.TokenCommand != "" || c.TokenFile != "") && c.OAuth2TokenURL != "") {
+		return fmt.Errorf("token command, token file and OAuth2 token URL are mutually exclusive")
+	}
+	if c.OAuth2TokenURL != "" && c.OAuth2ClientID == "" {
+		return fmt.Errorf("OAuth2 token URL requires a client ID")
+	}
+	if (c.TokenCommand != "" || c.TokenFile != "" || c.OAuth2TokenURL != "" || c.MetadataFile != "") && c.TokenRefresh <= 0 {
+		return fmt.Errorf("token refresh interval must be positive")
+	}
+	return nil
+}
+
+// validateSearch checks the rate search settings
+func (c *BenchmarkConfig) validateSearch() error {
+	if c.SearchSLO == "" {
+		return nil
+	}
+
+	objectives, err := slo.ParseAssertions(c.SearchSLO)
+	if err != nil {
+		return err
+	}
+	if len(objectives) != 1 {
+		return fmt.Errorf("search objective must be a single assertion")
+	}
+	if o := objectives[0]; o.Phase != slo.RunPhase || o.Metric == "throughput" {
+		return fmt.Errorf("search objective %q must be a latency or error_rate assertion without a phase", o.Text)
+	}
+	if c.SearchHold <= 0 {
+		return fmt.Errorf("search hold must be positive")
+	}
+	if c.SearchMaxRate <= 0 {
+		return fmt.Errorf("search requires a positive maximum rate")
+	}
+	if c.SearchMinRate < 0 || c.SearchMinRate > c.SearchMaxRate {
+		return fmt.Errorf("search minimum rate must be between 0 and the maximum rate")
+	}
+	if c.SearchResolution < 0 {
+		return fmt.Errorf("search resolution cannot be negative")
+	}
+	if c.Phases != "" || c.TimelinePath != "" || c.RampUp > 0 || c.DutyCycleOn > 0 || c.CheckpointPath != "" {
+		return fmt.Errorf("search cannot be combined with phases, a timeline, ramp-up, a duty cycle or checkpoints")
+	}
+	return nil
+}
+
+// StdoutWriters returns the flags of the machine-readable outputs configured
+// to write to stdout. Human-readable logs always go to stderr.
+func (c *BenchmarkConfig) StdoutWriters() []string {
+	var writers []string
+	if c.OutputFormat != "none" {
+		writers = append(writers, "--format")
+	}
+	if c.ProgressFormat == "json" {
+		writers = append(writers, "--progress-format")
+	}
+	if c.OutputCSV == "-" {
+		writers = append(writers, "--csv")
+	}
+	if c.ReportTemplate != "" && c.ReportOutput == "-" {
+		writers = append(writers, "--report-template")
+	}
+	if c.JSONSummary == "-" {
+		writers = append(writers, "--json")
+	}
+	if c.YAMLSummary == "-" {
+		writers = append(writers, "--yaml")
+	}
+	if c.MarkdownReport == "-" {
+		writers = append(writers, "--report-md")
+	}
+	if c.HTMLReport == "-" {
+		writers = append(writers, "--report-html")
+	}
+	if c.RawLogPath == "-" {
+		writers = append(writers, "--raw-log")
+	}
+	if c.CloudWatchEMF == "-" {
+		writers = append(writers, "--cloudwatch-emf")
+	}
+	return writers
+}
+
+// WithSuffix returns a copy of the configuration for one run of a sequence,
+// such as a scenario step or a repetition: the run ID, if set, and the per-run
+// output files get the suffix (before the extension) so that the runs do not
+// overwrite each other. Stores every run appends to, such as --sqlite,
+// --histogram-store and --csv with --csv-append, the generator state and
+// stdout ("-") are left alone.
+func (c *BenchmarkConfig) WithSuffix(suffix string) *BenchmarkConfig {
+	cfg := *c
+	if cfg.RunID != "" {
+		cfg.RunID += suffix
+	}
+	if !cfg.CSVAppend {
+		cfg.OutputCSV = suffixPath(cfg.OutputCSV, suffix)
+	}
+	for _, p := range []*string{
+		&cfg.ArchivePath, &cfg.RawLogPath, &cfg.ParquetRaw, &cfg.ParquetIntervals,
+		&cfg.HistogramLog, &cfg.HeatmapPath, &cfg.CurvePath, &cfg.ManifestPath,
+		&cfg.JSONSummary, &cfg.YAMLSummary, &cfg.MarkdownReport, &cfg.HTMLReport, &cfg.ReportOutput,
+		&cfg.ChartsDir,
+	} {
+		*p = suffixPath(*p, suffix)
+	}
+	return &cfg
+}
+
+// OutputFiles returns the files the run writes its results to, for --upload.
+// Stdout ("-"), the --charts directory and databases such as --sqlite are
+// not included.
+func (c *BenchmarkConfig) OutputFiles() []string {
+	var files []string
+	for _, path := range []string{
+		c.OutputCSV, c.ArchivePath, c.RawLogPath, c.ParquetRaw, c.ParquetIntervals,
+		c.HistogramLog, c.HeatmapPath, c.CurvePath, c.ManifestPath,
+		c.JSONSummary, c.YAMLSummary, c.MarkdownReport, c.HTMLReport,
+	} {
+		if path != "" && path != "-" {
+			files = append(files, path)
+		}
+	}
+	if c.ReportTemplate != "" && c.ReportOutput != "-" {
+		files = append(files, c.ReportOutput)
+	}
+	return files
+}
+
+// suffixPath inserts a suffix before the extension of a path, leaving empty
+// paths and stdout ("-") alone
+func suffixPath(path, suffix string) string {
+	if path == "" || path == "-" {
+		return path
+	}
+	ext := filepath.Ext(path)
+	return strings.TrimSuffix(path, ext) + suffix + ext
+}
+
 // String returns a string representation of the configuration
 func (c *BenchmarkConfig) String() string {
 	return fmt.Sprintf(
@@ -0,0 +1,140 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"sync/atomic"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	apiv1 "kvstore-benchmarker/api/v1"
+	"kvstore-benchmarker/pkg/archive"
+	"kvstore-benchmarker/pkg/collector"
+	"kvstore-benchmarker/pkg/config"
+)
+
+// Server accepts results from external load agents and feeds them into the collector
+type Server struct {
+	apiv1.UnimplementedAgentControllerServer
+
+	config    *config.BenchmarkConfig
+	collector *collector.Collector
+	grpc      *grpc.Server
+	accepting atomic.Bool
+}
+
+// NewServer creates an agent controller server for the given run
+func NewServer(cfg *config.BenchmarkConfig, c *collector.Collector) *Server {
+	s := &Server{
+		config:    cfg,
+		collector: c,
+		grpc:      grpc.NewServer(),
+	}
+	apiv1.RegisterAgentControllerServer(s.grpc, s)
+	return s
+}
+
+// Start listens on the given address and serves agents in the background
+func (s *Server) Start(address string) error {
+	lis, err := net.Listen("tcp", address)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", address, err)
+	}
+
+	go func() {
+		if err := s.grpc.Serve(lis); err != nil {
+			log.Printf("Warning: agent controller stopped: %v", err)
+		}
+	}()
+
+	log.Printf("Agent controller listening on %s", lis.Addr())
+	return nil
+}
+
+// SetAccepting controls whether streamed results are aggregated or discarded
+func (s *Server) SetAccepting(accepting bool) {
+	s.accepting.Store(accepting)
+}
+
+// Stop closes all agent streams and stops the server
+func (s *Server) Stop() {
+	s.grpc.Stop()
+}
+
+// Register returns the workload assignment for an agent
+func (s *Server) Register(ctx context.Context, req *apiv1.RegisterRequest) (*apiv1.Assignment, error) {
+	if req.AgentId == "" {
+		return nil, status.Error(codes.InvalidArgument, "agent_id is required")
+	}
+
+	log.Printf("Agent %s registered (%s)", req.AgentId, req.AgentVersion)
+
+	return &apiv1.Assignment{
+		TargetAddress:    s.config.TargetAddress,
+		NumConnections:   int32(s.config.NumConnections),
+		NumWorkers:       int32(s.config.NumWorkers),
+		DurationNs:       s.config.Duration.Nanoseconds(),
+		WarmupDurationNs: s.config.WarmupDuration.Nanoseconds(),
+		KeySpace:         int32(s.config.KeySpace),
+		ValueSize:        int32(s.config.ValueSize),
+		ReadRatio:        int32(s.config.ReadRatio),
+		WriteRatio:       int32(s.config.WriteRatio),
+		DeleteRatio:      int32(s.config.DeleteRatio),
+		IndexWriteRatio:  int32(s.config.IndexWriteRatio),
+		IndexReadRatio:   int32(s.config.IndexReadRatio),
+	}, nil
+}
+
+// StreamResults aggregates operation results streamed by an agent
+func (s *Server) StreamResults(stream apiv1.AgentController_StreamResultsServer) error {
+	first, err := stream.Recv()
+	if err != nil {
+		return err
+	}
+	header := first.GetHeader()
+	if header == nil || header.AgentId == "" {
+		return status.Error(codes.InvalidArgument, "first frame must be a run header with agent_id")
+	}
+	start := time.Unix(0, header.StartUnixNano)
+
+	resp := &apiv1.StreamResultsResponse{}
+	for {
+		frame, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return stream.SendAndClose(resp)
+		}
+		if err != nil {
+			return err
+		}
+
+		// Agents' own intervals and summaries are recomputed by the collector
+		op := frame.GetOp()
+		if op == nil {
+			continue
+		}
+		if !s.accepting.Load() {
+			resp.Discarded++
+			continue
+		}
+
+		result := &collector.BenchmarkResult{
+			Method:    archive.MethodName(op),
+			LatencyMs: float64(op.LatencyNs) / float64(time.Millisecond),
+			Timestamp: start.Add(time.Duration(op.OffsetNs)),
+			Worker:    -1,
+			Agent:     header.AgentId,
+		}
+		if op.Error != "" {
+			result.Error = errors.New(op.Error)
+		}
+		s.collector.AddResult(result)
+		resp.Accepted++
+	}
+}
@@ -0,0 +1,97 @@
+package grafana
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Annotator posts annotations to the Grafana HTTP API in the background, so
+// that a slow or unavailable Grafana never holds up a benchmark
+type Annotator struct {
+	url    string
+	token  string
+	tags   []string
+	client *http.Client
+	wg     sync.WaitGroup
+}
+
+// annotation is the request body of POST /api/annotations
+type annotation struct {
+	Time    int64    `json:"time"`              // Milliseconds since the epoch
+	TimeEnd int64    `json:"timeEnd,omitempty"` // End of a region annotation
+	Tags    []string `json:"tags"`
+	Text    string   `json:"text"`
+}
+
+// NewAnnotator creates an annotator for the Grafana at baseURL, authorized
+// with a service account token unless token is empty. Annotations carry the
+// AnnotationTag and the given tags, e.g. the run ID and labels.
+func NewAnnotator(baseURL, token string, tags []string) *Annotator {
+	return &Annotator{
+		url:    strings.TrimSuffix(baseURL, "/") + "/api/annotations",
+		token:  token,
+		tags:   append([]string{AnnotationTag}, tags...),
+		client: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// Point posts an annotation of an instant
+func (a *Annotator) Point(at time.Time, text string) {
+	a.post(annotation{Time: at.UnixMilli(), Tags: a.tags, Text: text})
+}
+
+// Region posts an annotation of a time range
+func (a *Annotator) Region(start, end time.Time, text string) {
+	a.post(annotation{Time: start.UnixMilli(), TimeEnd: end.UnixMilli(), Tags: a.tags, Text: text})
+}
+
+// Wait waits until the posted annotations were delivered or failed
+func (a *Annotator) Wait() {
+	a.wg.Wait()
+}
+
+// post sends an annotation in the background, logging failures
+func (a *Annotator) post(body annotation) {
+	a.wg.Add(1)
+	go func() {
+		defer a.wg.Done()
+		if err := a.send(body); err != nil {
+			log.Printf("Warning: failed to post Grafana annotation %q: %v", body.Text, err)
+		}
+	}()
+}
+
+// send posts an annotation
+func (a *Annotator) send(body annotation) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest(http.MethodPost, a.url, bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if a.token != "" {
+		req.Header.Set("Authorization", "Bearer "+a.token)
+	}
+
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("server returned %s: %s", resp.Status, bytes.TrimSpace(msg))
+	}
+	return nil
+}
@@ -0,0 +1,179 @@
+// Package grafana generates a Grafana dashboard for the metrics the
+// benchmarker exports to Prometheus and posts annotations marking the phases
+// of runs, so that load windows line up with server-side graphs.
+package grafana
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// AnnotationTag is the tag of every annotation the benchmarker posts, which
+// the generated dashboard shows
+const AnnotationTag = "kvbench"
+
+// DashboardOptions customize the generated dashboard
+type DashboardOptions struct {
+	Title  string
+	UID    string
+	Labels []string // Run label names to filter by, e.g. "team"
+}
+
+// panel is a time series panel of the dashboard
+type panel struct {
+	ID         int             `json:"id"`
+	Type       string          `json:"type"`
+	Title      string          `json:"title"`
+	Datasource datasource      `json:"datasource"`
+	GridPos    gridPos         `json:"gridPos"`
+	FieldCfg   fieldConfig     `json:"fieldConfig"`
+	Targets    []target        `json:"targets"`
+	Options    json.RawMessage `json:"options"`
+}
+
+type datasource struct {
+	Type string `json:"type"`
+	UID  string `json:"uid"`
+}
+
+type gridPos struct {
+	H int `json:"h"`
+	W int `json:"w"`
+	X int `json:"x"`
+	Y int `json:"y"`
+}
+
+type fieldConfig struct {
+	Defaults struct {
+		Unit string `json:"unit"`
+	} `json:"defaults"`
+	Overrides []any `json:"overrides"`
+}
+
+type target struct {
+	RefID        string     `json:"refId"`
+	Datasource   datasource `json:"datasource"`
+	Expr         string     `json:"expr"`
+	LegendFormat string     `json:"legendFormat"`
+}
+
+// variable is a template variable of the dashboard
+type variable struct {
+	Name       string      `json:"name"`
+	Label      string      `json:"label"`
+	Type       string      `json:"type"`
+	Query      any         `json:"query"`
+	Datasource *datasource `json:"datasource,omitempty"`
+	Refresh    int         `json:"refresh,omitempty"`
+	Multi      bool        `json:"multi,omitempty"`
+	IncludeAll bool        `json:"includeAll,omitempty"`
+	AllValue   string      `json:"allValue,omitempty"`
+	Current    any         `json:"current"`
+}
+
+// prometheus is the data source of the panels, picked on the dashboard
+var prometheus = datasource{Type: "prometheus", UID: "${datasource}"}
+
+// Dashboard returns the JSON model of a dashboard charting the throughput,
+// errors and latencies exported with --remote-write or --pushgateway by
+// method, with the benchmark annotations overlaid. It can be imported in the
+// Grafana UI or provisioned from a file.
+func Dashboard(opts DashboardOptions) ([]byte, error) {
+	if opts.Title == "" {
+		opts.Title = "KV Store Benchmark"
+	}
+	if opts.UID == "" {
+		opts.UID = "kvbench"
+	}
+
+	variables := []variable{
+		{Name: "datasource", Label: "Data source", Type: "datasource", Query: "prometheus", Current: map[string]any{}},
+	}
+	matchers := []string{`job="kvstore-benchmarker"`}
+	for _, name := range append([]string{"method"}, opts.Labels...) {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		variables = append(variables, variable{
+			Name:       name,
+			Label:      name,
+			Type:       "query",
+			Query:      fmt.Sprintf(`label_values(kvbench_operations_total{job="kvstore-benchmarker"}, %s)`, name),
+			Datasource: &prometheus,
+			Refresh:    2, // On time range change
+			Multi:      true,
+			IncludeAll: true,
+			AllValue:   ".*",
+			Current:    map[string]any{"text": "All", "value": "$__all"},
+		})
+		matchers = append(matchers, fmt.Sprintf(`%s=~"$%s"`, name, name))
+	}
+	selector := "{" + strings.Join(matchers, ", ") + "}"
+
+	rate := func(metric string) string {
+		return fmt.Sprintf("sum by (method) (rate(%s%s[$__rate_interval]))", metric, selector)
+	}
+	panels := []panel{
+		newPanel("Throughput", "ops", target{Expr: rate("kvbench_operations_total"), LegendFormat: "{{method}}"}),
+		newPanel("Errors", "ops", target{Expr: rate("kvbench_errors_total"), LegendFormat: "{{method}}"}),
+		newPanel("Latency percentiles", "ms", target{Expr: "kvbench_latency_ms" + selector, LegendFormat: "{{method}} q{{quantile}}"}),
+		newPanel("Average and maximum latency", "ms",
+			target{Expr: "kvbench_latency_avg_ms" + selector, LegendFormat: "{{method}} avg"},
+			target{Expr: "kvbench_latency_max_ms" + selector, LegendFormat: "{{method}} max"}),
+		newPanel("Error rate", "percent", target{
+			Expr:         "100 * " + rate("kvbench_errors_total") + " / " + rate("kvbench_operations_total"),
+			LegendFormat: "{{method}}",
+		}),
+	}
+	for i := range panels {
+		panels[i].ID = i + 1
+		panels[i].GridPos = gridPos{H: 8, W: 12, X: i % 2 * 12, Y: i / 2 * 8}
+	}
+
+	dashboard := map[string]any{
+		"uid":           opts.UID,
+		"title":         opts.Title,
+		"tags":          []string{AnnotationTag},
+		"schemaVersion": 39,
+		"editable":      true,
+		"refresh":       "10s",
+		"time":          map[string]string{"from": "now-1h", "to": "now"},
+		"templating":    map[string]any{"list": variables},
+		"annotations": map[string]any{"list": []any{
+			map[string]any{
+				"name":       "Benchmark phases",
+				"datasource": datasource{Type: "grafana", UID: "-- Grafana --"},
+				"enable":     true,
+				"iconColor":  "rgba(255, 152, 48, 1)",
+				"target": map[string]any{
+					"type":     "tags",
+					"tags":     []string{AnnotationTag},
+					"matchAny": false,
+					"limit":    100,
+				},
+			},
+		}},
+		"panels": panels,
+	}
+	return json.MarshalIndent(dashboard, "", "  ")
+}
+
+// newPanel creates a time series panel of the given targets and unit
+func newPanel(title, unit string, targets ...target) panel {
+	p := panel{
+		Type:       "timeseries",
+		Title:      title,
+		Datasource: prometheus,
+		Targets:    targets,
+		Options:    json.RawMessage(`{"legend": {"displayMode": "list", "placement": "bottom"}, "tooltip": {"mode": "multi"}}`),
+	}
+	p.FieldCfg.Defaults.Unit = unit
+	p.FieldCfg.Overrides = []any{}
+	for i := range p.Targets {
+		p.Targets[i].RefID = string(rune('A' + i))
+		p.Targets[i].Datasource = prometheus
+	}
+	return p
+}
@@ -0,0 +1,101 @@
+// Package histstore implements an append-only on-disk log of interval latency
+// histograms. Each report interval appends one compressed HdrHistogram per
+// method, so a run of any length keeps only the current interval in memory
+// while percentiles over any window can be computed after the run at full
+// histogram resolution.
+//
+// The file starts with an 8-byte magic and the run start time (unix
+// nanoseconds, big-endian int64). Each record is a big-endian uint32 payload
+// length, the CRC-32 (IEEE) of the payload and the payload: interval start
+// and end offsets from the run start (int64 nanoseconds), the method name
+// (uint16 length and bytes) and the histogram in the compressed HdrHistogram
+// V2 encoding. A record truncated by a crash ends the readable log.
+package histstore
+
+import (
+	"bufio"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"hash/crc32"
+	"os"
+	"time"
+
+	"github.com/HdrHistogram/hdrhistogram-go"
+)
+
+// Magic identifies histogram store files and their format version
+const Magic = "KVBHST01"
+
+// headerSize is the size of the file header in bytes
+const headerSize = len(Magic) + 8
+
+// Writer appends interval histograms to a store file
+type Writer struct {
+	file  *os.File
+	buf   *bufio.Writer
+	start time.Time
+}
+
+// Create creates a store file for a run starting at start
+func Create(path string, start time.Time) (*Writer, error) {
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create histogram store: %w", err)
+	}
+
+	w := &Writer{file: file, buf: bufio.NewWriterSize(file, 64*1024), start: start}
+	w.buf.WriteString(Magic)
+	binary.Write(w.buf, binary.BigEndian, start.UnixNano())
+	if err := w.Flush(); err != nil {
+		file.Close()
+		return nil, err
+	}
+	return w, nil
+}
+
+// Append adds the histogram of one method over the interval [from, to)
+func (w *Writer) Append(method string, from, to time.Time, h *hdrhistogram.Histogram) error {
+	encoded, err := h.Encode(hdrhistogram.V2CompressedEncodingCookieBase)
+	if err != nil {
+		return fmt.Errorf("failed to encode histogram: %w", err)
+	}
+	histogram, err := base64.StdEncoding.DecodeString(string(encoded))
+	if err != nil {
+		return fmt.Errorf("failed to encode histogram: %w", err)
+	}
+
+	payload := make([]byte, 0, 18+len(method)+len(histogram))
+	payload = binary.BigEndian.AppendUint64(payload, uint64(from.Sub(w.start).Nanoseconds()))
+	payload = binary.BigEndian.AppendUint64(payload, uint64(to.Sub(w.start).Nanoseconds()))
+	payload = binary.BigEndian.AppendUint16(payload, uint16(len(method)))
+	payload = append(payload, method...)
+	payload = append(payload, histogram...)
+
+	var header [8]byte
+	binary.BigEndian.PutUint32(header[0:4], uint32(len(payload)))
+	binary.BigEndian.PutUint32(header[4:8], crc32.ChecksumIEEE(payload))
+	w.buf.Write(header[:])
+	if _, err := w.buf.Write(payload); err != nil {
+		return fmt.Errorf("failed to write histogram store record: %w", err)
+	}
+	return nil
+}
+
+// Flush writes buffered records to the file, so that completed intervals
+// survive a crash of the benchmark process
+func (w *Writer) Flush() error {
+	if err := w.buf.Flush(); err != nil {
+		return fmt.Errorf("failed to flush histogram store: %w", err)
+	}
+	return nil
+}
+
+// Close flushes buffered records and closes the file
+func (w *Writer) Close() error {
+	if err := w.Flush(); err != nil {
+		w.file.Close()
+		return err
+	}
+	return w.file.Close()
+}
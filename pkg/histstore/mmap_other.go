@@ -0,0 +1,17 @@
+//go:build !unix
+
+package histstore
+
+import (
+	"io"
+	"os"
+)
+
+// mapFile reads the whole file on platforms without mmap support
+func mapFile(file *os.File) ([]byte, func() error, error) {
+	data, err := io.ReadAll(file)
+	if err != nil {
+		return nil, nil, err
+	}
+	return data, nil, nil
+}
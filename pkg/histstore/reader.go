@@ -0,0 +1,145 @@
+package histstore
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"hash/crc32"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/HdrHistogram/hdrhistogram-go"
+)
+
+// Interval is the index entry of one stored histogram
+type Interval struct {
+	Method string
+	Start  time.Duration // Offset from the run start
+	End    time.Duration
+	data   []byte
+}
+
+// Reader queries a histogram store file. The file is memory-mapped where the
+// platform supports it, so only the histograms a query touches are paged in.
+type Reader struct {
+	data      []byte
+	unmap     func() error
+	start     time.Time
+	intervals []Interval
+}
+
+// Open opens a store file and indexes its records
+func Open(path string) (*Reader, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open histogram store: %w", err)
+	}
+	defer file.Close()
+
+	data, unmap, err := mapFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to map histogram store: %w", err)
+	}
+
+	r := &Reader{data: data, unmap: unmap}
+	if err := r.index(); err != nil {
+		r.Close()
+		return nil, err
+	}
+	return r, nil
+}
+
+// index parses the header and the record boundaries, stopping at the first
+// truncated or corrupt record
+func (r *Reader) index() error {
+	if len(r.data) < headerSize || string(r.data[:len(Magic)]) != Magic {
+		return fmt.Errorf("not a histogram store file")
+	}
+	r.start = time.Unix(0, int64(binary.BigEndian.Uint64(r.data[len(Magic):headerSize])))
+
+	for pos := headerSize; pos+8 <= len(r.data); {
+		size := int(binary.BigEndian.Uint32(r.data[pos : pos+4]))
+		sum := binary.BigEndian.Uint32(r.data[pos+4 : pos+8])
+		pos += 8
+		if pos+size > len(r.data) || size < 18 {
+			break
+		}
+		payload := r.data[pos : pos+size]
+		pos += size
+		if crc32.ChecksumIEEE(payload) != sum {
+			break
+		}
+
+		methodLen := int(binary.BigEndian.Uint16(payload[16:18]))
+		if 18+methodLen > len(payload) {
+			break
+		}
+		r.intervals = append(r.intervals, Interval{
+			Method: string(payload[18 : 18+methodLen]),
+			Start:  time.Duration(binary.BigEndian.Uint64(payload[0:8])),
+			End:    time.Duration(binary.BigEndian.Uint64(payload[8:16])),
+			data:   payload[18+methodLen:],
+		})
+	}
+	return nil
+}
+
+// Start returns the run start time
+func (r *Reader) Start() time.Time {
+	return r.start
+}
+
+// Intervals returns the index of all stored histograms in file order
+func (r *Reader) Intervals() []Interval {
+	return r.intervals
+}
+
+// Methods returns the sorted names of the stored methods
+func (r *Reader) Methods() []string {
+	seen := make(map[string]bool)
+	var methods []string
+	for _, interval := range r.intervals {
+		if !seen[interval.Method] {
+			seen[interval.Method] = true
+			methods = append(methods, interval.Method)
+		}
+	}
+	sort.Strings(methods)
+	return methods
+}
+
+// Query merges the histograms of the intervals lying entirely within the
+// window [from, to] of offsets from the run start. An empty method selects all
+// methods and a non-positive to selects the end of the run. The returned
+// histogram records nanoseconds; n is the number of intervals merged.
+func (r *Reader) Query(method string, from, to time.Duration) (h *hdrhistogram.Histogram, n int, err error) {
+	for _, interval := range r.intervals {
+		if method != "" && interval.Method != method {
+			continue
+		}
+		if interval.Start < from || (to > 0 && interval.End > to) {
+			continue
+		}
+
+		decoded, err := hdrhistogram.Decode([]byte(base64.StdEncoding.EncodeToString(interval.data)))
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to decode %s histogram at %v: %w", interval.Method, interval.Start, err)
+		}
+		if h == nil {
+			h = decoded
+		} else {
+			h.Merge(decoded)
+		}
+		n++
+	}
+	return h, n, nil
+}
+
+// Close releases the mapped file
+func (r *Reader) Close() error {
+	if r.unmap == nil {
+		return nil
+	}
+	return r.unmap()
+}
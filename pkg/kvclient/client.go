@@ -7,21 +7,48 @@ import (
 	"time"
 
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/keepalive"
+	"google.golang.org/grpc/metadata"
 
 	pb "kvstore-benchmarker/internal/proto"
 )
 
 // Client wraps the gRPC KeyValueStore client
 type Client struct {
-	conn   *grpc.ClientConn
-	client pb.KeyValueStoreClient
-	mu     sync.RWMutex
+	conn     *grpc.ClientConn
+	client   pb.KeyValueStoreClient
+	signer   Signer         // Signs every request when set
+	tokens   *TokenProvider // Bearer token of every request when set
+	metadata *MetadataFile  // Metadata of every request when set
+	mu       sync.RWMutex
+}
+
+// Options configures the connections of clients
+type Options struct {
+	// Credentials secure the connection, which is plaintext if nil
+	Credentials credentials.TransportCredentials
+	// Keepalive pings keep idle connections open through proxies and load
+	// balancers; disabled if Time is zero
+	Keepalive keepalive.ClientParameters
 }
 
 // NewClient creates a new KeyValueStore client
-func NewClient(targetAddress string) (*Client, error) {
-	conn, err := grpc.Dial(targetAddress, grpc.WithTransportCredentials(insecure.NewCredentials()))
+func NewClient(targetAddress string, opts Options) (*Client, error) {
+	creds := opts.Credentials
+	if creds == nil {
+		creds = insecure.NewCredentials()
+	}
+	dialOptions := []grpc.DialOption{
+		grpc.WithTransportCredentials(creds),
+		grpc.WithStatsHandler(wireStatsHandler{}),
+		grpc.WithStatsHandler(timingStatsHandler{}),
+	}
+	if opts.Keepalive.Time > 0 {
+		dialOptions = append(dialOptions, grpc.WithKeepaliveParams(opts.Keepalive))
+	}
+	conn, err := grpc.Dial(targetAddress, dialOptions...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to %s: %w", targetAddress, err)
 	}
@@ -49,6 +76,10 @@ func (c *Client) Get(ctx context.Context, key []byte) (*pb.GetResponse, error) {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
 
+	ctx, err := c.authenticate(ctx, "Get", key)
+	if err != nil {
+		return nil, err
+	}
 	req := &pb.GetRequest{Key: key}
 	return c.client.Get(ctx, req)
 }
@@ -58,6 +89,10 @@ func (c *Client) Put(ctx context.Context, key, value []byte) (*pb.PutResponse, e
 	c.mu.RLock()
 	defer c.mu.RUnlock()
 
+	ctx, err := c.authenticate(ctx, "Put", key)
+	if err != nil {
+		return nil, err
+	}
 	req := &pb.PutRequest{Key: key, Value: value}
 	return c.client.Put(ctx, req)
 }
@@ -67,10 +102,56 @@ func (c *Client) Delete(ctx context.Context, key []byte) (*pb.DeleteResponse, er
 	c.mu.RLock()
 	defer c.mu.RUnlock()
 
+	ctx, err := c.authenticate(ctx, "Delete", key)
+	if err != nil {
+		return nil, err
+	}
 	req := &pb.DeleteRequest{Key: key}
 	return c.client.Delete(ctx, req)
 }
 
+// authenticate attaches the metadata file's entries, the bearer token and
+// the signature of a request to its outgoing metadata, if the client has a
+// metadata file, a token provider or a signer. A tenant identity of the
+// context adds its metadata and takes precedence; the bearer token replaces
+// an authorization entry of the file.
+func (c *Client) authenticate(ctx context.Context, method string, key []byte) (context.Context, error) {
+	signer := c.signer
+	id := identityOf(ctx)
+	if id != nil {
+		ctx = metadata.AppendToOutgoingContext(ctx, id.Metadata...)
+		if id.Signer != nil {
+			signer = id.Signer
+		}
+	}
+	if c.metadata != nil {
+		pairs := c.metadata.Metadata()
+		if id != nil || c.tokens != nil {
+			kept := make([]string, 0, len(pairs))
+			for i := 0; i+1 < len(pairs); i += 2 {
+				name := pairs[i]
+				if (id != nil && id.sets(name)) || (name == "authorization" && c.tokens != nil) {
+					continue
+				}
+				kept = append(kept, name, pairs[i+1])
+			}
+			pairs = kept
+		}
+		ctx = metadata.AppendToOutgoingContext(ctx, pairs...)
+	}
+	if c.tokens != nil && (id == nil || !id.authorizes()) {
+		ctx = metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+c.tokens.Token())
+	}
+	if signer == nil {
+		return ctx, nil
+	}
+	md, err := signer.Sign(method, key, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign %s request: %w", method, err)
+	}
+	return metadata.AppendToOutgoingContext(ctx, md...), nil
+}
+
 // ConnectionPool manages multiple gRPC connections
 type ConnectionPool struct {
 	clients []*Client
@@ -79,11 +160,11 @@ type ConnectionPool struct {
 }
 
 // NewConnectionPool creates a pool of KV store clients
-func NewConnectionPool(targetAddress string, numConnections int) (*ConnectionPool, error) {
+func NewConnectionPool(targetAddress string, numConnections int, opts Options) (*ConnectionPool, error) {
 	clients := make([]*Client, numConnections)
 
 	for i := 0; i < numConnections; i++ {
-		client, err := NewClient(targetAddress)
+		client, err := NewClient(targetAddress, opts)
 		if err != nil {
 			// Close any clients that were successfully created
 			for j := 0; j < i; j++ {
@@ -0,0 +1,46 @@
+package kvclient
+
+import "context"
+
+// Identity holds the credentials a request is sent with on behalf of one
+// tenant, so that server-side per-tenant quotas and isolation are exercised
+type Identity struct {
+	// Metadata key/value pairs sent with every request of the tenant. An
+	// "authorization" entry replaces the client's bearer token.
+	Metadata []string
+	// Signer replaces the client's signer when set
+	Signer Signer
+}
+
+// identityKey is the context key of the identity of an operation
+type identityKey struct{}
+
+// WithIdentity returns a context whose requests are sent with the tenant
+// identity id; a nil identity leaves the context unchanged
+func WithIdentity(ctx context.Context, id *Identity) context.Context {
+	if id == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, identityKey{}, id)
+}
+
+// identityOf returns the identity of the context's requests, or nil
+func identityOf(ctx context.Context) *Identity {
+	id, _ := ctx.Value(identityKey{}).(*Identity)
+	return id
+}
+
+// authorizes reports whether the identity carries its own authorization
+func (id *Identity) authorizes() bool {
+	return id.sets("authorization")
+}
+
+// sets reports whether the identity's metadata has an entry named name
+func (id *Identity) sets(name string) bool {
+	for i := 0; i+1 < len(id.Metadata); i += 2 {
+		if id.Metadata[i] == name {
+			return true
+		}
+	}
+	return false
+}
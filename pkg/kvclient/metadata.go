@@ -0,0 +1,130 @@
+package kvclient
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// MetadataFile keeps the gRPC metadata of a file, such as API keys, fresh for
+// the whole run by rereading the file every refresh interval. A failed reread
+// is logged and requests keep the previous metadata.
+type MetadataFile struct {
+	path    string
+	refresh time.Duration
+
+	mu    sync.RWMutex
+	pairs []string // Key/value pairs as passed to metadata.AppendToOutgoingContext
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewMetadataFile reads the metadata of a file, failing if it cannot, and
+// starts rereading it in the background until Stop is called. Every line
+// holds one "name: value" entry; blank lines and lines starting with # are
+// skipped.
+func NewMetadataFile(path string, refresh time.Duration) (*MetadataFile, error) {
+	f := &MetadataFile{path: path, refresh: refresh, done: make(chan struct{})}
+	if err := f.read(); err != nil {
+		return nil, err
+	}
+
+	var ctx context.Context
+	ctx, f.cancel = context.WithCancel(context.Background())
+	go f.run(ctx)
+	return f, nil
+}
+
+// read replaces the metadata with the current content of the file
+func (f *MetadataFile) read() error {
+	data, err := os.ReadFile(f.path)
+	if err != nil {
+		return fmt.Errorf("failed to read metadata file: %w", err)
+	}
+	pairs, err := parseMetadata(data)
+	if err != nil {
+		return fmt.Errorf("%s: %w", f.path, err)
+	}
+	f.mu.Lock()
+	f.pairs = pairs
+	f.mu.Unlock()
+	return nil
+}
+
+// parseMetadata parses "name: value" lines into key/value pairs with
+// lowercase names, as gRPC requires
+func parseMetadata(data []byte) ([]string, error) {
+	var pairs []string
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		name, value, ok := strings.Cut(text, ":")
+		name = strings.ToLower(strings.TrimSpace(name))
+		if !ok || name == "" || strings.ContainsAny(name, " \t") {
+			return nil, fmt.Errorf("line %d: expected \"name: value\"", line)
+		}
+		if strings.HasPrefix(name, "grpc-") {
+			return nil, fmt.Errorf("line %d: %s is reserved by gRPC", line, name)
+		}
+		pairs = append(pairs, name, strings.TrimSpace(value))
+	}
+	return pairs, scanner.Err()
+}
+
+// run rereads the file until the context is cancelled
+func (f *MetadataFile) run(ctx context.Context) {
+	defer close(f.done)
+	ticker := time.NewTicker(f.refresh)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		if err := f.read(); err != nil {
+			log.Printf("Warning: failed to refresh metadata, keeping the previous one: %v", err)
+		}
+	}
+}
+
+// Metadata returns the current key/value pairs
+func (f *MetadataFile) Metadata() []string {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	return f.pairs
+}
+
+// Stop stops rereading the file
+func (f *MetadataFile) Stop() {
+	f.cancel()
+	<-f.done
+}
+
+// SetMetadata sends the file's current metadata with every subsequent request
+// of the client, nil disables it
+func (c *Client) SetMetadata(metadata *MetadataFile) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.metadata = metadata
+}
+
+// SetMetadata sends the file's current metadata with every subsequent request
+// of the pool's clients
+func (p *ConnectionPool) SetMetadata(metadata *MetadataFile) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	for _, client := range p.clients {
+		client.SetMetadata(metadata)
+	}
+}
@@ -0,0 +1,24 @@
+package kvclient
+
+import (
+	"time"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/status"
+)
+
+// RetryAfter returns the delay a server asked clients to wait before retrying
+// a failed RPC, from the google.rpc.RetryInfo detail of its status, the gRPC
+// counterpart of an HTTP Retry-After header. It returns 0 if there is none.
+func RetryAfter(err error) time.Duration {
+	s, ok := status.FromError(err)
+	if !ok {
+		return 0
+	}
+	for _, detail := range s.Details() {
+		if info, ok := detail.(*errdetails.RetryInfo); ok && info.GetRetryDelay() != nil {
+			return max(info.GetRetryDelay().AsDuration(), 0)
+		}
+	}
+	return 0
+}
@@ -0,0 +1,151 @@
+package kvclient
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/stats"
+)
+
+// serverTimingKey is the response header or trailer in which servers may
+// report their processing time, in the W3C Server-Timing format, e.g.
+// "server-timing: total;dur=1.25" with the duration in milliseconds
+const serverTimingKey = "server-timing"
+
+// Breakdown splits the time an operation spent in its RPCs into stages. An
+// operation issuing several RPCs sums them.
+type Breakdown struct {
+	Queue time.Duration // From the RPC start until the request headers are sent: stream creation and connection waits
+	Send  time.Duration // Until the request message is sent
+	Wait  time.Duration // Until the response headers arrive: network round trip and server processing
+	Recv  time.Duration // Until the response message and trailers are received
+
+	// Processing time reported by the server, part of Wait, valid when
+	// ServerReported is set
+	Server         time.Duration
+	ServerReported bool
+}
+
+// Timing collects the latency breakdown of an operation's RPCs
+type Timing struct {
+	mu        sync.Mutex
+	breakdown Breakdown
+
+	// Stage timestamps of the RPC in flight, and whether its server timing
+	// was seen in the headers already
+	begin, headerSent, payloadSent, headerReceived time.Time
+	serverSeen                                     bool
+}
+
+// Breakdown returns the breakdown of the RPCs completed so far
+func (t *Timing) Breakdown() Breakdown {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.breakdown
+}
+
+// timingKey is the context key of the Timing of an operation
+type timingKey struct{}
+
+// WithTiming returns a context whose RPCs are broken down into the returned Timing
+func WithTiming(ctx context.Context) (context.Context, *Timing) {
+	t := &Timing{}
+	return context.WithValue(ctx, timingKey{}, t), t
+}
+
+// timingStatsHandler timestamps the stages of RPCs made with a WithTiming context
+type timingStatsHandler struct{}
+
+// TagRPC keeps the context, which already carries the Timing if any
+func (timingStatsHandler) TagRPC(ctx context.Context, _ *stats.RPCTagInfo) context.Context {
+	return ctx
+}
+
+// HandleRPC records stage timestamps and adds the stages of a finished RPC
+// to the operation's breakdown. Events without a timestamp of their own are
+// stamped on arrival.
+func (timingStatsHandler) HandleRPC(ctx context.Context, s stats.RPCStats) {
+	t, ok := ctx.Value(timingKey{}).(*Timing)
+	if !ok {
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	switch s := s.(type) {
+	case *stats.Begin:
+		t.begin, t.headerSent, t.payloadSent, t.headerReceived = s.BeginTime, time.Time{}, time.Time{}, time.Time{}
+		t.serverSeen = false
+	case *stats.OutHeader:
+		t.headerSent = time.Now()
+	case *stats.OutPayload:
+		t.payloadSent = s.SentTime
+	case *stats.InHeader:
+		t.headerReceived = time.Now()
+		t.serverTiming(s.Header)
+	case *stats.InTrailer:
+		t.serverTiming(s.Trailer)
+	case *stats.End:
+		t.finish(s.EndTime)
+	}
+}
+
+// finish adds the stages of the RPC ending at end. Stages that were not
+// reached, e.g. for RPCs failing before a response, count towards the last
+// stage that was.
+func (t *Timing) finish(end time.Time) {
+	last := t.begin
+	stage := func(reached time.Time) time.Duration {
+		if reached.IsZero() || reached.Before(last) {
+			return 0
+		}
+		d := reached.Sub(last)
+		last = reached
+		return d
+	}
+	t.breakdown.Queue += stage(t.headerSent)
+	t.breakdown.Send += stage(t.payloadSent)
+	if t.headerReceived.IsZero() {
+		// Trailers-only responses, such as most errors, have no headers
+		t.breakdown.Wait += stage(end)
+		return
+	}
+	t.breakdown.Wait += stage(t.headerReceived)
+	t.breakdown.Recv += stage(end)
+}
+
+// serverTiming adds the processing time a server reported in md, once per
+// RPC; the caller holds t.mu
+func (t *Timing) serverTiming(md metadata.MD) {
+	if t.serverSeen {
+		return
+	}
+	for _, value := range md.Get(serverTimingKey) {
+		for _, param := range strings.Split(value, ";") {
+			dur, ok := strings.CutPrefix(strings.TrimSpace(param), "dur=")
+			if !ok {
+				continue
+			}
+			ms, err := strconv.ParseFloat(strings.TrimSpace(strings.SplitN(dur, ",", 2)[0]), 64)
+			if err != nil || ms < 0 {
+				continue
+			}
+			t.breakdown.Server += time.Duration(ms * float64(time.Millisecond))
+			t.breakdown.ServerReported = true
+			t.serverSeen = true
+			return
+		}
+	}
+}
+
+// TagConn keeps the context; connections are not broken down
+func (timingStatsHandler) TagConn(ctx context.Context, _ *stats.ConnTagInfo) context.Context {
+	return ctx
+}
+
+// HandleConn ignores connection events
+func (timingStatsHandler) HandleConn(context.Context, stats.ConnStats) {}
@@ -0,0 +1,28 @@
+package kvclient
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+
+	"google.golang.org/grpc/credentials"
+)
+
+// TLSCredentials returns transport credentials verifying the server's
+// certificate against the PEM CA bundle in caFile, or the system roots if
+// empty, and against serverName, or the host of the target address if empty
+func TLSCredentials(caFile, serverName string) (credentials.TransportCredentials, error) {
+	config := &tls.Config{ServerName: serverName, MinVersion: tls.VersionTLS12}
+	if caFile != "" {
+		pem, err := os.ReadFile(caFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA bundle: %w", err)
+		}
+		config.RootCAs = x509.NewCertPool()
+		if !config.RootCAs.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no PEM certificates found in %s", caFile)
+		}
+	}
+	return credentials.NewTLS(config), nil
+}
@@ -0,0 +1,220 @@
+package kvclient
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// tokenFetchTimeout bounds a single token command or token request
+	tokenFetchTimeout = 30 * time.Second
+	// tokenRetryInterval is the wait before retrying a failed refresh; the
+	// previous token stays in use meanwhile
+	tokenRetryInterval = 10 * time.Second
+	// tokenLifetimeShare is the share of a token's lifetime after which it is
+	// refreshed, leaving a margin for slow token services
+	tokenLifetimeShare = 0.8
+)
+
+// TokenSource fetches a fresh bearer token and its lifetime, 0 when unknown
+type TokenSource func(ctx context.Context) (token string, lifetime time.Duration, err error)
+
+// CommandTokenSource runs a shell command that prints a token. The output is
+// either the bare token or an OAuth2 token response, a JSON object with
+// access_token and optionally expires_in in seconds.
+func CommandTokenSource(command string) TokenSource {
+	return func(ctx context.Context) (string, time.Duration, error) {
+		out, err := exec.CommandContext(ctx, "sh", "-c", command).Output()
+		if err != nil {
+			return "", 0, fmt.Errorf("failed to run token command: %w", err)
+		}
+		output := strings.TrimSpace(string(out))
+		if strings.HasPrefix(output, "{") {
+			return parseTokenResponse([]byte(output))
+		}
+		if output == "" {
+			return "", 0, fmt.Errorf("token command printed nothing")
+		}
+		return output, 0, nil
+	}
+}
+
+// FileTokenSource reads a token from a file that another process keeps
+// fresh, such as a Kubernetes projected service account token. The file holds
+// the bare token or an OAuth2 token response, like the output of
+// CommandTokenSource.
+func FileTokenSource(path string) TokenSource {
+	return func(ctx context.Context) (string, time.Duration, error) {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return "", 0, fmt.Errorf("failed to read token file: %w", err)
+		}
+		content := strings.TrimSpace(string(data))
+		if strings.HasPrefix(content, "{") {
+			return parseTokenResponse([]byte(content))
+		}
+		if content == "" {
+			return "", 0, fmt.Errorf("token file %s is empty", path)
+		}
+		return content, 0, nil
+	}
+}
+
+// ClientCredentialsTokenSource requests tokens from an OAuth2 token endpoint
+// with the client credentials grant, authenticating with HTTP basic auth
+func ClientCredentialsTokenSource(tokenURL, clientID, clientSecret string, scopes []string) TokenSource {
+	return func(ctx context.Context) (string, time.Duration, error) {
+		form := url.Values{"grant_type": {"client_credentials"}}
+		if len(scopes) > 0 {
+			form.Set("scope", strings.Join(scopes, " "))
+		}
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, tokenURL, strings.NewReader(form.Encode()))
+		if err != nil {
+			return "", 0, fmt.Errorf("failed to create token request: %w", err)
+		}
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		req.SetBasicAuth(url.QueryEscape(clientID), url.QueryEscape(clientSecret))
+
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			return "", 0, fmt.Errorf("failed to request token: %w", err)
+		}
+		defer resp.Body.Close()
+		var body json.RawMessage
+		if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+			return "", 0, fmt.Errorf("failed to decode token response (status %s): %w", resp.Status, err)
+		}
+		if resp.StatusCode != http.StatusOK {
+			return "", 0, fmt.Errorf("token endpoint returned %s: %s", resp.Status, body)
+		}
+		return parseTokenResponse(body)
+	}
+}
+
+// parseTokenResponse extracts the token and lifetime of an OAuth2 token response
+func parseTokenResponse(data []byte) (string, time.Duration, error) {
+	var resp struct {
+		AccessToken string  `json:"access_token"`
+		ExpiresIn   float64 `json:"expires_in"`
+	}
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return "", 0, fmt.Errorf("failed to parse token response: %w", err)
+	}
+	if resp.AccessToken == "" {
+		return "", 0, fmt.Errorf("token response has no access_token")
+	}
+	return resp.AccessToken, time.Duration(resp.ExpiresIn * float64(time.Second)), nil
+}
+
+// TokenProvider keeps a bearer token fresh for the whole run. It refreshes
+// the token every refresh interval, or earlier when the source reports that
+// it expires sooner. A failed refresh is logged and retried while requests
+// keep using the previous token.
+type TokenProvider struct {
+	source  TokenSource
+	refresh time.Duration
+
+	mu    sync.RWMutex
+	token string
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewTokenProvider fetches the first token, failing if it cannot, and starts
+// refreshing it in the background until Stop is called
+func NewTokenProvider(source TokenSource, refresh time.Duration) (*TokenProvider, error) {
+	p := &TokenProvider{source: source, refresh: refresh, done: make(chan struct{})}
+	next, err := p.fetch(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	var ctx context.Context
+	ctx, p.cancel = context.WithCancel(context.Background())
+	go p.run(ctx, next)
+	return p, nil
+}
+
+// fetch replaces the token with a fresh one and returns when to refresh it
+func (p *TokenProvider) fetch(ctx context.Context) (time.Duration, error) {
+	ctx, cancel := context.WithTimeout(ctx, tokenFetchTimeout)
+	defer cancel()
+
+	token, lifetime, err := p.source(ctx)
+	if err != nil {
+		return 0, err
+	}
+	p.mu.Lock()
+	p.token = token
+	p.mu.Unlock()
+
+	next := p.refresh
+	if lifetime > 0 {
+		next = min(next, time.Duration(float64(lifetime)*tokenLifetimeShare))
+	}
+	return next, nil
+}
+
+// run refreshes the token until the context is cancelled
+func (p *TokenProvider) run(ctx context.Context, next time.Duration) {
+	defer close(p.done)
+	timer := time.NewTimer(next)
+	defer timer.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-timer.C:
+		}
+		next, err := p.fetch(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			log.Printf("Warning: failed to refresh token, retrying in %v: %v", tokenRetryInterval, err)
+			next = tokenRetryInterval
+		}
+		timer.Reset(next)
+	}
+}
+
+// Token returns the current token
+func (p *TokenProvider) Token() string {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.token
+}
+
+// Stop stops refreshing the token
+func (p *TokenProvider) Stop() {
+	p.cancel()
+	<-p.done
+}
+
+// SetTokens sends the provider's current token as a bearer token with every
+// subsequent request of the client, nil disables it
+func (c *Client) SetTokens(tokens *TokenProvider) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.tokens = tokens
+}
+
+// SetTokens sends the provider's current token with every subsequent request
+// of the pool's clients
+func (p *ConnectionPool) SetTokens(tokens *TokenProvider) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	for _, client := range p.clients {
+		client.SetTokens(tokens)
+	}
+}
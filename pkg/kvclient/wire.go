@@ -0,0 +1,69 @@
+package kvclient
+
+import (
+	"context"
+	"sync/atomic"
+
+	"google.golang.org/grpc/stats"
+)
+
+// WireSize accumulates the bytes an operation put on and took off the wire:
+// serialized messages with their gRPC framing, plus received headers and
+// trailers. An operation issuing several RPCs sums all of them.
+type WireSize struct {
+	sent     atomic.Int64
+	received atomic.Int64
+}
+
+// Sent returns the bytes sent so far
+func (w *WireSize) Sent() int64 {
+	return w.sent.Load()
+}
+
+// Received returns the bytes received so far
+func (w *WireSize) Received() int64 {
+	return w.received.Load()
+}
+
+// wireSizeKey is the context key of the WireSize of an operation
+type wireSizeKey struct{}
+
+// WithWireSize returns a context whose RPCs are counted into the returned WireSize
+func WithWireSize(ctx context.Context) (context.Context, *WireSize) {
+	w := &WireSize{}
+	return context.WithValue(ctx, wireSizeKey{}, w), w
+}
+
+// wireStatsHandler counts the wire bytes of RPCs made with a WithWireSize context
+type wireStatsHandler struct{}
+
+// TagRPC keeps the context, which already carries the WireSize if any
+func (wireStatsHandler) TagRPC(ctx context.Context, _ *stats.RPCTagInfo) context.Context {
+	return ctx
+}
+
+// HandleRPC adds payload, header and trailer sizes to the operation's WireSize
+func (wireStatsHandler) HandleRPC(ctx context.Context, s stats.RPCStats) {
+	w, ok := ctx.Value(wireSizeKey{}).(*WireSize)
+	if !ok {
+		return
+	}
+	switch s := s.(type) {
+	case *stats.OutPayload:
+		w.sent.Add(int64(s.WireLength))
+	case *stats.InPayload:
+		w.received.Add(int64(s.WireLength))
+	case *stats.InHeader:
+		w.received.Add(int64(s.WireLength))
+	case *stats.InTrailer:
+		w.received.Add(int64(s.WireLength))
+	}
+}
+
+// TagConn keeps the context; connections are not accounted
+func (wireStatsHandler) TagConn(ctx context.Context, _ *stats.ConnTagInfo) context.Context {
+	return ctx
+}
+
+// HandleConn ignores connection events
+func (wireStatsHandler) HandleConn(context.Context, stats.ConnStats) {}
@@ -0,0 +1,79 @@
+package latency
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// Unit selects how latencies are rendered in logs and CSV output
+type Unit string
+
+const (
+	Millis Unit = "ms"
+	Micros Unit = "us"
+	Nanos  Unit = "ns"
+)
+
+// ParseUnit parses a latency unit name
+func ParseUnit(name string) (Unit, error) {
+	switch Unit(name) {
+	case Millis, Micros, Nanos:
+		return Unit(name), nil
+	case "":
+		return Millis, nil
+	default:
+		return "", fmt.Errorf("unknown latency unit %q (expected ms, us or ns)", name)
+	}
+}
+
+// FromMillis converts a latency in milliseconds to the unit
+func (u Unit) FromMillis(ms float64) float64 {
+	switch u {
+	case Micros:
+		return ms * 1e3
+	case Nanos:
+		return ms * 1e6
+	default:
+		return ms
+	}
+}
+
+// Precision returns the default number of decimal places for the unit
+func (u Unit) Precision() int {
+	switch u {
+	case Micros:
+		return 1
+	case Nanos:
+		return 0
+	default:
+		return 3
+	}
+}
+
+// Value renders a latency in milliseconds as a number in the unit, without a suffix
+func (u Unit) Value(ms float64) string {
+	return u.ValuePrecision(ms, u.Precision())
+}
+
+// ValuePrecision renders a latency like Value with the given number of decimal places
+func (u Unit) ValuePrecision(ms float64, precision int) string {
+	return strconv.FormatFloat(u.FromMillis(ms), 'f', precision, 64)
+}
+
+// Display renders a latency in milliseconds with the unit suffix, e.g. "1.250ms"
+func (u Unit) Display(ms float64) string {
+	return u.Value(ms) + u.Name()
+}
+
+// Column returns a CSV column name for a latency field, e.g. "p99_latency_us"
+func (u Unit) Column(name string) string {
+	return name + "_" + u.Name()
+}
+
+// Name returns the unit name, defaulting to milliseconds
+func (u Unit) Name() string {
+	if u == "" {
+		return string(Millis)
+	}
+	return string(u)
+}
@@ -0,0 +1,57 @@
+package manifest
+
+import (
+	"runtime/debug"
+)
+
+// Version is the release version of the benchmarker, set at build time with
+// -ldflags "-X kvstore-benchmarker/pkg/manifest.Version=v1.2.3"; without it
+// the module version recorded by the Go toolchain is used
+var Version string
+
+// Build describes the benchmarker binary that produced a run
+type Build struct {
+	Version      string `json:"version"`                 // "(devel)" for builds from a source tree
+	Revision     string `json:"revision,omitempty"`      // Git commit the binary was built from
+	RevisionTime string `json:"revision_time,omitempty"` // Commit time of the revision
+	Modified     bool   `json:"modified,omitempty"`      // Built from a tree with uncommitted changes
+}
+
+// CollectBuild describes the running binary from its version and the
+// version control information the Go toolchain stamps into it
+func CollectBuild() Build {
+	build := Build{Version: Version}
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		if build.Version == "" {
+			build.Version = "unknown"
+		}
+		return build
+	}
+	if build.Version == "" {
+		build.Version = info.Main.Version
+	}
+	for _, setting := range info.Settings {
+		switch setting.Key {
+		case "vcs.revision":
+			build.Revision = setting.Value
+		case "vcs.time":
+			build.RevisionTime = setting.Value
+		case "vcs.modified":
+			build.Modified = setting.Value == "true"
+		}
+	}
+	return build
+}
+
+// String renders the build, e.g. "(devel) 1a2b3c4d5e6f (modified)"
+func (b Build) String() string {
+	s := b.Version
+	if b.Revision != "" {
+		s += " " + b.Revision[:min(len(b.Revision), 12)]
+	}
+	if b.Modified {
+		s += " (modified)"
+	}
+	return s
+}
@@ -0,0 +1,179 @@
+// Package manifest records how and where a run was produced, so that results
+// can later be compared with an understanding of what differed between runs.
+package manifest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Manifest describes a run: its configuration, headline results and the
+// client machine that generated the load
+type Manifest struct {
+	RunID   string            `json:"run_id"`
+	Labels  map[string]string `json:"labels,omitempty"`
+	Notes   string            `json:"notes,omitempty"` // Operator's description of the run
+	Start   time.Time         `json:"start"`
+	End     time.Time         `json:"end"`
+	Config  string            `json:"config"`
+	Summary map[string]string `json:"summary,omitempty"`
+	Build   Build             `json:"build"`
+	Client  Client            `json:"client"`
+}
+
+// Client describes the benchmark client's hardware and software
+type Client struct {
+	Hostname   string `json:"hostname"`
+	OS         string `json:"os"`
+	Arch       string `json:"arch"`
+	Kernel     string `json:"kernel,omitempty"`
+	GoVersion  string `json:"go_version"`
+	CPUs       int    `json:"cpus"`
+	GOMAXPROCS int    `json:"gomaxprocs"` // CPUs the Go runtime schedules on
+	CPUModel   string `json:"cpu_model,omitempty"`
+	NICs       []NIC  `json:"nics"`
+}
+
+// NIC describes a network interface of the client
+type NIC struct {
+	Name      string `json:"name"`
+	MAC       string `json:"mac,omitempty"`
+	MTU       int    `json:"mtu"`
+	SpeedMbps int    `json:"speed_mbps,omitempty"` // 0 when unknown
+	Driver    string `json:"driver,omitempty"`
+}
+
+// CollectClient describes the machine the benchmark runs on. Details only
+// available from /proc and /sys are left empty on other platforms.
+func CollectClient() Client {
+	hostname, _ := os.Hostname()
+	client := Client{
+		Hostname:   hostname,
+		OS:         runtime.GOOS,
+		Arch:       runtime.GOARCH,
+		Kernel:     readTrimmed("/proc/sys/kernel/osrelease"),
+		GoVersion:  runtime.Version(),
+		CPUs:       runtime.NumCPU(),
+		GOMAXPROCS: runtime.GOMAXPROCS(0),
+		CPUModel:   cpuModel(),
+		NICs:       []NIC{},
+	}
+
+	interfaces, err := net.Interfaces()
+	if err != nil {
+		return client
+	}
+	for _, iface := range interfaces {
+		if iface.Flags&net.FlagLoopback != 0 || iface.Flags&net.FlagUp == 0 {
+			continue
+		}
+		nic := NIC{Name: iface.Name, MAC: iface.HardwareAddr.String(), MTU: iface.MTU}
+		sys := filepath.Join("/sys/class/net", iface.Name)
+		if speed, err := strconv.Atoi(readTrimmed(filepath.Join(sys, "speed"))); err == nil && speed > 0 {
+			nic.SpeedMbps = speed
+		}
+		if driver, err := os.Readlink(filepath.Join(sys, "device", "driver")); err == nil {
+			nic.Driver = filepath.Base(driver)
+		}
+		client.NICs = append(client.NICs, nic)
+	}
+	return client
+}
+
+// cpuModel returns the model name of the first CPU listed in /proc/cpuinfo
+func cpuModel() string {
+	data, err := os.ReadFile("/proc/cpuinfo")
+	if err != nil {
+		return ""
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		key, value, ok := strings.Cut(line, ":")
+		if ok && strings.TrimSpace(key) == "model name" {
+			return strings.TrimSpace(value)
+		}
+	}
+	return ""
+}
+
+// readTrimmed returns the contents of a small file, empty if it cannot be read
+func readTrimmed(path string) string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
+
+// Write saves a manifest as indented JSON
+func Write(path string, m *Manifest) error {
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode manifest: %w", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("failed to write manifest: %w", err)
+	}
+	return nil
+}
+
+// Load reads a manifest written by Write
+func Load(path string) (*Manifest, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read manifest: %w", err)
+	}
+	var m Manifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("failed to parse manifest %s: %w", path, err)
+	}
+	return &m, nil
+}
+
+// ClientDifferences describes the hardware differences between the clients of
+// two runs that can make their results incomparable. Hostnames, MAC addresses
+// and Go versions are not compared.
+func ClientDifferences(a, b Client) []string {
+	var diffs []string
+	differ := func(what, x, y string) {
+		if x != y {
+			diffs = append(diffs, fmt.Sprintf("%s: %s vs %s", what, orUnknown(x), orUnknown(y)))
+		}
+	}
+
+	differ("OS/architecture", a.OS+"/"+a.Arch, b.OS+"/"+b.Arch)
+	differ("CPU model", a.CPUModel, b.CPUModel)
+	differ("CPUs", strconv.Itoa(a.CPUs), strconv.Itoa(b.CPUs))
+	differ("NICs", describeNICs(a.NICs), describeNICs(b.NICs))
+	return diffs
+}
+
+// describeNICs summarizes the comparable properties of network interfaces,
+// leaving out their names, which vary between otherwise identical machines
+func describeNICs(nics []NIC) string {
+	parts := make([]string, len(nics))
+	for i, nic := range nics {
+		speed := "unknown speed"
+		if nic.SpeedMbps > 0 {
+			speed = fmt.Sprintf("%d Mb/s", nic.SpeedMbps)
+		}
+		parts[i] = fmt.Sprintf("%s %s MTU %d", orUnknown(nic.Driver), speed, nic.MTU)
+	}
+	sort.Strings(parts)
+	return "[" + strings.Join(parts, "; ") + "]"
+}
+
+// orUnknown substitutes "unknown" for an empty value
+func orUnknown(s string) string {
+	if s == "" {
+		return "unknown"
+	}
+	return s
+}
@@ -0,0 +1,66 @@
+// Package nullbackend implements a KeyValueStore server that does no work, for
+// measuring the overhead of the benchmarker's own client stack
+package nullbackend
+
+import (
+	"context"
+	"fmt"
+	"net"
+
+	"google.golang.org/grpc"
+
+	pb "kvstore-benchmarker/internal/proto"
+)
+
+// Server answers every request immediately: Puts and Deletes succeed and Gets
+// return a fixed value, so that response decoding is measured too
+type Server struct {
+	pb.UnimplementedKeyValueStoreServer
+
+	value []byte
+	grpc  *grpc.Server
+	lis   net.Listener
+}
+
+// NewServer creates a null backend whose Gets return values of the given size
+func NewServer(valueSize int) *Server {
+	s := &Server{value: make([]byte, valueSize), grpc: grpc.NewServer()}
+	pb.RegisterKeyValueStoreServer(s.grpc, s)
+	return s
+}
+
+// Start listens on a free loopback port and serves in the background
+func (s *Server) Start() error {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return fmt.Errorf("failed to listen on loopback: %w", err)
+	}
+	s.lis = lis
+	go s.grpc.Serve(lis)
+	return nil
+}
+
+// Address returns the address the server listens on
+func (s *Server) Address() string {
+	return s.lis.Addr().String()
+}
+
+// Stop stops the server
+func (s *Server) Stop() {
+	s.grpc.Stop()
+}
+
+// Put discards the value
+func (s *Server) Put(ctx context.Context, req *pb.PutRequest) (*pb.PutResponse, error) {
+	return &pb.PutResponse{Success: true}, nil
+}
+
+// Get returns the fixed value for every key
+func (s *Server) Get(ctx context.Context, req *pb.GetRequest) (*pb.GetResponse, error) {
+	return &pb.GetResponse{Value: s.value, Found: true}, nil
+}
+
+// Delete does nothing
+func (s *Server) Delete(ctx context.Context, req *pb.DeleteRequest) (*pb.DeleteResponse, error) {
+	return &pb.DeleteResponse{Success: true}, nil
+}
@@ -0,0 +1,80 @@
+// Package objstore uploads files to Amazon S3 (or S3-compatible stores) and
+// Google Cloud Storage with plain HTTP requests, taking credentials from the
+// environment like the cloud SDKs, so that results outlive ephemeral
+// benchmark machines and pods.
+package objstore
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Store is a bucket objects are written to
+type Store interface {
+	// Put writes size bytes of body as the object key
+	Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) error
+}
+
+// Location is a bucket and an optional key prefix
+type Location struct {
+	Scheme string // "s3" or "gs"
+	Bucket string
+	Prefix string // Key prefix without leading or trailing slashes
+}
+
+// Parse parses a location of the form s3://bucket/prefix or gs://bucket/prefix
+func Parse(location string) (Location, error) {
+	u, err := url.Parse(location)
+	if err != nil {
+		return Location{}, fmt.Errorf("invalid upload location %q: %w", location, err)
+	}
+	if u.Scheme != "s3" && u.Scheme != "gs" {
+		return Location{}, fmt.Errorf("upload location %q must start with s3:// or gs://", location)
+	}
+	if u.Host == "" {
+		return Location{}, fmt.Errorf("upload location %q has no bucket", location)
+	}
+	return Location{Scheme: u.Scheme, Bucket: u.Host, Prefix: strings.Trim(u.Path, "/")}, nil
+}
+
+// Key returns the key of an object named name under the prefix
+func (l Location) Key(name string) string {
+	name = strings.TrimPrefix(name, "/")
+	if l.Prefix == "" {
+		return name
+	}
+	return l.Prefix + "/" + name
+}
+
+// URL returns the location of the object key, e.g. s3://bucket/key
+func (l Location) URL(key string) string {
+	return l.Scheme + "://" + l.Bucket + "/" + key
+}
+
+// Open returns the store of the location's bucket, reading credentials from
+// the environment
+func Open(l Location) (Store, error) {
+	client := &http.Client{Timeout: 10 * time.Minute}
+	switch l.Scheme {
+	case "s3":
+		return newS3Store(l.Bucket, client)
+	case "gs":
+		return newGCSStore(l.Bucket, client), nil
+	}
+	return nil, fmt.Errorf("unknown upload scheme %q", l.Scheme)
+}
+
+// checkResponse turns an unsuccessful response into an error with the start
+// of its body, which carries the store's error message
+func checkResponse(resp *http.Response) error {
+	if resp.StatusCode/100 == 2 {
+		return nil
+	}
+	msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+	return fmt.Errorf("server returned %s: %s", resp.Status, strings.TrimSpace(string(msg)))
+}
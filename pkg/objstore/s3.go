@@ -0,0 +1,171 @@
+package objstore
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+// unsignedPayload lets S3 accept a streamed body without hashing it first;
+// the body is still protected by TLS
+const unsignedPayload = "UNSIGNED-PAYLOAD"
+
+// s3Store writes objects with PUT requests signed with AWS Signature Version 4
+type s3Store struct {
+	bucket       string
+	region       string
+	endpoint     *url.URL // Custom endpoint of an S3-compatible store, nil for AWS
+	accessKey    string
+	secretKey    string
+	sessionToken string
+	client       *http.Client
+}
+
+// newS3Store creates a store for an S3 bucket. Credentials come from
+// AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY and optionally AWS_SESSION_TOKEN,
+// the region from AWS_REGION or AWS_DEFAULT_REGION (us-east-1 by default).
+// AWS_ENDPOINT_URL_S3 or AWS_ENDPOINT_URL select an S3-compatible store such
+// as MinIO, addressed with path-style URLs.
+func newS3Store(bucket string, client *http.Client) (*s3Store, error) {
+	s := &s3Store{
+		bucket:       bucket,
+		region:       firstEnv("AWS_REGION", "AWS_DEFAULT_REGION"),
+		accessKey:    os.Getenv("AWS_ACCESS_KEY_ID"),
+		secretKey:    os.Getenv("AWS_SECRET_ACCESS_KEY"),
+		sessionToken: os.Getenv("AWS_SESSION_TOKEN"),
+		client:       client,
+	}
+	if s.accessKey == "" || s.secretKey == "" {
+		return nil, fmt.Errorf("S3 uploads require AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY")
+	}
+	if s.region == "" {
+		s.region = "us-east-1"
+	}
+	if endpoint := firstEnv("AWS_ENDPOINT_URL_S3", "AWS_ENDPOINT_URL"); endpoint != "" {
+		u, err := url.Parse(endpoint)
+		if err != nil || u.Host == "" {
+			return nil, fmt.Errorf("invalid S3 endpoint %q", endpoint)
+		}
+		s.endpoint = u
+	}
+	return s, nil
+}
+
+// Put uploads an object in a single request, which S3 accepts up to 5 GB
+func (s *s3Store) Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) error {
+	u := s.objectURL(key)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, u.String(), body)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.ContentLength = size
+	req.Header.Set("Content-Type", contentType)
+	s.sign(req, u, time.Now())
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	return checkResponse(resp)
+}
+
+// objectURL returns the URL of an object: virtual-hosted style on AWS, unless
+// the bucket name has dots the TLS certificate does not cover, and path style
+// on custom endpoints. RawPath holds the key encoded as it is signed, so that
+// it is sent as is rather than escaped again.
+func (s *s3Store) objectURL(key string) *url.URL {
+	switch {
+	case s.endpoint != nil:
+		u := *s.endpoint
+		u.RawPath = strings.TrimSuffix(u.EscapedPath(), "/") + "/" + escapePath(s.bucket) + "/" + escapePath(key)
+		u.Path = strings.TrimSuffix(u.Path, "/") + "/" + s.bucket + "/" + key
+		return &u
+	case strings.Contains(s.bucket, "."):
+		return &url.URL{Scheme: "https", Host: "s3." + s.region + ".amazonaws.com", Path: "/" + s.bucket + "/" + key, RawPath: "/" + s.bucket + "/" + escapePath(key)}
+	default:
+		return &url.URL{Scheme: "https", Host: s.bucket + ".s3." + s.region + ".amazonaws.com", Path: "/" + key, RawPath: "/" + escapePath(key)}
+	}
+}
+
+// sign adds the AWS Signature Version 4 headers to a request
+func (s *s3Store) sign(req *http.Request, u *url.URL, now time.Time) {
+	amzDate := now.UTC().Format("20060102T150405Z")
+	date := amzDate[:8]
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", unsignedPayload)
+	if s.sessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", s.sessionToken)
+	}
+
+	// Headers are signed in sorted order by lowercase name
+	headers := []string{"host:" + u.Host, "x-amz-content-sha256:" + unsignedPayload, "x-amz-date:" + amzDate}
+	signed := "host;x-amz-content-sha256;x-amz-date"
+	if s.sessionToken != "" {
+		headers = append(headers, "x-amz-security-token:"+s.sessionToken)
+		signed += ";x-amz-security-token"
+	}
+	canonical := strings.Join([]string{
+		req.Method,
+		u.EscapedPath(),
+		"", // No query string
+		strings.Join(headers, "\n") + "\n",
+		signed,
+		unsignedPayload,
+	}, "\n")
+
+	scope := date + "/" + s.region + "/s3/aws4_request"
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hexSHA256(canonical)
+	key := hmacSHA256([]byte("AWS4"+s.secretKey), date)
+	for _, part := range []string{s.region, "s3", "aws4_request"} {
+		key = hmacSHA256(key, part)
+	}
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s.accessKey, scope, signed, signature))
+}
+
+// escapePath percent-encodes an object key as Signature Version 4 expects:
+// everything but unreserved characters and the slashes between segments
+func escapePath(key string) string {
+	var b strings.Builder
+	for i := 0; i < len(key); i++ {
+		c := key[i]
+		if c == '/' || c == '-' || c == '_' || c == '.' || c == '~' ||
+			'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9' {
+			b.WriteByte(c)
+		} else {
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+func hexSHA256(data string) string {
+	sum := sha256.Sum256([]byte(data))
+	return hex.EncodeToString(sum[:])
+}
+
+// firstEnv returns the first of the environment variables that is set
+func firstEnv(names ...string) string {
+	for _, name := range names {
+		if value := os.Getenv(name); value != "" {
+			return value
+		}
+	}
+	return ""
+}
@@ -0,0 +1,130 @@
+package parquet
+
+import (
+	"encoding/binary"
+)
+
+// Thrift compact protocol type codes of struct fields and list elements
+const (
+	thriftTrue   = 1
+	thriftFalse  = 2
+	thriftI32    = 5
+	thriftI64    = 6
+	thriftBinary = 8
+	thriftList   = 9
+	thriftStruct = 12
+)
+
+// thriftWriter encodes Thrift structs in the compact protocol, in which
+// Parquet stores page headers and the file footer. Only the types Parquet
+// metadata needs are supported. Fields must be written in ascending order.
+type thriftWriter struct {
+	buf  []byte
+	last []int16 // Last field ID of every open struct, innermost last
+}
+
+// newThriftWriter creates a writer positioned in a top-level struct
+func newThriftWriter() *thriftWriter {
+	return &thriftWriter{last: []int16{0}}
+}
+
+// bytes returns the encoding written so far
+func (t *thriftWriter) bytes() []byte {
+	return t.buf
+}
+
+// fieldHeader writes the header of field id of the given type, with the
+// field ID as delta of the previous one when it fits in four bits
+func (t *thriftWriter) fieldHeader(id int16, typ byte) {
+	last := &t.last[len(t.last)-1]
+	if delta := id - *last; delta > 0 && delta <= 15 {
+		t.buf = append(t.buf, byte(delta)<<4|typ)
+	} else {
+		t.buf = append(t.buf, typ)
+		t.varint(int64(id))
+	}
+	*last = id
+}
+
+// varint writes a zigzag-encoded variable-length integer
+func (t *thriftWriter) varint(v int64) {
+	t.buf = binary.AppendUvarint(t.buf, uint64(v<<1)^uint64(v>>63))
+}
+
+// uvarint writes an unsigned variable-length integer
+func (t *thriftWriter) uvarint(v uint64) {
+	t.buf = binary.AppendUvarint(t.buf, v)
+}
+
+// i32 writes a 32-bit integer field
+func (t *thriftWriter) i32(id int16, v int32) {
+	t.fieldHeader(id, thriftI32)
+	t.varint(int64(v))
+}
+
+// i64 writes a 64-bit integer field
+func (t *thriftWriter) i64(id int16, v int64) {
+	t.fieldHeader(id, thriftI64)
+	t.varint(v)
+}
+
+// boolean writes a boolean field, whose value is part of the field header
+func (t *thriftWriter) boolean(id int16, v bool) {
+	if v {
+		t.fieldHeader(id, thriftTrue)
+	} else {
+		t.fieldHeader(id, thriftFalse)
+	}
+}
+
+// binary writes a string or byte field
+func (t *thriftWriter) binary(id int16, v string) {
+	t.fieldHeader(id, thriftBinary)
+	t.uvarint(uint64(len(v)))
+	t.buf = append(t.buf, v...)
+}
+
+// listHeader writes the header of a list field of n elements of type typ
+func (t *thriftWriter) listHeader(id int16, typ byte, n int) {
+	t.fieldHeader(id, thriftList)
+	if n < 15 {
+		t.buf = append(t.buf, byte(n)<<4|typ)
+	} else {
+		t.buf = append(t.buf, 0xf0|typ)
+		t.uvarint(uint64(n))
+	}
+}
+
+// i32List writes a list field of 32-bit integers
+func (t *thriftWriter) i32List(id int16, values []int32) {
+	t.listHeader(id, thriftI32, len(values))
+	for _, v := range values {
+		t.varint(int64(v))
+	}
+}
+
+// binaryList writes a list field of strings
+func (t *thriftWriter) binaryList(id int16, values []string) {
+	t.listHeader(id, thriftBinary, len(values))
+	for _, v := range values {
+		t.uvarint(uint64(len(v)))
+		t.buf = append(t.buf, v...)
+	}
+}
+
+// structField opens a struct field; its fields follow until endStruct
+func (t *thriftWriter) structField(id int16) {
+	t.fieldHeader(id, thriftStruct)
+	t.beginStruct()
+}
+
+// beginStruct opens a struct element of a list
+func (t *thriftWriter) beginStruct() {
+	t.last = append(t.last, 0)
+}
+
+// endStruct closes the innermost open struct
+func (t *thriftWriter) endStruct() {
+	t.buf = append(t.buf, 0)
+	t.last = t.last[:len(t.last)-1]
+}
@@ -0,0 +1,327 @@
+// Package parquet writes flat tables as Apache Parquet files, the columnar
+// format Spark, DuckDB and pandas load efficiently. Every column is required
+// and PLAIN-encoded in a single Snappy-compressed data page per row group.
+package parquet
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"os"
+	"time"
+
+	"github.com/golang/snappy"
+)
+
+// magic starts and ends every Parquet file
+const magic = "PAR1"
+
+// rowGroupRows is the number of rows buffered before they are written as a
+// row group, bounding memory use for long streams of rows
+const rowGroupRows = 1 << 16
+
+// Physical types, encodings and other enumerations of the Parquet format
+const (
+	typeInt64     = 2
+	typeDouble    = 5
+	typeByteArray = 6
+
+	repetitionRequired = 0
+
+	convertedUTF8            = 0
+	convertedTimestampMicros = 10
+
+	encodingPlain = 0
+	encodingRLE   = 3
+
+	codecSnappy = 1
+
+	pageData = 0
+)
+
+// Type is the type of a column
+type Type int
+
+const (
+	Int64     Type = iota // 64-bit signed integers (int or int64 values)
+	Double                // 64-bit floats (float64 values)
+	String                // UTF-8 strings (string values)
+	Timestamp             // UTC instants with microsecond precision (time.Time values)
+)
+
+// Column is a column of a Parquet file
+type Column struct {
+	Name string
+	Type Type
+}
+
+// physical returns the physical Parquet type of the column
+func (c Column) physical() int32 {
+	switch c.Type {
+	case Double:
+		return typeDouble
+	case String:
+		return typeByteArray
+	default:
+		return typeInt64
+	}
+}
+
+// chunk locates the column chunk of a row group in the file
+type chunk struct {
+	offset       int64
+	uncompressed int64 // Size of the page header and the uncompressed page
+	compressed   int64 // Size of the page header and the compressed page
+}
+
+// rowGroup records a written row group for the footer
+type rowGroup struct {
+	chunks []chunk
+	rows   int64
+}
+
+// Writer writes rows to a Parquet file. Rows are buffered column by column
+// and written as a row group every rowGroupRows rows; the footer describing
+// them is written by Close.
+type Writer struct {
+	file    *os.File
+	buf     *bufio.Writer
+	offset  int64
+	columns []Column
+	values  [][]byte // PLAIN-encoded values of the buffered rows, by column
+	rows    int64    // Buffered rows
+	groups  []rowGroup
+}
+
+// Create creates a Parquet file with the given columns
+func Create(path string, columns []Column) (*Writer, error) {
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Parquet file: %w", err)
+	}
+	w := &Writer{
+		file:    file,
+		buf:     bufio.NewWriterSize(file, 64*1024),
+		columns: columns,
+		values:  make([][]byte, len(columns)),
+	}
+	if err := w.write([]byte(magic)); err != nil {
+		file.Close()
+		return nil, err
+	}
+	return w, nil
+}
+
+// Write appends a row with a value of the column's type for every column
+func (w *Writer) Write(row ...any) error {
+	if len(row) != len(w.columns) {
+		return fmt.Errorf("parquet row has %d values for %d columns", len(row), len(w.columns))
+	}
+	for i, column := range w.columns {
+		if err := w.appendValue(i, row[i]); err != nil {
+			return fmt.Errorf("parquet column %s: %w", column.Name, err)
+		}
+	}
+	w.rows++
+	if w.rows >= rowGroupRows {
+		return w.flush()
+	}
+	return nil
+}
+
+// appendValue PLAIN-encodes the value of column i
+func (w *Writer) appendValue(i int, value any) error {
+	buf := w.values[i]
+	switch w.columns[i].Type {
+	case Int64:
+		switch v := value.(type) {
+		case int64:
+			buf = binary.LittleEndian.AppendUint64(buf, uint64(v))
+		case int:
+			buf = binary.LittleEndian.AppendUint64(buf, uint64(int64(v)))
+		default:
+			return fmt.Errorf("expected an integer, got %T", value)
+		}
+	case Double:
+		v, ok := value.(float64)
+		if !ok {
+			return fmt.Errorf("expected a float64, got %T", value)
+		}
+		buf = binary.LittleEndian.AppendUint64(buf, math.Float64bits(v))
+	case String:
+		v, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("expected a string, got %T", value)
+		}
+		buf = binary.LittleEndian.AppendUint32(buf, uint32(len(v)))
+		buf = append(buf, v...)
+	case Timestamp:
+		v, ok := value.(time.Time)
+		if !ok {
+			return fmt.Errorf("expected a time.Time, got %T", value)
+		}
+		buf = binary.LittleEndian.AppendUint64(buf, uint64(v.UnixMicro()))
+	}
+	w.values[i] = buf
+	return nil
+}
+
+// flush writes the buffered rows as a row group of one data page per column
+func (w *Writer) flush() error {
+	group := rowGroup{rows: w.rows}
+	for i, values := range w.values {
+		page := snappy.Encode(nil, values)
+		header := pageHeader(len(values), len(page), w.rows)
+
+		c := chunk{
+			offset:       w.offset,
+			uncompressed: int64(len(header) + len(values)),
+			compressed:   int64(len(header) + len(page)),
+		}
+		if err := w.write(header); err != nil {
+			return err
+		}
+		if err := w.write(page); err != nil {
+			return err
+		}
+		group.chunks = append(group.chunks, c)
+		w.values[i] = values[:0]
+	}
+	w.groups = append(w.groups, group)
+	w.rows = 0
+	return nil
+}
+
+// write appends data to the file
+func (w *Writer) write(data []byte) error {
+	n, err := w.buf.Write(data)
+	w.offset += int64(n)
+	if err != nil {
+		return fmt.Errorf("failed to write Parquet file: %w", err)
+	}
+	return nil
+}
+
+// Close writes the buffered rows and the footer and closes the file
+func (w *Writer) Close() error {
+	if err := w.finish(); err != nil {
+		w.file.Close()
+		return err
+	}
+	return w.file.Close()
+}
+
+// finish writes the buffered rows and the footer
+func (w *Writer) finish() error {
+	if w.rows > 0 {
+		if err := w.flush(); err != nil {
+			return err
+		}
+	}
+	footer := w.footer()
+	if err := w.write(footer); err != nil {
+		return err
+	}
+	if err := w.write(binary.LittleEndian.AppendUint32(nil, uint32(len(footer)))); err != nil {
+		return err
+	}
+	if err := w.write([]byte(magic)); err != nil {
+		return err
+	}
+	if err := w.buf.Flush(); err != nil {
+		return fmt.Errorf("failed to flush Parquet file: %w", err)
+	}
+	return nil
+}
+
+// pageHeader encodes the header of a PLAIN-encoded data page of n values
+func pageHeader(uncompressed, compressed int, n int64) []byte {
+	t := newThriftWriter()
+	t.i32(1, pageData)
+	t.i32(2, int32(uncompressed))
+	t.i32(3, int32(compressed))
+	t.structField(5)
+	t.i32(1, int32(n))
+	t.i32(2, encodingPlain)
+	t.i32(3, encodingRLE)
+	t.i32(4, encodingRLE)
+	t.endStruct()
+	t.endStruct()
+	return t.bytes()
+}
+
+// footer encodes the file metadata: the schema and the location of every
+// column chunk
+func (w *Writer) footer() []byte {
+	var rows int64
+	for _, group := range w.groups {
+		rows += group.rows
+	}
+
+	t := newThriftWriter()
+	t.i32(1, 1)
+
+	// The schema is a root element followed by the columns
+	t.listHeader(2, thriftStruct, len(w.columns)+1)
+	t.beginStruct()
+	t.binary(4, "schema")
+	t.i32(5, int32(len(w.columns)))
+	t.endStruct()
+	for _, column := range w.columns {
+		t.beginStruct()
+		t.i32(1, column.physical())
+		t.i32(3, repetitionRequired)
+		t.binary(4, column.Name)
+		switch column.Type {
+		case String:
+			t.i32(6, convertedUTF8)
+			t.structField(10)
+			t.structField(1) // STRING
+			t.endStruct()
+			t.endStruct()
+		case Timestamp:
+			t.i32(6, convertedTimestampMicros)
+			t.structField(10)
+			t.structField(8) // TIMESTAMP
+			t.boolean(1, true)
+			t.structField(2)
+			t.structField(2) // MICROS
+			t.endStruct()
+			t.endStruct()
+			t.endStruct()
+			t.endStruct()
+		}
+		t.endStruct()
+	}
+
+	t.i64(3, rows)
+	t.listHeader(4, thriftStruct, len(w.groups))
+	for _, group := range w.groups {
+		t.beginStruct()
+		t.listHeader(1, thriftStruct, len(group.chunks))
+		var size int64
+		for i, c := range group.chunks {
+			size += c.uncompressed
+			t.beginStruct()
+			t.i64(2, c.offset)
+			t.structField(3)
+			t.i32(1, w.columns[i].physical())
+			t.i32List(2, []int32{encodingPlain})
+			t.binaryList(3, []string{w.columns[i].Name})
+			t.i32(4, codecSnappy)
+			t.i64(5, group.rows)
+			t.i64(6, c.uncompressed)
+			t.i64(7, c.compressed)
+			t.i64(9, c.offset)
+			t.endStruct()
+			t.endStruct()
+		}
+		t.i64(2, size)
+		t.i64(3, group.rows)
+		t.endStruct()
+	}
+	t.binary(6, "kvstore-benchmarker")
+	t.endStruct()
+	return t.bytes()
+}
@@ -0,0 +1,142 @@
+// Package results defines the versioned schema of the run results written as
+// JSON or YAML: the --json and --yaml summaries, the web dashboard's event
+// stream, JSON progress lines and webhook notifications, so that tooling
+// reading them can rely on what a field means. Within a schema version fields are only ever added; removing,
+// renaming or changing the meaning of a field increments SchemaVersion.
+// Readers should therefore ignore unknown fields and check the version.
+package results
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"kvstore-benchmarker/pkg/manifest"
+)
+
+// SchemaVersion is the version of the results schema this build writes.
+// Summaries written before the schema was versioned decode as version 0 and
+// have the same fields as version 1.
+const SchemaVersion = 1
+
+// Summary is the machine-readable summary of a run. Latencies are always in
+// milliseconds, whatever the --latency-unit.
+type Summary struct {
+	SchemaVersion int               `json:"schema_version"`
+	RunID         string            `json:"run_id"`
+	Labels        map[string]string `json:"labels,omitempty"`
+	Notes         string            `json:"notes,omitempty"`
+	Start         time.Time         `json:"start"`
+	End           time.Time         `json:"end"`
+	DurationS     float64           `json:"duration_s"`
+	ThroughputOps float64           `json:"throughput_ops"`
+	SLOFailed     int               `json:"slo_failed"`
+	// Share of throttles with a suggested retry delay whose worker waited it
+	// out, absent without such throttles
+	RetryAfterAdherence *float64 `json:"retry_after_adherence_pct,omitempty"`
+	Methods             []Stats  `json:"methods"`
+	Aggregated          Stats    `json:"aggregated"`
+	// Configuration in the JSON form config.LoadFromFile reads
+	Config json.RawMessage `json:"config"`
+	Build  manifest.Build  `json:"build"`
+	Client manifest.Client `json:"client"`
+}
+
+// Interval is the statistics of one report interval, as streamed to clients
+// of the web dashboard's event stream, or of the whole run for its final event
+type Interval struct {
+	SchemaVersion int       `json:"schema_version"`
+	RunID         string    `json:"run_id"`
+	Start         time.Time `json:"start"`
+	End           time.Time `json:"end"`
+	OffsetS       float64   `json:"offset_s"`       // End of the interval in seconds since the start of the run
+	DurationS     float64   `json:"duration_s"`     // Measured time of the interval
+	ThroughputOps float64   `json:"throughput_ops"` // Successful ops/sec of all methods
+	Methods       []Stats   `json:"methods"`
+	Aggregated    Stats     `json:"aggregated"`
+}
+
+// Progress is a progress tick written to stdout by --progress-format=json,
+// one object per line. Like progress log lines, TotalOps counts the whole
+// measured phase so far while the rest covers the last report interval.
+type Progress struct {
+	SchemaVersion int       `json:"schema_version"`
+	RunID         string    `json:"run_id"`
+	Time          time.Time `json:"time"`
+	IntervalS     float64   `json:"interval_s"`            // Time covered by the tick
+	TotalOps      int64     `json:"total_ops"`             // Operations since the start of the measured phase
+	OpsPerSec     float64   `json:"ops_per_sec"`           // Operations per second, failed or not
+	AdmittedOps   float64   `json:"admitted_ops_per_sec"`  // Operations per second not throttled by the server
+	ReadMBps      float64   `json:"read_mb_per_sec"`       // Values returned by Gets
+	WriteMBps     float64   `json:"write_mb_per_sec"`      // Keys and values sent
+	SLOMetPct     *float64  `json:"slo_met_pct,omitempty"` // Share of operations meeting their SLO, absent without one
+	Aggregated    Stats     `json:"aggregated"`            // Statistics of all methods in the interval
+}
+
+// Notification is the body posted to the --notify-url-file webhook with
+// --notify-format=json when a run ends. Statistics cover the measured phase
+// so far; a run that aborted before it has none.
+type Notification struct {
+	SchemaVersion int               `json:"schema_version"`
+	RunID         string            `json:"run_id"`
+	Status        string            `json:"status"`          // "passed", "failed" (SLO assertions, regression thresholds or search objective) or "aborted"
+	Error         string            `json:"error,omitempty"` // Why the run failed or aborted
+	Target        string            `json:"target"`
+	Labels        map[string]string `json:"labels,omitempty"`
+	Notes         string            `json:"notes,omitempty"`
+	Start         time.Time         `json:"start"`
+	End           time.Time         `json:"end"`
+	DurationS     float64           `json:"duration_s"`
+	ThroughputOps float64           `json:"throughput_ops"`
+	SLOTotal      int               `json:"slo_total"`
+	SLOFailed     int               `json:"slo_failed"`
+	Regressions   int               `json:"regressions"`
+	Aggregated    Stats             `json:"aggregated"`
+}
+
+// Stats are the statistics of a method or of all methods together
+type Stats struct {
+	Method         string             `json:"method,omitempty"`
+	Ops            int64              `json:"ops"`
+	Errors         int64              `json:"errors"`
+	Dropped        int64              `json:"dropped"`
+	ErrorRatePct   float64            `json:"error_rate_pct"`
+	ErrorCodes     map[string]int64   `json:"error_codes,omitempty"`
+	Throttled      int64              `json:"throttled"`
+	ThrottleRate   float64            `json:"throttle_rate_pct"`
+	RetryAfterAvg  float64            `json:"retry_after_avg_ms,omitempty"`
+	RetryAfterMax  float64            `json:"retry_after_max_ms,omitempty"`
+	AvgMs          float64            `json:"avg_ms"`
+	MinMs          float64            `json:"min_ms"`
+	MaxMs          float64            `json:"max_ms"`
+	PercentilesMs  map[string]float64 `json:"percentiles_ms"` // By percentile key, e.g. "p99_9"
+	TrimmedMeanMs  float64            `json:"trimmed_mean_ms"`
+	MADMs          float64            `json:"mad_ms"`
+	ErrorAvgMs     float64            `json:"error_avg_ms,omitempty"`
+	ErrorP99Ms     float64            `json:"error_p99_ms,omitempty"`
+	ErrorMaxMs     float64            `json:"error_max_ms,omitempty"`
+	BytesSent      int64              `json:"bytes_sent"`
+	BytesRecv      int64              `json:"bytes_recv"`
+	Overflow       int64              `json:"overflow_ops"`
+	SLOThresholdMs float64            `json:"slo_threshold_ms,omitempty"`
+	SLOViolations  int64              `json:"slo_violations,omitempty"`
+}
+
+// Successes returns the operations that neither failed nor were throttled
+func (s Stats) Successes() int64 {
+	return s.Ops - s.Errors - s.Throttled
+}
+
+// Read decodes a JSON summary, rejecting those of a newer schema version
+// than this build understands
+func Read(r io.Reader) (*Summary, error) {
+	var summary Summary
+	if err := json.NewDecoder(r).Decode(&summary); err != nil {
+		return nil, fmt.Errorf("failed to parse JSON summary: %w", err)
+	}
+	if summary.SchemaVersion > SchemaVersion {
+		return nil, fmt.Errorf("JSON summary has schema version %d, this build reads up to %d", summary.SchemaVersion, SchemaVersion)
+	}
+	return &summary, nil
+}
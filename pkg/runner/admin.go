@@ -0,0 +1,51 @@
+package runner
+
+import (
+	"log"
+	"net/http"
+
+	"kvstore-benchmarker/pkg/admin"
+)
+
+// pauseStatus is the admin endpoint response describing the pause state
+type pauseStatus struct {
+	Paused       bool    `json:"paused"`
+	PausedTotalS float64 `json:"paused_total_seconds"`
+}
+
+// registerAdminRoutes exposes benchmark control endpoints on the admin server
+func (r *BenchmarkRunner) registerAdminRoutes(s *admin.Server) {
+	s.Handle("/pause", func(w http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodPost {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		if r.gate.Pause() {
+			log.Printf("Benchmark paused (admin endpoint)")
+		}
+		admin.WriteJSON(w, r.pauseStatus())
+	})
+
+	s.Handle("/resume", func(w http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodPost {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		if r.gate.Resume() {
+			log.Printf("Benchmark resumed (admin endpoint)")
+		}
+		admin.WriteJSON(w, r.pauseStatus())
+	})
+
+	s.Handle("/status", func(w http.ResponseWriter, req *http.Request) {
+		admin.WriteJSON(w, r.pauseStatus())
+	})
+}
+
+// pauseStatus returns the current pause state
+func (r *BenchmarkRunner) pauseStatus() pauseStatus {
+	return pauseStatus{
+		Paused:       r.gate.IsPaused(),
+		PausedTotalS: r.gate.PausedTotal().Seconds(),
+	}
+}
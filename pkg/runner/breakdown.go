@@ -0,0 +1,50 @@
+package runner
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"kvstore-benchmarker/pkg/collector"
+	"kvstore-benchmarker/pkg/kvclient"
+)
+
+// breakdownOf converts the RPC stage timings of an operation into its
+// latency breakdown
+func breakdownOf(b kvclient.Breakdown) *collector.Breakdown {
+	ms := func(d time.Duration) float64 {
+		return float64(d) / float64(time.Millisecond)
+	}
+	return &collector.Breakdown{
+		Queue:          ms(b.Queue),
+		Send:           ms(b.Send),
+		Wait:           ms(b.Wait),
+		Recv:           ms(b.Recv),
+		Server:         ms(b.Server),
+		ServerReported: b.ServerReported,
+	}
+}
+
+// printBreakdown prints the statistics of every latency component of the run
+func (r *BenchmarkRunner) printBreakdown(out io.Writer, color bool) {
+	components := r.collector.GetBreakdownStats()
+	if len(components) == 0 {
+		return
+	}
+
+	header := []string{"Component", "Method", "Count", "Errors", "Error%", "Avg"}
+	for _, p := range r.collector.Percentiles() {
+		header = append(header, collector.PercentileLabel(p))
+	}
+	table := newTextTable(append(header, "Min", "Max")...)
+	for _, component := range components {
+		for _, stat := range component.Methods {
+			table.addRow(append([]tableCell{{text: component.Component}}, r.statsRow(stat, "")...)...)
+		}
+		table.addRow(append([]tableCell{{text: component.Component, style: ansiBold}}, r.statsRow(component.Aggregated, ansiBold)...)...)
+	}
+
+	fmt.Fprintf(out, "=== LATENCY BREAKDOWN (latencies in %s) ===\n\n", r.unit().Name())
+	table.render(out, color)
+	fmt.Fprintln(out)
+}
@@ -0,0 +1,144 @@
+package runner
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"math"
+	"time"
+
+	"kvstore-benchmarker/pkg/collector"
+	"kvstore-benchmarker/pkg/kvclient"
+	"kvstore-benchmarker/pkg/latency"
+	"kvstore-benchmarker/pkg/nullbackend"
+)
+
+// calibrationMethods are the operations timed against the null backend
+var calibrationMethods = []string{"Get", "Put", "Delete"}
+
+// calibrate measures the latency the benchmarker itself adds to every
+// operation: one worker issues each operation in turn, for an equal share of
+// the calibration time, against an in-process null backend on loopback
+func (r *BenchmarkRunner) calibrate(ctx context.Context) (map[string]collector.Stats, error) {
+	server := nullbackend.NewServer(r.config.ValueSize)
+	if err := server.Start(); err != nil {
+		return nil, fmt.Errorf("failed to start null backend: %w", err)
+	}
+	defer server.Stop()
+
+	client, err := kvclient.NewClient(server.Address(), kvclient.Options{})
+	if err != nil {
+		return nil, err
+	}
+	defer client.Close()
+
+	c, err := collector.NewCollector(collector.Options{
+		BufferSize:    r.config.ResultsBufferSize,
+		BlockWhenFull: true,
+		LatencyUnit:   latency.Unit(r.config.LatencyUnit),
+		Engine:        r.config.PercentileEngine,
+		Percentiles:   r.collector.Percentiles(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	c.Start(ctx)
+	defer c.Stop()
+
+	value, err := GenerateValue(r.config.ValueSize)
+	if err != nil {
+		return nil, err
+	}
+	share := r.config.CalibrateDuration / time.Duration(len(calibrationMethods))
+	n := 0
+	for _, method := range calibrationMethods {
+		deadline := time.Now().Add(share)
+		for ; time.Now().Before(deadline) && ctx.Err() == nil; n++ {
+			key := r.keyGen.Key(n % r.config.KeySpace)
+			start := time.Now()
+			switch method {
+			case "Get":
+				_, err = client.Get(ctx, key)
+			case "Put":
+				_, err = client.Put(ctx, key, value)
+			case "Delete":
+				_, err = client.Delete(ctx, key)
+			}
+			c.AddResult(&collector.BenchmarkResult{
+				Method:    method,
+				LatencyMs: float64(time.Since(start)) / float64(time.Millisecond),
+				Error:     err,
+				Timestamp: time.Now(),
+			})
+		}
+	}
+	c.Flush()
+	return c.GetStats(), nil
+}
+
+// runCalibration runs the calibration step and logs the measured overhead
+func (r *BenchmarkRunner) runCalibration(ctx context.Context) error {
+	log.Printf("Calibrating client overhead against a null backend for %v", r.config.CalibrateDuration)
+	overhead, err := r.calibrate(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to calibrate: %w", err)
+	}
+	r.overhead = overhead
+
+	unit := r.unit()
+	for _, method := range calibrationMethods {
+		stat := overhead[method]
+		log.Printf("Client overhead: %s %d ops | Avg: %s | P50: %s | Max: %s",
+			method, stat.Count, unit.Display(stat.AvgLatency), unit.Display(stat.P50Latency), unit.Display(stat.MaxLatency))
+	}
+	return nil
+}
+
+// printOverhead prints the calibrated client overhead next to the final
+// results and, if requested, the results with the overhead subtracted
+func (r *BenchmarkRunner) printOverhead(out io.Writer, color bool, stats map[string]collector.Stats) {
+	if r.overhead == nil {
+		return
+	}
+	unit := r.unit()
+
+	table, _ := r.statsTable(r.overhead, collector.Stats{})
+	fmt.Fprintf(out, "=== CLIENT OVERHEAD (null backend, latencies in %s) ===\n\n", unit.Name())
+	table.render(out, color)
+	fmt.Fprintln(out)
+
+	if !r.config.CalibrateSubtract {
+		return
+	}
+	adjusted := make(map[string]collector.Stats, len(stats))
+	for method, stat := range stats {
+		if overhead, ok := r.overhead[method]; ok {
+			adjusted[method] = subtractOverhead(stat, overhead)
+		}
+	}
+	table, _ = r.statsTable(adjusted, collector.Stats{})
+	fmt.Fprintf(out, "=== FINAL RESULTS MINUS CLIENT OVERHEAD (latencies in %s) ===\n\n", unit.Name())
+	table.render(out, color)
+	fmt.Fprintln(out)
+}
+
+// subtractOverhead subtracts the overhead latency from each latency statistic
+// of the same kind, never going below zero. Subtracting percentiles is an
+// approximation: it assumes the overhead is roughly constant per operation.
+func subtractOverhead(stat, overhead collector.Stats) collector.Stats {
+	sub := func(v, o float64) float64 { return math.Max(0, v-o) }
+	stat.AvgLatency = sub(stat.AvgLatency, overhead.AvgLatency)
+	stat.MinLatency = sub(stat.MinLatency, overhead.MinLatency)
+	stat.MaxLatency = sub(stat.MaxLatency, overhead.MaxLatency)
+	stat.P50Latency = sub(stat.P50Latency, overhead.P50Latency)
+	stat.P95Latency = sub(stat.P95Latency, overhead.P95Latency)
+	stat.P99Latency = sub(stat.P99Latency, overhead.P99Latency)
+	stat.P999Latency = sub(stat.P999Latency, overhead.P999Latency)
+	percentiles := make([]float64, len(stat.Percentiles))
+	for i, v := range stat.Percentiles {
+		percentiles[i] = sub(v, overhead.Percentile(i))
+	}
+	stat.Percentiles = percentiles
+	return stat
+}
@@ -0,0 +1,79 @@
+package runner
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// chartConverter is the command SVG charts are converted to PNG with
+const chartConverter = "rsvg-convert"
+
+// chartFormats returns the image formats of --chart-format
+func chartFormats(value string) []string {
+	var formats []string
+	for _, format := range strings.Split(value, ",") {
+		formats = append(formats, strings.TrimSpace(format))
+	}
+	return formats
+}
+
+// checkChartConverter fails early when PNG charts are requested but the
+// converter rendering them is missing
+func checkChartConverter(formats []string) error {
+	for _, format := range formats {
+		if format != "png" {
+			continue
+		}
+		if _, err := exec.LookPath(chartConverter); err != nil {
+			return fmt.Errorf("--chart-format=png requires the %s command: %w", chartConverter, err)
+		}
+	}
+	return nil
+}
+
+// writeCharts renders the charts of the HTML report to image files in dir,
+// named after the chart and format, e.g. latency.svg and curve.png. Charts
+// without points are skipped.
+func (r *BenchmarkRunner) writeCharts(dir string) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create charts directory: %w", err)
+	}
+	formats := chartFormats(r.config.ChartFormat)
+	for _, c := range r.htmlCharts() {
+		if c.Empty() {
+			continue
+		}
+		svg := []byte(c.SVG())
+		for _, format := range formats {
+			path := filepath.Join(dir, c.Name+"."+format)
+			var err error
+			switch format {
+			case "svg":
+				err = os.WriteFile(path, svg, 0o644)
+			case "png":
+				err = convertPNG(svg, path)
+			}
+			if err != nil {
+				return fmt.Errorf("failed to write chart %s: %w", path, err)
+			}
+		}
+	}
+	return nil
+}
+
+// convertPNG renders an SVG image to a PNG file at twice its size, so that
+// the charts stay sharp in documents and on high-density screens
+func convertPNG(svg []byte, path string) error {
+	cmd := exec.Command(chartConverter, "--format=png", "--zoom=2", "--output="+path)
+	cmd.Stdin = bytes.NewReader(svg)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("%w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return nil
+}
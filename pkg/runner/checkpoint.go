@@ -0,0 +1,145 @@
+package runner
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"kvstore-benchmarker/pkg/collector"
+)
+
+// checkpointVersion is the version of the checkpoint file layout
+const checkpointVersion = 1
+
+// checkpoint is the resumable state of a run, saved periodically during the
+// measured phase
+type checkpoint struct {
+	Version   int                 `json:"version"`
+	RunID     string              `json:"run_id"`
+	Notes     string              `json:"notes,omitempty"`
+	SavedAt   time.Time           `json:"saved_at"`
+	Active    time.Duration       `json:"active"`   // Active run time, the throughput denominator
+	Position  time.Duration       `json:"position"` // Active time into the measured phase
+	Completed bool                `json:"completed"`
+	Gaps      []runGap            `json:"gaps,omitempty"`
+	Collector *collector.Snapshot `json:"collector"`
+}
+
+// runGap is a period during which the run was interrupted and nothing was
+// measured, from the last checkpoint before the interruption to the resume
+type runGap struct {
+	From time.Time `json:"from"`
+	To   time.Time `json:"to"`
+}
+
+// saveCheckpoint writes the current state to the checkpoint file. The file is
+// replaced atomically so that a crash while saving keeps the previous one.
+func (r *BenchmarkRunner) saveCheckpoint(position time.Duration, completed bool) error {
+	snapshot, err := r.collector.Snapshot()
+	if err != nil {
+		return fmt.Errorf("failed to snapshot collector: %w", err)
+	}
+
+	data, err := json.Marshal(checkpoint{
+		Version:   checkpointVersion,
+		RunID:     r.config.RunID,
+		Notes:     r.config.Notes,
+		SavedAt:   time.Now(),
+		Active:    r.activeElapsed(),
+		Position:  position,
+		Completed: completed,
+		Gaps:      r.gaps,
+		Collector: snapshot,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode checkpoint: %w", err)
+	}
+
+	tmp := r.config.CheckpointPath + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("failed to write checkpoint: %w", err)
+	}
+	if err := os.Rename(tmp, r.config.CheckpointPath); err != nil {
+		return fmt.Errorf("failed to replace checkpoint: %w", err)
+	}
+	return nil
+}
+
+// loadCheckpoint reads a checkpoint file
+func loadCheckpoint(path string) (*checkpoint, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read checkpoint: %w", err)
+	}
+
+	var cp checkpoint
+	if err := json.Unmarshal(data, &cp); err != nil {
+		return nil, fmt.Errorf("failed to parse checkpoint: %w", err)
+	}
+	if cp.Version != checkpointVersion {
+		return nil, fmt.Errorf("unsupported checkpoint version %d", cp.Version)
+	}
+	if cp.Collector == nil {
+		return nil, fmt.Errorf("checkpoint has no collector state")
+	}
+	return &cp, nil
+}
+
+// resume restores the state saved in the checkpoint file, so that the measured
+// phase continues where the checkpoint was taken and the time since then is
+// recorded as a gap
+func (r *BenchmarkRunner) resume() error {
+	cp, err := loadCheckpoint(r.config.CheckpointPath)
+	if err != nil {
+		return err
+	}
+	if cp.Completed {
+		return fmt.Errorf("checkpoint %s belongs to a completed run", r.config.CheckpointPath)
+	}
+	if err := r.collector.Restore(cp.Collector); err != nil {
+		return fmt.Errorf("failed to restore checkpoint: %w", err)
+	}
+
+	gap := runGap{From: cp.SavedAt, To: time.Now()}
+	r.config.RunID = cp.RunID
+	if r.config.Notes == "" {
+		r.config.Notes = cp.Notes
+	}
+	r.resumeAt = cp.Position
+	r.priorActive = cp.Active
+	r.gaps = append(cp.Gaps, gap)
+
+	log.Printf("Resuming run %s at %v of %v (not measured for %v since the checkpoint at %s)",
+		cp.RunID, cp.Position.Round(time.Second), r.config.Duration,
+		gap.To.Sub(gap.From).Round(time.Second), gap.From.Format(time.RFC3339))
+	return nil
+}
+
+// checkpointer saves a checkpoint every checkpoint interval until ctx is done
+func (r *BenchmarkRunner) checkpointer(ctx context.Context, clock *phaseClock) {
+	ticker := time.NewTicker(r.config.CheckpointInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := r.saveCheckpoint(clock.Elapsed(), false); err != nil {
+				log.Printf("Warning: %v", err)
+			}
+		}
+	}
+}
+
+// gapTotal returns the total time the run was interrupted
+func (r *BenchmarkRunner) gapTotal() time.Duration {
+	var total time.Duration
+	for _, gap := range r.gaps {
+		total += gap.To.Sub(gap.From)
+	}
+	return total
+}
@@ -0,0 +1,125 @@
+package runner
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+
+	"kvstore-benchmarker/pkg/compare"
+	"kvstore-benchmarker/pkg/latency"
+	"kvstore-benchmarker/pkg/manifest"
+)
+
+// WriteComparison writes a table of the per-method changes from the baseline
+// to the current results. Changes for the worse by more than threshold percent
+// are styled as regressions, changes for the better as improvements, in color
+// per the --color mode. The runs' notes and the differences between their
+// clients and builds come first. It returns the number of regressions.
+func WriteComparison(out io.Writer, base, current *compare.Results, threshold float64, colorMode string, unit latency.Unit) int {
+	fmt.Fprintf(out, "Baseline: %s\nCurrent:  %s\n\n", describeResults(base), describeResults(current))
+	if base.Notes != "" || current.Notes != "" {
+		fmt.Fprintf(out, "Baseline notes: %s\n", indentNotes(base.Notes))
+		fmt.Fprintf(out, "Current notes:  %s\n\n", indentNotes(current.Notes))
+	}
+	if diffs := clientDifferences(base, current); len(diffs) > 0 {
+		fmt.Fprintf(out, "Warning: the runs used different clients or builds, so their results may not be comparable:\n")
+		for _, diff := range diffs {
+			fmt.Fprintf(out, "  %s\n", diff)
+		}
+		fmt.Fprintln(out)
+	}
+
+	table := newTextTable("Method", "Metric", "Baseline", "Current", "Change")
+	regressions := 0
+	for _, d := range compare.Compare(base, current) {
+		style := ""
+		switch worse := d.Worse(); {
+		case worse > threshold:
+			style = ansiRed
+			regressions++
+		case worse < -threshold:
+			style = ansiGreen
+		}
+		method := tableCell{text: d.Method}
+		if d.Method == compare.Aggregated {
+			method.style = ansiBold
+		}
+		table.addRow(
+			method,
+			tableCell{text: metricLabel(d.Metric, unit)},
+			tableCell{text: formatMetric(d.Metric, d.Base, unit)},
+			tableCell{text: formatMetric(d.Metric, d.Current, unit)},
+			tableCell{text: formatChange(d.Change), style: style},
+		)
+	}
+	table.render(out, colorEnabled(colorMode, out))
+
+	fmt.Fprintf(out, "\n%d regressions beyond %s%%\n", regressions, strconv.FormatFloat(threshold, 'f', -1, 64))
+	return regressions
+}
+
+// clientDifferences describes the differences between the builds and client
+// hardware of two runs, as far as both results record them
+func clientDifferences(base, current *compare.Results) []string {
+	var diffs []string
+	if base.Build != nil && current.Build != nil && base.Build.String() != current.Build.String() {
+		diffs = append(diffs, fmt.Sprintf("Build: %s vs %s", base.Build, current.Build))
+	}
+	if base.Client != nil && current.Client != nil {
+		diffs = append(diffs, manifest.ClientDifferences(*base.Client, *current.Client)...)
+	}
+	return diffs
+}
+
+// indentNotes aligns the continuation lines of run notes with their first
+// line, or returns "none" for a run without notes
+func indentNotes(notes string) string {
+	if notes == "" {
+		return "none"
+	}
+	return strings.ReplaceAll(strings.TrimSpace(notes), "\n", "\n"+strings.Repeat(" ", len("Baseline notes: ")))
+}
+
+// describeResults names the file and run of results
+func describeResults(r *compare.Results) string {
+	if r.RunID == "" {
+		return r.Path
+	}
+	return fmt.Sprintf("%s (run %s)", r.Path, r.RunID)
+}
+
+// metricLabel names a compared metric, e.g. "P99 (ms)"
+func metricLabel(m compare.Metric, unit latency.Unit) string {
+	switch {
+	case m.Latency:
+		return "P" + strings.ReplaceAll(strings.TrimPrefix(m.Name, "p"), "_", ".") + " (" + unit.Name() + ")"
+	case m.Name == "throughput":
+		return "ops/sec"
+	default:
+		return "error%"
+	}
+}
+
+// formatMetric renders the value of a compared metric, "-" if unknown
+func formatMetric(m compare.Metric, v float64, unit latency.Unit) string {
+	switch {
+	case math.IsNaN(v):
+		return "-"
+	case m.Latency:
+		return unit.Value(v)
+	case m.Name == "throughput":
+		return fmt.Sprintf("%.0f", v)
+	default:
+		return fmt.Sprintf("%.2f", v)
+	}
+}
+
+// formatChange renders a relative change in percent
+func formatChange(change float64) string {
+	if math.IsInf(change, 1) {
+		return "new"
+	}
+	return fmt.Sprintf("%+.1f%%", change)
+}
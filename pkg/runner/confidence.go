@@ -0,0 +1,114 @@
+package runner
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"slices"
+	"sort"
+
+	"kvstore-benchmarker/pkg/compare"
+)
+
+// tQuantiles are the 97.5% quantiles of Student's t distribution by degrees
+// of freedom, for two-sided 95% confidence intervals of small samples
+var tQuantiles = []float64{
+	1: 12.706, 2: 4.303, 3: 3.182, 4: 2.776, 5: 2.571, 6: 2.447, 7: 2.365, 8: 2.306, 9: 2.262, 10: 2.228,
+	11: 2.201, 12: 2.179, 13: 2.160, 14: 2.145, 15: 2.131, 16: 2.120, 17: 2.110, 18: 2.101, 19: 2.093, 20: 2.086,
+	21: 2.080, 22: 2.074, 23: 2.069, 24: 2.064, 25: 2.060, 26: 2.056, 27: 2.052, 28: 2.048, 29: 2.045, 30: 2.042,
+}
+
+// tQuantile returns the 97.5% quantile of Student's t distribution with df
+// degrees of freedom, approximated from the normal quantile above 30
+func tQuantile(df int) float64 {
+	if df < len(tQuantiles) {
+		return tQuantiles[df]
+	}
+	const z = 1.959964
+	return z + (z*z*z+z)/(4*float64(df))
+}
+
+// confidence95 returns the half-width of the 95% confidence interval of the
+// mean of values, NaN for fewer than two values
+func confidence95(s spread, n int) float64 {
+	if n < 2 {
+		return math.NaN()
+	}
+	return tQuantile(n-1) * s.stddev / math.Sqrt(float64(n))
+}
+
+// printConfidence prints 95% confidence intervals of the mean throughput and
+// latency percentiles of every method, treating the report intervals as
+// samples. Report intervals are not independent (load and backend state carry
+// over), so these understate the uncertainty; repeated runs give better ones.
+func (r *BenchmarkRunner) printConfidence(out io.Writer, color bool) {
+	points := r.intervals.snapshot()
+	if len(points) < 2 {
+		return
+	}
+	unit := r.unit()
+	percentiles := r.collector.Percentiles()
+
+	var methods []string
+	for _, point := range points {
+		for method := range point.methods {
+			if !slices.Contains(methods, method) {
+				methods = append(methods, method)
+			}
+		}
+	}
+	sort.Strings(methods)
+
+	table := newTextTable("Method", "Metric", "Mean", "+/-95%", "Low", "High")
+	addRow := func(method string, metric compare.Metric, values []float64) {
+		if len(values) < 2 {
+			return
+		}
+		s := spreadOf(values)
+		half := confidence95(s, len(values))
+		style := ""
+		if method == compare.Aggregated {
+			style = ansiBold
+		}
+		table.addRow(
+			tableCell{text: method, style: style},
+			tableCell{text: metricLabel(metric, unit)},
+			tableCell{text: formatMetric(metric, s.mean, unit)},
+			tableCell{text: formatMetric(metric, half, unit)},
+			tableCell{text: formatMetric(metric, s.mean-half, unit)},
+			tableCell{text: formatMetric(metric, s.mean+half, unit)},
+		)
+	}
+	addMethod := func(method string, throughput func(timelinePoint) (float64, bool), latencies func(timelinePoint) []float64) {
+		var values []float64
+		for _, point := range points {
+			if v, ok := throughput(point); ok {
+				values = append(values, v)
+			}
+		}
+		addRow(method, compare.Metric{Name: "throughput", Higher: true}, values)
+
+		for i, p := range percentiles {
+			values = values[:0]
+			for _, point := range points {
+				if l := latencies(point); i < len(l) {
+					values = append(values, l[i])
+				}
+			}
+			addRow(method, compare.Metric{Name: percentileKey(p), Latency: true}, values)
+		}
+	}
+
+	for _, method := range methods {
+		addMethod(method,
+			func(point timelinePoint) (float64, bool) { v, ok := point.methods[method]; return v, ok },
+			func(point timelinePoint) []float64 { return point.methodPercentiles[method] })
+	}
+	addMethod(compare.Aggregated,
+		func(point timelinePoint) (float64, bool) { return point.throughput, true },
+		func(point timelinePoint) []float64 { return point.percentiles })
+
+	fmt.Fprintf(out, "=== 95%% CONFIDENCE INTERVALS (%d report intervals) ===\n\n", len(points))
+	table.render(out, color)
+	fmt.Fprintln(out)
+}
@@ -0,0 +1,225 @@
+package runner
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"kvstore-benchmarker/pkg/kvclient"
+)
+
+// convergencePollInterval is the pause between read rounds of a convergence probe
+const convergencePollInterval = 5 * time.Millisecond
+
+// convergenceProbe stresses read repair and anti-entropy: each probe writes
+// conflicting versions of a fresh key through every endpoint at once, then
+// reads it back from all of them until they agree, timing the convergence
+type convergenceProbe struct {
+	endpoints []string
+	clients   []*kvclient.Client
+	interval  time.Duration
+	timeout   time.Duration
+	runID     string
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+
+	mu       sync.Mutex
+	seq      int
+	times    []time.Duration
+	timedOut int
+	failed   int
+}
+
+// newConvergenceProbe connects to the configured endpoints with opts,
+// sending the request credentials, or returns nil if none are configured
+func newConvergenceProbe(endpoints string, interval, timeout time.Duration, runID string, opts kvclient.Options, creds *requestCredentials) (*convergenceProbe, error) {
+	p := &convergenceProbe{interval: interval, timeout: timeout, runID: runID}
+	for _, endpoint := range strings.Split(endpoints, ",") {
+		if endpoint = strings.TrimSpace(endpoint); endpoint == "" {
+			continue
+		}
+		client, err := kvclient.NewClient(endpoint, opts)
+		if err != nil {
+			p.close()
+			return nil, err
+		}
+		creds.apply(client)
+		p.endpoints = append(p.endpoints, endpoint)
+		p.clients = append(p.clients, client)
+	}
+	if len(p.clients) == 0 {
+		return nil, nil
+	}
+	return p, nil
+}
+
+// start runs a probe every interval until stop is called
+func (p *convergenceProbe) start(ctx context.Context) {
+	ctx, p.cancel = context.WithCancel(ctx)
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+		ticker := time.NewTicker(p.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				p.probe(ctx)
+			}
+		}
+	}()
+}
+
+// stop ends probing and waits for a running probe to finish
+func (p *convergenceProbe) stop() {
+	if p.cancel != nil {
+		p.cancel()
+	}
+	p.wg.Wait()
+}
+
+// close closes the endpoint connections
+func (p *convergenceProbe) close() {
+	for _, client := range p.clients {
+		client.Close()
+	}
+}
+
+// probe writes conflicting versions of a fresh key and waits for the
+// endpoints to converge on one of them
+func (p *convergenceProbe) probe(ctx context.Context) {
+	p.mu.Lock()
+	p.seq++
+	key := []byte(fmt.Sprintf("conv/%s/%d", p.runID, p.seq))
+	p.mu.Unlock()
+
+	// Release all writes together so that they race
+	var wg sync.WaitGroup
+	errs := make([]error, len(p.clients))
+	startWrites := make(chan struct{})
+	for i, client := range p.clients {
+		wg.Add(1)
+		go func(i int, client *kvclient.Client) {
+			defer wg.Done()
+			<-startWrites
+			value := []byte(fmt.Sprintf("%s/%d/%d", p.endpoints[i], i, time.Now().UnixNano()))
+			opCtx, cancel := context.WithTimeout(ctx, p.timeout)
+			defer cancel()
+			_, errs[i] = client.Put(opCtx, key, value)
+		}(i, client)
+	}
+	close(startWrites)
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil {
+			if ctx.Err() == nil {
+				log.Printf("Warning: convergence probe write failed: %v", err)
+				p.record(0, false, true)
+			}
+			return
+		}
+	}
+
+	// Read from every endpoint until they all return the same version
+	written := time.Now()
+	deadline := written.Add(p.timeout)
+	for {
+		converged, err := p.agree(ctx, key)
+		if ctx.Err() != nil {
+			return
+		}
+		if err == nil && converged {
+			p.record(time.Since(written), true, false)
+			return
+		}
+		if time.Now().After(deadline) {
+			p.record(0, false, false)
+			return
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(convergencePollInterval):
+		}
+	}
+}
+
+// agree reads the key from every endpoint and reports whether all found the
+// same value
+func (p *convergenceProbe) agree(ctx context.Context, key []byte) (bool, error) {
+	var first []byte
+	for i, client := range p.clients {
+		resp, err := client.Get(ctx, key)
+		if err != nil {
+			return false, err
+		}
+		if !resp.Found {
+			return false, nil
+		}
+		if i == 0 {
+			first = resp.Value
+		} else if string(resp.Value) != string(first) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// record adds the outcome of a probe
+func (p *convergenceProbe) record(d time.Duration, converged, failed bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	switch {
+	case converged:
+		p.times = append(p.times, d)
+	case failed:
+		p.failed++
+	default:
+		p.timedOut++
+	}
+}
+
+// printConvergence reports the convergence times of the probes
+func (r *BenchmarkRunner) printConvergence() {
+	p := r.convergence
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	probes := len(p.times) + p.timedOut + p.failed
+	line := fmt.Sprintf("Convergence: %d probes over %d endpoints, %d converged, %d timed out (>%v), %d failed writes",
+		probes, len(p.clients), len(p.times), p.timedOut, p.timeout, p.failed)
+	if len(p.times) > 0 {
+		times := append([]time.Duration(nil), p.times...)
+		sort.Slice(times, func(i, j int) bool { return times[i] < times[j] })
+		var total time.Duration
+		for _, t := range times {
+			total += t
+		}
+		unit := r.unit()
+		ms := func(d time.Duration) string { return unit.Display(float64(d) / float64(time.Millisecond)) }
+		line += fmt.Sprintf(" | Avg: %s | P50: %s | P99: %s | Max: %s",
+			ms(total/time.Duration(len(times))), ms(nearestRank(times, 50)), ms(nearestRank(times, 99)), ms(times[len(times)-1]))
+	}
+	log.Print(line)
+}
+
+// nearestRank returns the nearest-rank percentile of sorted durations
+func nearestRank(sorted []time.Duration, p float64) time.Duration {
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
@@ -0,0 +1,48 @@
+package runner
+
+import (
+	"context"
+	"time"
+)
+
+// dutyCycle alternates between a loaded and an idle window, starting with load
+type dutyCycle struct {
+	on    time.Duration
+	off   time.Duration
+	clock *phaseClock
+}
+
+// newDutyCycle creates a duty cycle following the phase clock, or nil if disabled
+func newDutyCycle(on, off time.Duration, clock *phaseClock) *dutyCycle {
+	if on <= 0 || off <= 0 {
+		return nil
+	}
+	return &dutyCycle{on: on, off: off, clock: clock}
+}
+
+// idleRemaining returns how long the cycle stays idle at the given phase time, or 0 during load
+func (d *dutyCycle) idleRemaining(elapsed time.Duration) time.Duration {
+	pos := elapsed % (d.on + d.off)
+	if pos < d.on {
+		return 0
+	}
+	return d.on + d.off - pos
+}
+
+// wait blocks while the cycle is idle. It returns false if ctx is done first.
+func (d *dutyCycle) wait(ctx context.Context) bool {
+	idle := d.idleRemaining(d.clock.Elapsed())
+	if idle == 0 {
+		return true
+	}
+
+	timer := time.NewTimer(idle)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
@@ -0,0 +1,137 @@
+package runner
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// energySampleTimeout bounds a single run of the energy sampling command
+const energySampleTimeout = 5 * time.Second
+
+// energyMeter samples an external power or energy command, such as a RAPL or
+// IPMI script, and accumulates the energy used by the measured phase
+type energyMeter struct {
+	command string
+	power   bool // The command prints watts instead of a cumulative joule counter
+
+	mu      sync.Mutex
+	started bool
+	last    float64 // Last reading, joules or watts
+	lastAt  time.Time
+	lastOps int64
+	joules  float64
+	ops     int64
+	failed  bool
+}
+
+// newEnergyMeter returns a meter for the command, or nil if none is configured
+func newEnergyMeter(command, mode string) *energyMeter {
+	if command == "" {
+		return nil
+	}
+	return &energyMeter{command: command, power: mode == "power"}
+}
+
+// read runs the command and parses the first number it prints
+func (m *energyMeter) read(ctx context.Context) (float64, error) {
+	ctx, cancel := context.WithTimeout(ctx, energySampleTimeout)
+	defer cancel()
+
+	out, err := exec.CommandContext(ctx, "sh", "-c", m.command).Output()
+	if err != nil {
+		return 0, fmt.Errorf("failed to run energy command: %w", err)
+	}
+	fields := strings.Fields(string(out))
+	if len(fields) == 0 {
+		return 0, fmt.Errorf("energy command printed nothing")
+	}
+	value, err := strconv.ParseFloat(fields[0], 64)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse energy command output %q: %w", fields[0], err)
+	}
+	return value, nil
+}
+
+// sample takes a reading at the given total operation count and returns the
+// joules and operations since the previous reading; ok is false for the
+// first reading and on failures, which are logged once
+func (m *energyMeter) sample(ctx context.Context, ops int64) (joules float64, deltaOps int64, ok bool) {
+	value, err := m.read(ctx)
+	now := time.Now()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if err != nil {
+		if !m.failed {
+			log.Printf("Warning: %v", err)
+			m.failed = true
+		}
+		return 0, 0, false
+	}
+
+	if m.started {
+		if m.power {
+			// Trapezoidal integration of the power readings
+			joules = (m.last + value) / 2 * now.Sub(m.lastAt).Seconds()
+		} else {
+			joules = value - m.last
+		}
+		deltaOps = ops - m.lastOps
+		ok = joules > 0
+		if ok {
+			m.joules += joules
+			m.ops += deltaOps
+		}
+	}
+	m.started = true
+	m.last, m.lastAt, m.lastOps = value, now, ops
+	return joules, deltaOps, ok
+}
+
+// totals returns the energy and operations of all sampled intervals
+func (m *energyMeter) totals() (joules float64, ops int64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.joules, m.ops
+}
+
+// sampleEnergy samples the energy meter, logging the efficiency of the
+// interval since the previous sample
+func (r *BenchmarkRunner) sampleEnergy(ctx context.Context) {
+	if r.energy == nil {
+		return
+	}
+	joules, ops, ok := r.energy.sample(ctx, r.collector.GetAggregatedStats().Count)
+	if ok {
+		log.Printf("Energy: %.1f J over the interval, %.1f ops/joule", joules, float64(ops)/joules)
+	}
+}
+
+// printEnergy reports the energy efficiency of the measured phase
+func (r *BenchmarkRunner) printEnergy() {
+	if r.energy == nil {
+		return
+	}
+	joules, ops := r.energy.totals()
+	if joules <= 0 {
+		log.Printf("Energy: no valid samples")
+		return
+	}
+	log.Printf("Energy: %.1f J, %.1f ops/joule", joules, float64(ops)/joules)
+}
+
+// opsPerJoule returns the operations per joule of the measured phase, 0 without samples
+func (r *BenchmarkRunner) opsPerJoule() float64 {
+	joules, ops := r.energy.totals()
+	if joules <= 0 {
+		return 0
+	}
+	return float64(ops) / joules
+}
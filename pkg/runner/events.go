@@ -0,0 +1,168 @@
+package runner
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"kvstore-benchmarker/pkg/collector"
+	"kvstore-benchmarker/pkg/results"
+)
+
+const (
+	eventBuffer    = 16               // Events queued per client before it misses some
+	eventKeepAlive = 15 * time.Second // Comment lines keeping idle streams open through proxies
+)
+
+// event is an encoded Server-Sent Event
+type event struct {
+	name string
+	data []byte
+}
+
+// eventStream is a sink broadcasting interval statistics as Server-Sent
+// Events to the clients of the web dashboard's /events endpoint: an
+// "interval" event per report interval and a "final" event with the whole
+// run, after which the streams end. Clients too slow to keep up miss events
+// rather than holding up the collector.
+type eventStream struct {
+	runID       string
+	percentiles []float64
+
+	mu      sync.Mutex
+	clients map[chan event]struct{}
+	closed  bool
+}
+
+// newEventStream creates a stream of the intervals of a run with no clients
+func newEventStream(runID string, percentiles []float64) *eventStream {
+	return &eventStream{
+		runID:       runID,
+		percentiles: percentiles,
+		clients:     make(map[chan event]struct{}),
+	}
+}
+
+// Start implements Sink; clients connect on their own
+func (s *eventStream) Start(time.Time) error { return nil }
+
+// RecordInterval sends the statistics of an interval to every client
+func (s *eventStream) RecordInterval(interval collector.SinkStats) error {
+	return s.send("interval", interval)
+}
+
+// RecordFinal sends the statistics of the whole run to every client
+func (s *eventStream) RecordFinal(final collector.SinkStats) error {
+	return s.send("final", final)
+}
+
+// Close ends the streams of all clients
+func (s *eventStream) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for client := range s.clients {
+		close(client)
+	}
+	s.clients = nil
+	s.closed = true
+	return nil
+}
+
+// send encodes statistics in the results schema and queues them for every
+// client that has room
+func (s *eventStream) send(name string, stats collector.SinkStats) error {
+	interval := results.Interval{
+		SchemaVersion: results.SchemaVersion,
+		RunID:         s.runID,
+		Start:         stats.Start,
+		End:           stats.End,
+		OffsetS:       stats.Offset.Seconds(),
+		DurationS:     stats.Elapsed.Seconds(),
+		ThroughputOps: stats.Throughput(stats.Aggregated),
+		Methods:       make([]results.Stats, 0, len(stats.Methods)),
+		Aggregated:    newResultStats(stats.Aggregated, s.percentiles),
+	}
+	for _, method := range stats.Methods {
+		interval.Methods = append(interval.Methods, newResultStats(method, s.percentiles))
+	}
+	data, err := json.Marshal(interval)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s event: %w", name, err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for client := range s.clients {
+		select {
+		case client <- event{name: name, data: data}:
+		default:
+		}
+	}
+	return nil
+}
+
+// subscribe registers a client, returning its queue of events; nil once the
+// run has ended
+func (s *eventStream) subscribe() chan event {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return nil
+	}
+	client := make(chan event, eventBuffer)
+	s.clients[client] = struct{}{}
+	return client
+}
+
+// unsubscribe removes a client that disconnected
+func (s *eventStream) unsubscribe(client chan event) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.clients[client]; ok {
+		delete(s.clients, client)
+		close(client)
+	}
+}
+
+// serve streams the events to a client until the run or the connection ends
+func (s *eventStream) serve(w http.ResponseWriter, req *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming not supported", http.StatusInternalServerError)
+		return
+	}
+	client := s.subscribe()
+	if client == nil {
+		http.Error(w, "the benchmark has finished", http.StatusGone)
+		return
+	}
+	defer s.unsubscribe(client)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-store")
+	fmt.Fprintf(w, ": run %s\n\n", s.runID)
+	flusher.Flush()
+
+	keepAlive := time.NewTicker(eventKeepAlive)
+	defer keepAlive.Stop()
+	for {
+		select {
+		case <-req.Context().Done():
+			return
+		case <-keepAlive.C:
+			fmt.Fprint(w, ": keep-alive\n\n")
+		case e, ok := <-client:
+			if !ok {
+				return
+			}
+			if _, err := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", e.name, e.data); err != nil {
+				log.Printf("Warning: event stream client dropped: %v", err)
+				return
+			}
+		}
+		flusher.Flush()
+	}
+}
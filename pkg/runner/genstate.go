@@ -0,0 +1,116 @@
+package runner
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+)
+
+// generatorStateVersion is the version of the generator state file layout
+const generatorStateVersion = 1
+
+// generatorState is the key generator saved between sessions, so that a
+// restarted benchmarker addresses the same dataset. Random key encodings have
+// no seed, so the keys themselves are saved.
+type generatorState struct {
+	Version     int       `json:"version"`
+	SavedAt     time.Time `json:"saved_at"`
+	KeyEncoding string    `json:"key_encoding"`
+	KeySpace    int       `json:"key_space"`
+	KeyTenants  int       `json:"key_tenants"`
+	Keys        [][]byte  `json:"keys"`
+	Written     []byte    `json:"written"` // Bitmap of keys holding a written value
+	KeyIndex    int       `json:"key_index"`
+	Generated   int64     `json:"generated"` // Keys handed out over all sessions
+}
+
+// SaveState writes the generator state to a file, replacing it atomically
+func (kg *KeyGenerator) SaveState(path string) error {
+	kg.mu.RLock()
+	kg.writtenMu.Lock()
+	state := generatorState{
+		Version:     generatorStateVersion,
+		SavedAt:     time.Now(),
+		KeyEncoding: kg.encoding,
+		KeySpace:    len(kg.keys),
+		KeyTenants:  kg.tenants,
+		Keys:        kg.keys,
+		Written:     make([]byte, (len(kg.written)+7)/8),
+		KeyIndex:    kg.keyIndex,
+		Generated:   kg.generated.Load(),
+	}
+	for i, w := range kg.written {
+		if w {
+			state.Written[i/8] |= 1 << (i % 8)
+		}
+	}
+	data, err := json.Marshal(state)
+	kg.writtenMu.Unlock()
+	kg.mu.RUnlock()
+	if err != nil {
+		return fmt.Errorf("failed to encode generator state: %w", err)
+	}
+
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("failed to write generator state: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("failed to replace generator state: %w", err)
+	}
+	return nil
+}
+
+// LoadKeyGenerator restores a key generator saved with SaveState. The saved
+// key space must match the requested one. It returns nil without an error if
+// the file doesn't exist yet.
+func LoadKeyGenerator(path string, keySpace int, encoding string, tenants int) (*KeyGenerator, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read generator state: %w", err)
+	}
+
+	var state generatorState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("failed to parse generator state: %w", err)
+	}
+	if state.Version != generatorStateVersion {
+		return nil, fmt.Errorf("unsupported generator state version %d", state.Version)
+	}
+	if encoding == "" {
+		encoding = KeyEncodingRandom
+	}
+	if state.KeyEncoding == "" {
+		state.KeyEncoding = KeyEncodingRandom
+	}
+	if encoding != KeyEncodingComposite {
+		// Tenants only shape composite keys
+		state.KeyTenants = tenants
+	}
+	if state.KeySpace != keySpace || state.KeyEncoding != encoding || state.KeyTenants != tenants {
+		return nil, fmt.Errorf("generator state is for %d %s keys over %d tenants, not %d %s keys over %d tenants",
+			state.KeySpace, state.KeyEncoding, state.KeyTenants, keySpace, encoding, tenants)
+	}
+	if len(state.Keys) != keySpace || len(state.Written) != (keySpace+7)/8 || state.KeyIndex < 0 || state.KeyIndex >= keySpace {
+		return nil, fmt.Errorf("generator state is corrupt")
+	}
+
+	written := make([]bool, keySpace)
+	for i := range written {
+		written[i] = state.Written[i/8]&(1<<(i%8)) != 0
+	}
+	kg := &KeyGenerator{
+		keys:     state.Keys,
+		keyIndex: state.KeyIndex,
+		encoding: encoding,
+		tenants:  tenants,
+		written:  written,
+	}
+	kg.generated.Store(state.Generated)
+	return kg, nil
+}
@@ -0,0 +1,54 @@
+package runner
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"kvstore-benchmarker/pkg/collector"
+	"kvstore-benchmarker/pkg/config"
+	"kvstore-benchmarker/pkg/grafana"
+)
+
+// newAnnotator creates the Grafana annotator of the configuration, tagging
+// annotations with the run ID and labels, or returns nil without --grafana-url
+func newAnnotator(cfg *config.BenchmarkConfig) (*grafana.Annotator, error) {
+	if cfg.GrafanaURL == "" {
+		return nil, nil
+	}
+	var token string
+	if cfg.GrafanaTokenFile != "" {
+		data, err := os.ReadFile(cfg.GrafanaTokenFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read Grafana token: %w", err)
+		}
+		token = strings.TrimSpace(string(data))
+	}
+
+	tags := []string{"run_id:" + cfg.RunID}
+	labels, _ := collector.ParseLabels(cfg.Labels)
+	for _, label := range labels {
+		tags = append(tags, label.Name+":"+label.Value)
+	}
+	return grafana.NewAnnotator(cfg.GrafanaURL, token, tags), nil
+}
+
+// annotatePhases marks the measured phase and its named phases as regions on
+// Grafana graphs
+func (r *BenchmarkRunner) annotatePhases(start, end time.Time) {
+	if r.annotator == nil {
+		return
+	}
+	r.annotator.Region(start, end, fmt.Sprintf("Run %s: benchmark phase", r.config.RunID))
+	for i, phase := range r.phases {
+		if i >= len(r.phaseStarts) || r.phaseStarts[i].After(end) {
+			break
+		}
+		phaseEnd := end
+		if i+1 < len(r.phaseStarts) && r.phaseStarts[i+1].Before(end) {
+			phaseEnd = r.phaseStarts[i+1]
+		}
+		r.annotator.Region(r.phaseStarts[i], phaseEnd, fmt.Sprintf("Run %s: phase %s", r.config.RunID, phase.Name))
+	}
+}
@@ -0,0 +1,100 @@
+package runner
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+)
+
+// postRunTimeout bounds how long a --post-run command may take
+const postRunTimeout = 5 * time.Minute
+
+// PostProcessor receives the final report of a run, and the path of the
+// run's JSON manifest, when the benchmarker is used as a library
+type PostProcessor func(report *Report, manifestPath string) error
+
+// postProcessor is a registered post-processor with the name used in warnings
+type postProcessor struct {
+	name string
+	fn   PostProcessor
+}
+
+var (
+	postProcessorsMu sync.Mutex
+	postProcessors   []postProcessor
+)
+
+// RegisterPostProcessor registers a function called with the results at the
+// end of every run, in registration order, after the --post-run command.
+// Errors are logged and do not fail the run.
+func RegisterPostProcessor(name string, fn PostProcessor) {
+	postProcessorsMu.Lock()
+	defer postProcessorsMu.Unlock()
+	postProcessors = append(postProcessors, postProcessor{name: name, fn: fn})
+}
+
+// runPostProcessors hands the results to the --post-run command and the
+// registered post-processors. Without --manifest, the manifest they receive
+// is written to a temporary file removed afterwards.
+func (r *BenchmarkRunner) runPostProcessors() {
+	postProcessorsMu.Lock()
+	processors := append([]postProcessor(nil), postProcessors...)
+	postProcessorsMu.Unlock()
+	if r.config.PostRun == "" && len(processors) == 0 {
+		return
+	}
+
+	path := r.config.ManifestPath
+	if path == "" {
+		file, err := os.CreateTemp("", "kvbench-"+r.config.RunID+"-*.json")
+		if err != nil {
+			log.Printf("Warning: failed to create results file for post-processors: %v", err)
+			return
+		}
+		file.Close()
+		path = file.Name()
+		defer os.Remove(path)
+		if err := r.writeManifest(path); err != nil {
+			log.Printf("Warning: %v", err)
+			return
+		}
+	}
+
+	if r.config.PostRun != "" {
+		if err := r.execPostRun(path); err != nil {
+			log.Printf("Warning: post-run command failed: %v", err)
+		}
+	}
+	report := r.report()
+	for _, p := range processors {
+		if err := p.fn(report, path); err != nil {
+			log.Printf("Warning: post-processor %s failed: %v", p.name, err)
+		}
+	}
+}
+
+// execPostRun runs the --post-run command with the manifest path as its last
+// argument and in KVBENCH_RESULT, and the run ID in KVBENCH_RUN_ID. Its
+// output goes to stderr, keeping stdout for machine-readable output.
+func (r *BenchmarkRunner) execPostRun(path string) error {
+	args := strings.Fields(r.config.PostRun)
+	if len(args) == 0 {
+		return fmt.Errorf("no post-run command")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), postRunTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, args[0], append(args[1:], path)...)
+	cmd.Env = append(os.Environ(), "KVBENCH_RESULT="+path, "KVBENCH_RUN_ID="+r.config.RunID)
+	cmd.Stdout = os.Stderr
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("%s: %w", args[0], err)
+	}
+	return nil
+}
@@ -0,0 +1,328 @@
+package runner
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"kvstore-benchmarker/pkg/chart"
+	"kvstore-benchmarker/pkg/collector"
+	"kvstore-benchmarker/pkg/manifest"
+)
+
+// timelinePoint is the aggregated statistics of one report interval
+type timelinePoint struct {
+	offset      float64            // End of the interval in seconds since the start of the run
+	throughput  float64            // Successful ops/sec of all methods
+	offered     float64            // Ops/sec attempted, throttled or not
+	admitted    float64            // Ops/sec not throttled by the server
+	throttled   bool               // Whether the server throttled any operation
+	errors      float64            // Failed ops/sec of all methods
+	methods     map[string]float64 // Successful ops/sec by method
+	percentiles []float64          // Latencies in milliseconds at the collector's percentiles
+
+	methodPercentiles map[string][]float64 // Latencies at the collector's percentiles by method
+}
+
+// intervalSeries is a sink keeping the interval statistics the HTML report
+// charts and confidence intervals are computed from
+type intervalSeries struct {
+	mu     sync.Mutex
+	points []timelinePoint
+}
+
+// Start implements Sink; the timeline starts empty
+func (t *intervalSeries) Start(time.Time) error { return nil }
+
+// RecordInterval keeps the throughput and percentiles of an interval
+func (t *intervalSeries) RecordInterval(interval collector.SinkStats) error {
+	if interval.Aggregated.Count == 0 {
+		return nil
+	}
+	point := timelinePoint{
+		offset:      interval.Offset.Seconds(),
+		throughput:  interval.Throughput(interval.Aggregated),
+		methods:     make(map[string]float64, len(interval.Methods)),
+		percentiles: interval.Aggregated.Percentiles,
+		throttled:   interval.Aggregated.Throttled > 0,
+
+		methodPercentiles: make(map[string][]float64, len(interval.Methods)),
+	}
+	if elapsed := interval.Elapsed.Seconds(); elapsed > 0 {
+		point.offered = float64(interval.Aggregated.Count) / elapsed
+		point.admitted = float64(interval.Aggregated.Admitted()) / elapsed
+		point.errors = float64(interval.Aggregated.ErrorCount) / elapsed
+	}
+	for _, stats := range interval.Methods {
+		point.methods[stats.Method] = interval.Throughput(stats)
+		point.methodPercentiles[stats.Method] = stats.Percentiles
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.points = append(t.points, point)
+	return nil
+}
+
+// RecordFinal implements Sink; the report takes the final statistics from the collector
+func (t *intervalSeries) RecordFinal(collector.SinkStats) error { return nil }
+
+// Close implements Sink; the points stay available to the report
+func (t *intervalSeries) Close() error { return nil }
+
+// snapshot returns the points recorded so far
+func (t *intervalSeries) snapshot() []timelinePoint {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return append([]timelinePoint(nil), t.points...)
+}
+
+// htmlCell is a results table cell of the HTML report
+type htmlCell struct {
+	Text  string
+	Class string
+}
+
+// htmlReport is the data of the HTML report template
+type htmlReport struct {
+	*Report
+	Target      string
+	Load        string
+	Measured    string
+	Build       string
+	Client      string
+	Header      []string
+	Rows        [][]htmlCell
+	Charts      []template.HTML
+	Config      string
+	GeneratedAt time.Time
+}
+
+// writeHTMLReport writes a self-contained HTML report with the results, charts
+// of latency and throughput over time and the effective configuration to path,
+// or stdout if path is "-". Charts are inline SVG, so the file opens in any
+// browser without network access.
+func (r *BenchmarkRunner) writeHTMLReport(path string) error {
+	report := r.report()
+	cfg := r.config
+	data := htmlReport{
+		Report: report,
+		Target: cfg.TargetAddress,
+		Load: fmt.Sprintf("%d connections, %d workers, %d%% reads, %d%% writes, %d%% deletes",
+			cfg.NumConnections, cfg.NumWorkers, cfg.ReadRatio, cfg.WriteRatio, cfg.DeleteRatio),
+		Measured: fmt.Sprintf("%v from %s to %s, %.0f ops/sec",
+			report.Duration.Round(time.Millisecond), report.Start.UTC().Format(time.RFC3339),
+			report.End.UTC().Format(time.RFC3339), report.Throughput),
+		Build:       "kvstore-benchmarker " + report.Build.String(),
+		Client:      describeClient(report.Client),
+		GeneratedAt: time.Now(),
+	}
+
+	table, _ := r.statsTable(r.collector.GetStats(), report.Aggregated)
+	data.Header = table.header
+	data.Rows = htmlRows(table)
+	data.Charts = htmlSVGs(r.htmlCharts())
+
+	config, err := json.MarshalIndent(cfg, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode configuration: %w", err)
+	}
+	data.Config = string(config)
+
+	var b bytes.Buffer
+	if err := htmlTemplate.Execute(&b, data); err != nil {
+		return fmt.Errorf("failed to render HTML report: %w", err)
+	}
+	if path == "-" {
+		_, err := os.Stdout.Write(b.Bytes())
+		return err
+	}
+	if err := os.WriteFile(path, b.Bytes(), 0o644); err != nil {
+		return fmt.Errorf("failed to write HTML report: %w", err)
+	}
+	return nil
+}
+
+// htmlRows converts the rows of a results table to HTML cells
+func htmlRows(table *textTable) [][]htmlCell {
+	var rows [][]htmlCell
+	for _, row := range table.rows {
+		cells := make([]htmlCell, len(row))
+		for i, cell := range row {
+			cells[i] = htmlCell{Text: cell.text, Class: htmlClasses[cell.style]}
+		}
+		rows = append(rows, cells)
+	}
+	return rows
+}
+
+// htmlSVGs renders the charts that have data as inline SVG
+func htmlSVGs(charts []chart.Chart) []template.HTML {
+	var svgs []template.HTML
+	for _, c := range charts {
+		if !c.Empty() {
+			svgs = append(svgs, template.HTML(c.SVG()))
+		}
+	}
+	return svgs
+}
+
+// describeClient summarizes the client machine in a line, e.g. "bench-1,
+// linux/amd64, 16 CPUs (GOMAXPROCS 16), go1.22.1"
+func describeClient(c manifest.Client) string {
+	return fmt.Sprintf("%s, %s/%s, %d CPUs (GOMAXPROCS %d), %s", c.Hostname, c.OS, c.Arch, c.CPUs, c.GOMAXPROCS, c.GoVersion)
+}
+
+// htmlCharts builds the latency over time, throughput over time and
+// throughput/latency charts from the interval statistics, plus offered
+// against admitted load when the server throttled operations
+func (r *BenchmarkRunner) htmlCharts() []chart.Chart {
+	if r.intervals == nil {
+		return nil
+	}
+	points := r.intervals.snapshot()
+	unit := r.unit()
+	percentiles := r.collector.Percentiles()
+
+	latency := chart.Chart{
+		Name:   "latency",
+		Title:  "Latency over time",
+		XLabel: "Time since start (s)",
+		YLabel: "Latency (" + unit.Name() + ")",
+	}
+	curve := chart.Chart{
+		Name:    "curve",
+		Title:   "Throughput vs. latency (one point per interval)",
+		XLabel:  "Throughput (ops/sec)",
+		YLabel:  "Latency (" + unit.Name() + ")",
+		Scatter: true,
+	}
+	for i, p := range percentiles {
+		over := chart.Series{Name: collector.PercentileLabel(p)}
+		against := chart.Series{Name: collector.PercentileLabel(p)}
+		for _, point := range points {
+			if i >= len(point.percentiles) {
+				continue
+			}
+			ms := unit.FromMillis(point.percentiles[i])
+			over.Points = append(over.Points, chart.Point{X: point.offset, Y: ms})
+			against.Points = append(against.Points, chart.Point{X: point.throughput, Y: ms})
+		}
+		latency.Series = append(latency.Series, over)
+		curve.Series = append(curve.Series, against)
+	}
+
+	throughput := chart.Chart{
+		Name:   "throughput",
+		Title:  "Throughput over time",
+		XLabel: "Time since start (s)",
+		YLabel: "Throughput (ops/sec)",
+	}
+	total := chart.Series{Name: "Total"}
+	byMethod := make(map[string]*chart.Series)
+	var methods []string
+	for _, point := range points {
+		total.Points = append(total.Points, chart.Point{X: point.offset, Y: point.throughput})
+		for method, ops := range point.methods {
+			s, ok := byMethod[method]
+			if !ok {
+				s = &chart.Series{Name: method}
+				byMethod[method] = s
+				methods = append(methods, method)
+			}
+			s.Points = append(s.Points, chart.Point{X: point.offset, Y: ops})
+		}
+	}
+	throughput.Series = append(throughput.Series, total)
+	sort.Strings(methods)
+	if len(methods) > 1 {
+		for _, method := range methods {
+			throughput.Series = append(throughput.Series, *byMethod[method])
+		}
+	}
+
+	charts := []chart.Chart{latency, throughput, curve}
+
+	// Offered against admitted load shows how much the server shed
+	load := chart.Chart{
+		Name:   "load",
+		Title:  "Offered vs. admitted load",
+		XLabel: "Time since start (s)",
+		YLabel: "Operations (ops/sec)",
+	}
+	offered := chart.Series{Name: "Offered"}
+	admitted := chart.Series{Name: "Admitted"}
+	throttled := false
+	for _, point := range points {
+		offered.Points = append(offered.Points, chart.Point{X: point.offset, Y: point.offered})
+		admitted.Points = append(admitted.Points, chart.Point{X: point.offset, Y: point.admitted})
+		throttled = throttled || point.throttled
+	}
+	if throttled {
+		load.Series = []chart.Series{offered, admitted}
+		charts = append(charts, load)
+	}
+	return charts
+}
+
+// htmlClasses maps the ANSI styles of results table cells to CSS classes
+var htmlClasses = map[string]string{
+	ansiBold:  "total",
+	ansiRed:   "bad",
+	ansiGreen: "good",
+}
+
+// htmlStyle is the style sheet of the HTML report and the web dashboard
+const htmlStyle = `<style>
+body { font-family: sans-serif; margin: 2em auto; max-width: 60em; color: #222; }
+table { border-collapse: collapse; margin: 1em 0; }
+th, td { padding: 0.3em 0.8em; border-bottom: 1px solid #ddd; text-align: right; }
+th:first-child, td:first-child { text-align: left; }
+td.total { font-weight: bold; }
+td.bad { color: #c62828; }
+td.good { color: #2e7d32; }
+dt { font-weight: bold; float: left; clear: left; width: 7em; }
+dd { margin-left: 8em; }
+svg { display: block; margin: 1em 0; max-width: 100%; height: auto; }
+pre { background: #f6f8fa; padding: 1em; overflow-x: auto; }
+</style>`
+
+var htmlTemplate = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>Benchmark run {{.RunID}}</title>
+` + htmlStyle + `
+</head>
+<body>
+<h1>Benchmark run {{.RunID}}</h1>
+{{with .Notes}}<p>{{.}}</p>
+{{end}}<dl>
+<dt>Target</dt><dd><code>{{.Target}}</code></dd>
+<dt>Load</dt><dd>{{.Load}}</dd>
+<dt>Measured</dt><dd>{{.Measured}}</dd>
+<dt>Client</dt><dd>{{.Client}}</dd>
+<dt>Build</dt><dd>{{.Build}}</dd>
+{{with .Labels}}<dt>Labels</dt><dd>{{range $name, $value := .}}<code>{{$name}}={{$value}}</code> {{end}}</dd>
+{{end}}</dl>
+
+<h2>Results (latencies in {{.Unit}})</h2>
+<table>
+<tr>{{range .Header}}<th>{{.}}</th>{{end}}</tr>
+{{range .Rows}}<tr>{{range .}}<td{{with .Class}} class="{{.}}"{{end}}>{{.Text}}</td>{{end}}</tr>
+{{end}}</table>
+
+{{if .Charts}}<h2>Charts</h2>
+{{range .Charts}}{{.}}{{end}}{{end}}
+<h2>Configuration</h2>
+<pre>{{.Config}}</pre>
+
+<p><small>Generated {{.GeneratedAt.UTC.Format "2006-01-02 15:04:05 MST"}}</small></p>
+</body>
+</html>
+`))
@@ -0,0 +1,169 @@
+package runner
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"hash/fnv"
+	"log"
+	"sync"
+	"time"
+
+	"kvstore-benchmarker/pkg/kvclient"
+)
+
+// Key prefixes of the secondary-index workload. Its primary records live apart
+// from the plain Get/Put/Delete keys so that plain writes never overwrite them.
+var (
+	indexRecordPrefix = []byte("rec/")
+	indexEntryPrefix  = []byte("idx/")
+)
+
+// indexStampSize is the size of the write timestamp heading index records and
+// entries
+const indexStampSize = 8
+
+// indexTracker counts secondary-index queries and the stale reads among them,
+// where the index entry was visible before the primary record it points to
+type indexTracker struct {
+	mu       sync.Mutex
+	queries  int64
+	misses   int64 // No index entry yet
+	stale    int64
+	totalLag time.Duration
+	maxLag   time.Duration
+}
+
+// observe records a query; lag is the age of the index entry when its primary
+// record was found missing or older, 0 for a consistent read
+func (t *indexTracker) observe(found, stale bool, lag time.Duration) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.queries++
+	if !found {
+		t.misses++
+		return
+	}
+	if stale {
+		t.stale++
+		t.totalLag += lag
+		if lag > t.maxLag {
+			t.maxLag = lag
+		}
+	}
+}
+
+// indexAttribute derives the indexed attribute of a primary key
+func indexAttribute(key []byte) []byte {
+	h := fnv.New64a()
+	h.Write(key)
+	return h.Sum(nil)
+}
+
+// indexKeys returns the primary record key and the index entry key of a key
+func indexKeys(key []byte) (record, entry []byte) {
+	record = append(append([]byte{}, indexRecordPrefix...), key...)
+	entry = append(append([]byte{}, indexEntryPrefix...), indexAttribute(key)...)
+	return record, entry
+}
+
+// stamped prefixes data with a write timestamp
+func stamped(at time.Time, data []byte) []byte {
+	buf := make([]byte, indexStampSize, indexStampSize+len(data))
+	binary.BigEndian.PutUint64(buf, uint64(at.UnixNano()))
+	return append(buf, data...)
+}
+
+// unstamp splits a stamped value into its write timestamp and data
+func unstamp(value []byte) (time.Time, []byte, bool) {
+	if len(value) < indexStampSize {
+		return time.Time{}, nil, false
+	}
+	return time.Unix(0, int64(binary.BigEndian.Uint64(value))), value[indexStampSize:], true
+}
+
+// indexPut writes a primary record and then its index entry, both stamped with
+// the same write time, and returns the payload bytes sent
+func (r *BenchmarkRunner) indexPut(ctx context.Context, client *kvclient.Client, key []byte) (int64, error) {
+	value, err := GenerateValue(r.config.ValueSize)
+	if err != nil {
+		return 0, err
+	}
+	record, entry := indexKeys(key)
+	now := time.Now()
+
+	recordValue := stamped(now, value)
+	if _, err := client.Put(ctx, record, recordValue); err != nil {
+		return 0, fmt.Errorf("failed to write primary record: %w", err)
+	}
+	entryValue := stamped(now, record)
+	if _, err := client.Put(ctx, entry, entryValue); err != nil {
+		return int64(len(record) + len(recordValue)), fmt.Errorf("failed to write index entry: %w", err)
+	}
+	return int64(len(record) + len(recordValue) + len(entry) + len(entryValue)), nil
+}
+
+// indexGet looks a record up by its indexed attribute: it reads the index
+// entry, then the primary record it points to. A primary record that is
+// missing or older than the entry is a stale read, tracked unless warming up.
+// It returns the payload bytes sent and received.
+func (r *BenchmarkRunner) indexGet(ctx context.Context, client *kvclient.Client, key []byte, isWarmup bool) (int64, int64, error) {
+	_, entry := indexKeys(key)
+	sent := int64(len(entry))
+
+	resp, err := client.Get(ctx, entry)
+	if err != nil {
+		return sent, 0, fmt.Errorf("failed to read index entry: %w", err)
+	}
+	received := int64(len(resp.Value))
+	indexedAt, record, ok := unstamp(resp.Value)
+	if !resp.Found || !ok {
+		if !isWarmup {
+			r.index.observe(false, false, 0)
+		}
+		return sent, received, nil
+	}
+
+	sent += int64(len(record))
+	resp, err = client.Get(ctx, record)
+	if err != nil {
+		return sent, received, fmt.Errorf("failed to read primary record: %w", err)
+	}
+	received += int64(len(resp.Value))
+	writtenAt, _, ok := unstamp(resp.Value)
+	stale := !resp.Found || !ok || writtenAt.Before(indexedAt)
+	if !isWarmup {
+		var lag time.Duration
+		if stale {
+			lag = time.Since(indexedAt)
+		}
+		r.index.observe(true, stale, lag)
+	}
+	return sent, received, nil
+}
+
+// printIndex reports the consistency of secondary-index queries
+func (r *BenchmarkRunner) printIndex() {
+	if r.config.IndexReadRatio == 0 {
+		return
+	}
+	t := r.index
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	hits := t.queries - t.misses
+	if hits == 0 {
+		log.Printf("Index Consistency: %d queries, no index entries found", t.queries)
+		return
+	}
+	line := fmt.Sprintf("Index Consistency: %d queries, %d misses, %d stale (%.2f%%)",
+		t.queries, t.misses, t.stale, float64(t.stale)/float64(hits)*100)
+	if t.stale > 0 {
+		unit := r.unit()
+		avg := float64(t.totalLag) / float64(t.stale) / float64(time.Millisecond)
+		max := float64(t.maxLag) / float64(time.Millisecond)
+		line += fmt.Sprintf(", lag avg %s max %s", unit.Display(avg), unit.Display(max))
+	}
+	log.Print(line)
+}
@@ -0,0 +1,37 @@
+package runner
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// handleInterrupts handles SIGINT and SIGTERM for the whole run: it restores
+// the terminal of --tui, posts the notification of --notify-url, then lets
+// the signal take its course. The returned function stops handling them.
+func (r *BenchmarkRunner) handleInterrupts() func() {
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, os.Interrupt, syscall.SIGTERM)
+	done := make(chan struct{})
+	go func() {
+		select {
+		case <-done:
+		case sig := <-sigs:
+			if d := r.shown.Load(); d != nil {
+				d.close()
+			}
+			if r.notifyURL != "" {
+				r.notify(fmt.Errorf("interrupted by %v", sig))
+			}
+			signal.Stop(sigs)
+			if p, err := os.FindProcess(os.Getpid()); err != nil || p.Signal(sig) != nil {
+				os.Exit(1)
+			}
+		}
+	}()
+	return func() {
+		signal.Stop(sigs)
+		close(done)
+	}
+}
@@ -0,0 +1,43 @@
+package runner
+
+import (
+	"context"
+	"math/rand"
+	"time"
+)
+
+// jitter returns a random duration in [0, max)
+func jitter(max time.Duration) time.Duration {
+	if max <= 0 {
+		return 0
+	}
+	return time.Duration(rand.Int63n(int64(max)))
+}
+
+// opContext returns the context of a single operation, bounded by the
+// configured timeout plus a random jitter so that deadlines of concurrent
+// operations do not expire in lockstep
+func (r *BenchmarkRunner) opContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	if r.config.OpTimeout <= 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, r.config.OpTimeout+jitter(r.config.OpTimeoutJitter))
+}
+
+// waitStartJitter delays a worker's first operation by a random offset, and
+// returns false if ctx was cancelled meanwhile
+func waitStartJitter(ctx context.Context, max time.Duration) bool {
+	delay := jitter(max)
+	if delay == 0 {
+		return true
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
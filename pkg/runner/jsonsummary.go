@@ -0,0 +1,111 @@
+package runner
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"kvstore-benchmarker/pkg/collector"
+	"kvstore-benchmarker/pkg/results"
+)
+
+// newResultStats converts statistics to the results schema, labelling their
+// latencies at the collector's percentiles
+func newResultStats(s collector.Stats, percentiles []float64) results.Stats {
+	stats := results.Stats{
+		Method:         s.Method,
+		Ops:            s.Count,
+		Errors:         s.ErrorCount,
+		ErrorRatePct:   s.ErrorRate,
+		ErrorCodes:     s.ErrorCodes,
+		Throttled:      s.Throttled,
+		ThrottleRate:   s.ThrottleRate(),
+		RetryAfterAvg:  s.RetryAfterAvg,
+		RetryAfterMax:  s.RetryAfterMax,
+		AvgMs:          s.AvgLatency,
+		MinMs:          s.MinLatency,
+		MaxMs:          s.MaxLatency,
+		PercentilesMs:  make(map[string]float64, len(percentiles)),
+		TrimmedMeanMs:  s.TrimmedMean,
+		MADMs:          s.MAD,
+		ErrorAvgMs:     s.ErrorAvgLatency,
+		ErrorP99Ms:     s.ErrorP99Latency,
+		ErrorMaxMs:     s.ErrorMaxLatency,
+		BytesSent:      s.BytesSent,
+		BytesRecv:      s.BytesRecv,
+		Overflow:       s.Overflow,
+		SLOThresholdMs: s.SLOThreshold,
+		SLOViolations:  s.SLOViolations,
+	}
+	for i, p := range percentiles {
+		stats.PercentilesMs[percentileKey(p)] = s.Percentile(i)
+	}
+	return stats
+}
+
+// percentileKey names a percentile in JSON output, e.g. "p99_9"
+func percentileKey(p float64) string {
+	return strings.ReplaceAll(strings.ToLower(collector.PercentileLabel(p)), ".", "_")
+}
+
+// jsonSummary collects the machine-readable summary of the run
+func (r *BenchmarkRunner) jsonSummary() (*results.Summary, error) {
+	report := r.report()
+	percentiles := r.collector.Percentiles()
+	config, err := json.Marshal(r.config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode configuration: %w", err)
+	}
+
+	summary := &results.Summary{
+		SchemaVersion: results.SchemaVersion,
+		RunID:         report.RunID,
+		Labels:        report.Labels,
+		Notes:         report.Notes,
+		Start:         report.Start,
+		End:           report.End,
+		DurationS:     report.Duration.Seconds(),
+		ThroughputOps: report.Throughput,
+		SLOFailed:     r.sloFailures(),
+		Methods:       make([]results.Stats, 0, len(report.Methods)),
+		Aggregated:    newResultStats(report.Aggregated, percentiles),
+		Config:        config,
+		Build:         report.Build,
+		Client:        report.Client,
+	}
+	summary.Aggregated.Dropped = r.collector.MeasuredDropped()
+	if _, pct := r.retries.adherence(); pct >= 0 {
+		summary.RetryAfterAdherence = &pct
+	}
+	dropped := r.collector.DroppedByMethod()
+	for _, stat := range report.Methods {
+		stats := newResultStats(stat, percentiles)
+		stats.Dropped = dropped[stat.Method]
+		summary.Methods = append(summary.Methods, stats)
+	}
+	return summary, nil
+}
+
+// writeJSONSummary writes the machine-readable summary as indented JSON to
+// path, or stdout if path is "-"
+func (r *BenchmarkRunner) writeJSONSummary(path string) error {
+	summary, err := r.jsonSummary()
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(summary, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode JSON summary: %w", err)
+	}
+	data = append(data, '\n')
+
+	if path == "-" {
+		_, err = os.Stdout.Write(data)
+		return err
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write JSON summary: %w", err)
+	}
+	return nil
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"math/big"
 	"sync"
+	"sync/atomic"
 )
 
 // KeyGenerator generates keys and values for benchmarking
@@ -13,16 +14,29 @@ type KeyGenerator struct {
 	keys     [][]byte
 	mu       sync.RWMutex
 	keyIndex int
+
+	// Layout of the key space, recorded in saved generator state
+	encoding string
+	tenants  int
+
+	// Keys currently holding a written value, tracked only while generator
+	// state is saved, and keys handed out so far
+	writtenMu sync.Mutex
+	written   []bool
+	generated atomic.Int64
 }
 
-// NewKeyGenerator creates a new key generator with pre-generated keys
-func NewKeyGenerator(keySpace int) (*KeyGenerator, error) {
-	keys := make([][]byte, keySpace)
+// NewKeyGenerator creates a new key generator with pre-generated keys in the
+// given encoding; tenants is only used by composite keys
+func NewKeyGenerator(keySpace int, encoding string, tenants int) (*KeyGenerator, error) {
+	encoder, err := newKeyEncoder(encoding, keySpace, tenants)
+	if err != nil {
+		return nil, err
+	}
 
+	keys := make([][]byte, keySpace)
 	for i := 0; i < keySpace; i++ {
-		// Generate 8-16 byte random keys
-		keyLen := 8 + (i % 9) // Varies between 8-16 bytes
-		key, err := generateRandomBytes(keyLen)
+		key, err := encoder.encode(i)
 		if err != nil {
 			return nil, fmt.Errorf("failed to generate key %d: %w", i, err)
 		}
@@ -32,6 +46,9 @@ func NewKeyGenerator(keySpace int) (*KeyGenerator, error) {
 	return &KeyGenerator{
 		keys:     keys,
 		keyIndex: 0,
+		encoding: encoding,
+		tenants:  tenants,
+		written:  make([]bool, keySpace),
 	}, nil
 }
 
@@ -47,6 +64,11 @@ func (kg *KeyGenerator) GetNextKey() []byte {
 
 // GetRandomKey returns a random key from the pool
 func (kg *KeyGenerator) GetRandomKey() []byte {
+	return kg.Key(kg.GetRandomKeyIndex())
+}
+
+// GetRandomKeyIndex returns the index of a random key from the pool
+func (kg *KeyGenerator) GetRandomKeyIndex() int {
 	kg.mu.RLock()
 	defer kg.mu.RUnlock()
 
@@ -57,7 +79,37 @@ func (kg *KeyGenerator) GetRandomKey() []byte {
 		n = big.NewInt(int64(kg.keyIndex))
 	}
 
-	return kg.keys[n.Int64()]
+	kg.generated.Add(1)
+	return int(n.Int64())
+}
+
+// Key returns the key at an index of the pool
+func (kg *KeyGenerator) Key(i int) []byte {
+	kg.mu.RLock()
+	defer kg.mu.RUnlock()
+	return kg.keys[i]
+}
+
+// MarkWritten records whether the key at an index currently holds a value
+// written by the benchmark
+func (kg *KeyGenerator) MarkWritten(i int, written bool) {
+	kg.writtenMu.Lock()
+	defer kg.writtenMu.Unlock()
+	kg.written[i] = written
+}
+
+// Written returns the number of keys currently holding a written value
+func (kg *KeyGenerator) Written() int {
+	kg.writtenMu.Lock()
+	defer kg.writtenMu.Unlock()
+
+	n := 0
+	for _, w := range kg.written {
+		if w {
+			n++
+		}
+	}
+	return n
 }
 
 // GenerateValue generates a random value of the specified size
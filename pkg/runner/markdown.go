@@ -0,0 +1,46 @@
+package runner
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// writeMarkdownReport writes the final results as Markdown, ready to paste
+// into PR descriptions and wiki pages, to path or stdout if path is "-"
+func (r *BenchmarkRunner) writeMarkdownReport(path string) error {
+	report := r.report()
+	cfg := r.config
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "## Benchmark run %s\n\n", report.RunID)
+	if report.Notes != "" {
+		fmt.Fprintf(&b, "%s\n\n", report.Notes)
+	}
+	fmt.Fprintf(&b, "- **Target:** `%s`\n", cfg.TargetAddress)
+	fmt.Fprintf(&b, "- **Load:** %d connections, %d workers, %d%% reads, %d%% writes, %d%% deletes\n",
+		cfg.NumConnections, cfg.NumWorkers, cfg.ReadRatio, cfg.WriteRatio, cfg.DeleteRatio)
+	fmt.Fprintf(&b, "- **Measured:** %v from %s, %.0f ops/sec\n",
+		report.Duration.Round(time.Millisecond), report.Start.UTC().Format(time.RFC3339), report.Throughput)
+	if labels := r.collector.Labels(); len(labels) > 0 {
+		pairs := make([]string, len(labels))
+		for i, label := range labels {
+			pairs[i] = fmt.Sprintf("`%s=%s`", label.Name, label.Value)
+		}
+		fmt.Fprintf(&b, "- **Labels:** %s\n", strings.Join(pairs, ", "))
+	}
+
+	table, _ := r.statsTable(r.collector.GetStats(), report.Aggregated)
+	fmt.Fprintf(&b, "\n### Results (latencies in %s)\n\n", report.Unit)
+	table.renderMarkdown(&b)
+
+	if path == "-" {
+		_, err := os.Stdout.WriteString(b.String())
+		return err
+	}
+	if err := os.WriteFile(path, []byte(b.String()), 0o644); err != nil {
+		return fmt.Errorf("failed to write Markdown report: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,174 @@
+package runner
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"kvstore-benchmarker/pkg/collector"
+	"kvstore-benchmarker/pkg/config"
+	"kvstore-benchmarker/pkg/results"
+)
+
+// notifyTimeout bounds how long posting the notification of a run may take
+const notifyTimeout = 10 * time.Second
+
+// readNotifyURL reads the webhook URL of --notify-url-file, or returns ""
+// without one
+func readNotifyURL(cfg *config.BenchmarkConfig) (string, error) {
+	if cfg.NotifyURLFile == "" {
+		return "", nil
+	}
+	data, err := os.ReadFile(cfg.NotifyURLFile)
+	if err != nil {
+		return "", fmt.Errorf("failed to read notification webhook URL: %w", err)
+	}
+	webhook := strings.TrimSpace(string(data))
+	if u, err := url.Parse(webhook); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return "", fmt.Errorf("%s does not hold an http(s) webhook URL", cfg.NotifyURLFile)
+	}
+	return webhook, nil
+}
+
+// notify posts the outcome of the run, given the error Run returns, to the
+// webhook once. Failures are logged and do not change the outcome.
+func (r *BenchmarkRunner) notify(runErr error) {
+	if r.notifyURL == "" {
+		return
+	}
+	r.notifyOnce.Do(func() {
+		if err := r.postNotification(runErr); err != nil {
+			log.Printf("Warning: failed to post notification: %v", err)
+		}
+	})
+}
+
+// postNotification posts the notification in the --notify-format
+func (r *BenchmarkRunner) postNotification(runErr error) error {
+	n := r.notification(runErr)
+	var body any = n
+	if r.config.NotifyFormat == "slack" {
+		body = map[string]string{"text": r.slackMessage(n)}
+	}
+	data, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+
+	client := &http.Client{Timeout: notifyTimeout}
+	resp, err := client.Post(r.notifyURL, "application/json", bytes.NewReader(data))
+	if err != nil {
+		// The URL holds the webhook's credentials, keep it out of logs
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("webhook returned %s: %s", resp.Status, bytes.TrimSpace(msg))
+	}
+	return nil
+}
+
+// notification summarizes the outcome of the run. It only reads state that
+// is safe to read while the run goes on, since an interrupt can notify at any
+// time.
+func (r *BenchmarkRunner) notification(runErr error) results.Notification {
+	aggregated := r.collector.GetAggregatedStats()
+	duration := r.collector.Measured()
+	labels := make(map[string]string)
+	for _, label := range r.collector.Labels() {
+		labels[label.Name] = label.Value
+	}
+	r.outcomeMu.Lock()
+	sloTotal, sloFailed, regressions := len(r.sloResults), r.sloFailures(), r.regressionFailures()
+	r.outcomeMu.Unlock()
+
+	n := results.Notification{
+		SchemaVersion: results.SchemaVersion,
+		RunID:         r.config.RunID,
+		Status:        "passed",
+		Target:        r.config.TargetAddress,
+		Labels:        labels,
+		Notes:         r.config.Notes,
+		Start:         r.startTime,
+		End:           time.Now(),
+		DurationS:     duration.Seconds(),
+		SLOTotal:      sloTotal,
+		SLOFailed:     sloFailed,
+		Regressions:   regressions,
+		Aggregated:    newResultStats(aggregated, r.collector.Percentiles()),
+	}
+	if seconds := duration.Seconds(); seconds > 0 {
+		n.ThroughputOps = float64(aggregated.Count) / seconds
+	}
+	n.Aggregated.Dropped = r.collector.MeasuredDropped()
+	switch {
+	case runErr == nil:
+	case errors.Is(runErr, ErrSLOViolated), errors.Is(runErr, ErrRegressed), errors.Is(runErr, ErrTargetNotMet):
+		n.Status = "failed"
+		n.Error = runErr.Error()
+	default:
+		n.Status = "aborted"
+		n.Error = runErr.Error()
+	}
+	return n
+}
+
+// slackMessage renders a notification as the mrkdwn text of a Slack message,
+// e.g. ":white_check_mark: *Benchmark 20240115-103000 passed* ..."
+func (r *BenchmarkRunner) slackMessage(n results.Notification) string {
+	icon := map[string]string{"passed": ":white_check_mark:", "failed": ":x:", "aborted": ":warning:"}[n.Status]
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s *Benchmark %s %s* against `%s`", icon, n.RunID, n.Status, n.Target)
+	if len(n.Labels) > 0 {
+		names := make([]string, 0, len(n.Labels))
+		for name := range n.Labels {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		labels := make([]string, len(names))
+		for i, name := range names {
+			labels[i] = name + "=" + n.Labels[name]
+		}
+		fmt.Fprintf(&b, " (%s)", strings.Join(labels, ", "))
+	}
+	if n.Error != "" {
+		fmt.Fprintf(&b, "\n%s", n.Error)
+	}
+	if n.Aggregated.Ops == 0 {
+		return b.String()
+	}
+
+	// P99, or the highest percentile when P99 is not configured
+	percentiles := r.collector.Percentiles()
+	index := slices.Index(percentiles, 99)
+	if index < 0 {
+		index = len(percentiles) - 1
+	}
+	tail := percentiles[index]
+	fmt.Fprintf(&b, "\n%.0f ops/sec over %v · %s %s · errors %.2f%%",
+		n.ThroughputOps, time.Duration(n.DurationS*float64(time.Second)).Round(time.Second),
+		collector.PercentileLabel(tail), r.unit().Display(n.Aggregated.PercentilesMs[percentileKey(tail)]),
+		n.Aggregated.ErrorRatePct)
+	if n.SLOTotal > 0 {
+		fmt.Fprintf(&b, " · SLOs %d/%d passed", n.SLOTotal-n.SLOFailed, n.SLOTotal)
+	}
+	if n.Regressions > 0 {
+		fmt.Fprintf(&b, " · %d regressions", n.Regressions)
+	}
+	return b.String()
+}
@@ -0,0 +1,146 @@
+package runner
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// pauseGate blocks workers while the benchmark is paused and tracks paused time
+type pauseGate struct {
+	mu       sync.Mutex
+	paused   bool
+	resumed  chan struct{} // closed when the current pause ends
+	pausedAt time.Time
+	total    time.Duration // accumulated time of completed pauses
+}
+
+// newPauseGate creates an unpaused gate
+func newPauseGate() *pauseGate {
+	return &pauseGate{}
+}
+
+// Pause pauses the benchmark. It returns false if it was already paused.
+func (g *pauseGate) Pause() bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.paused {
+		return false
+	}
+	g.paused = true
+	g.resumed = make(chan struct{})
+	g.pausedAt = time.Now()
+	return true
+}
+
+// Resume resumes the benchmark. It returns false if it was not paused.
+func (g *pauseGate) Resume() bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if !g.paused {
+		return false
+	}
+	g.paused = false
+	g.total += time.Since(g.pausedAt)
+	close(g.resumed)
+	return true
+}
+
+// Toggle pauses a running benchmark or resumes a paused one and returns the new state
+func (g *pauseGate) Toggle() (paused bool) {
+	if g.Pause() {
+		return true
+	}
+	g.Resume()
+	return false
+}
+
+// IsPaused reports whether the benchmark is currently paused
+func (g *pauseGate) IsPaused() bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.paused
+}
+
+// PausedTotal returns the total paused time, including an ongoing pause
+func (g *pauseGate) PausedTotal() time.Duration {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.paused {
+		return g.total + time.Since(g.pausedAt)
+	}
+	return g.total
+}
+
+// wait blocks while the benchmark is paused. It returns false if ctx is done first.
+func (g *pauseGate) wait(ctx context.Context) bool {
+	g.mu.Lock()
+	paused, resumed := g.paused, g.resumed
+	g.mu.Unlock()
+
+	if !paused {
+		return true
+	}
+
+	select {
+	case <-ctx.Done():
+		return false
+	case <-resumed:
+		return true
+	}
+}
+
+// phaseClock measures the active (unpaused) time of a benchmark phase
+type phaseClock struct {
+	gate          *pauseGate
+	start         time.Time
+	pausedAtStart time.Duration
+}
+
+// newPhaseClock starts a phase clock at the current time
+func newPhaseClock(gate *pauseGate) *phaseClock {
+	return &phaseClock{
+		gate:          gate,
+		start:         time.Now(),
+		pausedAtStart: gate.PausedTotal(),
+	}
+}
+
+// Elapsed returns the active time since the phase started
+func (c *phaseClock) Elapsed() time.Duration {
+	return time.Since(c.start) - (c.gate.PausedTotal() - c.pausedAtStart)
+}
+
+// advance moves the phase position forward by d, to continue a resumed phase
+func (c *phaseClock) advance(d time.Duration) {
+	c.start = c.start.Add(-d)
+}
+
+// runPhaseTimer cancels the phase once it has been active for duration,
+// suspending the countdown while the benchmark is paused
+func runPhaseTimer(ctx context.Context, cancel context.CancelFunc, clock *phaseClock, duration time.Duration) {
+	defer cancel()
+
+	for {
+		remaining := duration - clock.Elapsed()
+		if remaining <= 0 {
+			return
+		}
+
+		timer := time.NewTimer(remaining)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		// Wait out an ongoing pause before re-checking the remaining time
+		if !clock.gate.wait(ctx) {
+			return
+		}
+	}
+}
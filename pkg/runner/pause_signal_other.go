@@ -0,0 +1,8 @@
+//go:build !unix
+
+package runner
+
+import "context"
+
+// handlePauseSignal is a no-op on platforms without SIGUSR2
+func (r *BenchmarkRunner) handlePauseSignal(ctx context.Context) {}
@@ -0,0 +1,33 @@
+//go:build unix
+
+package runner
+
+import (
+	"context"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// handlePauseSignal toggles pause/resume whenever the process receives SIGUSR2
+func (r *BenchmarkRunner) handlePauseSignal(ctx context.Context) {
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, syscall.SIGUSR2)
+
+	go func() {
+		defer signal.Stop(sigs)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-sigs:
+				if r.gate.Toggle() {
+					log.Printf("Benchmark paused (SIGUSR2)")
+				} else {
+					log.Printf("Benchmark resumed (SIGUSR2)")
+				}
+			}
+		}
+	}()
+}
@@ -0,0 +1,42 @@
+package runner
+
+import (
+	"encoding/json"
+	"log"
+	"os"
+	"time"
+
+	"kvstore-benchmarker/pkg/collector"
+	"kvstore-benchmarker/pkg/results"
+)
+
+// writeProgressJSON writes a progress tick as a JSON line on stdout for
+// wrapper scripts monitoring the run. total is the measured phase so far,
+// stats the window of the last report interval.
+func (r *BenchmarkRunner) writeProgressJSON(total, stats collector.Stats, window time.Duration) {
+	elapsed := window.Seconds()
+	progress := results.Progress{
+		SchemaVersion: results.SchemaVersion,
+		RunID:         r.config.RunID,
+		Time:          time.Now(),
+		IntervalS:     elapsed,
+		TotalOps:      total.Count,
+		OpsPerSec:     float64(stats.Count) / elapsed,
+		AdmittedOps:   float64(stats.Admitted()) / elapsed,
+		ReadMBps:      collector.MBPerSec(stats.BytesRecv, elapsed),
+		WriteMBps:     collector.MBPerSec(stats.BytesSent, elapsed),
+		Aggregated:    newResultStats(stats, r.collector.Percentiles()),
+	}
+	if conformance := stats.SLOConformance(); conformance >= 0 {
+		progress.SLOMetPct = &conformance
+	}
+
+	data, err := json.Marshal(progress)
+	if err != nil {
+		log.Printf("Warning: failed to encode progress: %v", err)
+		return
+	}
+	if _, err := os.Stdout.Write(append(data, '\n')); err != nil {
+		log.Printf("Warning: failed to write progress: %v", err)
+	}
+}
@@ -0,0 +1,40 @@
+package runner
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// rampUp starts workers one at a time, evenly spaced over the ramp window,
+// until n are running. The end of the measured phase's ramp is marked in the
+// collector output and recorded for the summary so it can be excluded from
+// analysis.
+func (r *BenchmarkRunner) rampUp(ctx context.Context, clock *phaseClock, group *workerGroup, n int, window time.Duration, isWarmup bool) {
+	log.Printf("Ramping up %d workers over %v", n, window)
+
+	step := window / time.Duration(n-1)
+	for i := 1; i <= n; i++ {
+		for {
+			wait := time.Duration(i-1)*step - clock.Elapsed()
+			if wait <= 0 {
+				break
+			}
+			timer := time.NewTimer(wait)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return
+			case <-timer.C:
+			}
+		}
+		r.scaleWorkers(group, i)
+	}
+
+	elapsed := clock.Elapsed()
+	log.Printf("Ramp-up complete: %d workers running after %v", n, elapsed.Round(time.Millisecond))
+	if !isWarmup {
+		r.rampEnd = elapsed
+		r.collector.MarkRampEnd(time.Now())
+	}
+}
@@ -0,0 +1,97 @@
+package runner
+
+import (
+	"context"
+	"math/rand"
+
+	"golang.org/x/time/rate"
+
+	"kvstore-benchmarker/pkg/config"
+)
+
+// operations lists the supported operations in selection order
+var operations = []string{"Get", "Put", "Delete", "IndexPut", "IndexGet"}
+
+// newOperationLimiters creates a limiter for every operation with a configured rate limit
+func newOperationLimiters(cfg *config.BenchmarkConfig) map[string]*rate.Limiter {
+	limits := map[string]int{
+		"Get":    cfg.GetRateLimit,
+		"Put":    cfg.PutRateLimit,
+		"Delete": cfg.DeleteRateLimit,
+	}
+
+	limiters := make(map[string]*rate.Limiter)
+	for op, limit := range limits {
+		if limit <= 0 {
+			continue
+		}
+		// Allow bursts of roughly 10ms worth of operations
+		burst := limit / 100
+		if burst < 1 {
+			burst = 1
+		}
+		limiters[op] = rate.NewLimiter(rate.Limit(limit), burst)
+	}
+	return limiters
+}
+
+// admitOperation applies per-operation rate limits to the selected operation.
+// If the operation is over its limit the worker switches to another operation
+// that still has capacity, so capped operations don't throttle uncapped ones.
+// If no operation has capacity it waits for the selected operation's limiter.
+func (r *BenchmarkRunner) admitOperation(ctx context.Context, op string) (string, error) {
+	limiter, limited := r.opLimiters[op]
+	if !limited || limiter.Allow() {
+		return op, nil
+	}
+
+	// Pick a weighted alternative among operations with remaining capacity
+	var candidates []string
+	var weights []int
+	total := 0
+	for _, alt := range operations {
+		weight := r.operationRatio(alt)
+		if alt == op || weight == 0 {
+			continue
+		}
+		if l, ok := r.opLimiters[alt]; ok && l.Tokens() < 1 {
+			continue
+		}
+		candidates = append(candidates, alt)
+		weights = append(weights, weight)
+		total += weight
+	}
+
+	if total > 0 {
+		n := rand.Intn(total)
+		for i, alt := range candidates {
+			if n < weights[i] {
+				if l, ok := r.opLimiters[alt]; !ok || l.Allow() {
+					return alt, nil
+				}
+				break
+			}
+			n -= weights[i]
+		}
+	}
+
+	return op, limiter.Wait(ctx)
+}
+
+// operationRatio returns the configured percentage for an operation
+func (r *BenchmarkRunner) operationRatio(op string) int {
+	switch op {
+	case "Get":
+		return r.config.ReadRatio
+	case "Put":
+		return r.config.WriteRatio
+	case "Delete":
+		return r.config.DeleteRatio
+	case "IndexPut":
+		return r.config.IndexWriteRatio
+	case "IndexGet":
+		return r.config.IndexReadRatio
+	default:
+		return 0
+	}
+}
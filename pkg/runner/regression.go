@@ -0,0 +1,133 @@
+package runner
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log"
+
+	"kvstore-benchmarker/pkg/collector"
+	"kvstore-benchmarker/pkg/compare"
+)
+
+// ErrRegressed is returned by Run when the benchmark completed but its
+// results regressed beyond a --fail-on threshold versus the --baseline
+var ErrRegressed = errors.New("results regressed versus the baseline")
+
+// comparisonThreshold is the change in percent beyond which the comparison
+// table highlights regressions and improvements
+const comparisonThreshold = 5
+
+// loadBaseline reads the --baseline results before the run, so a missing or
+// invalid file does not waste it
+func (r *BenchmarkRunner) loadBaseline() error {
+	if r.config.Baseline == "" {
+		return nil
+	}
+	baseline, err := compare.Load(r.config.Baseline)
+	if err != nil {
+		return fmt.Errorf("failed to load baseline: %w", err)
+	}
+	r.baseline = baseline
+	return nil
+}
+
+// results returns the final statistics of the run for comparison with the
+// baseline, with throughput and latencies as in the JSON summary
+func (r *BenchmarkRunner) results() *compare.Results {
+	report := r.report()
+	percentiles := r.collector.Percentiles()
+	results := &compare.Results{Path: "this run", RunID: report.RunID, Notes: report.Notes, Build: &report.Build, Client: &report.Client}
+	for _, p := range percentiles {
+		results.Percentiles = append(results.Percentiles, percentileKey(p))
+	}
+
+	seconds := report.Duration.Seconds()
+	method := func(s collector.Stats) *compare.Method {
+		m := &compare.Method{Name: s.Method, ErrorRate: s.ErrorRate, Latencies: make(map[string]float64, len(percentiles))}
+		if seconds > 0 {
+			m.Throughput = float64(s.Successes()) / seconds
+		}
+		for i, p := range percentiles {
+			m.Latencies[percentileKey(p)] = s.Percentile(i)
+		}
+		return m
+	}
+	for _, s := range report.Methods {
+		results.Methods = append(results.Methods, method(s))
+	}
+	if report.Aggregated.Count > 0 {
+		results.Methods = append(results.Methods, method(report.Aggregated))
+	}
+	return results
+}
+
+// checkRegressions checks the --fail-on thresholds against the baseline
+func (r *BenchmarkRunner) checkRegressions() {
+	var regressions []compare.Result
+	if r.baseline != nil {
+		current := r.results()
+		for _, t := range r.thresholds {
+			regressions = append(regressions, t.Check(r.baseline, current))
+		}
+	}
+	r.outcomeMu.Lock()
+	r.regressions = regressions
+	r.outcomeMu.Unlock()
+}
+
+// regressionFailures returns the number of failed regression thresholds
+func (r *BenchmarkRunner) regressionFailures() int {
+	failed := 0
+	for _, result := range r.regressions {
+		if result.Failed {
+			failed++
+		}
+	}
+	return failed
+}
+
+// printBaseline prints the outcome of every --fail-on threshold, or the full
+// comparison with the baseline without thresholds
+func (r *BenchmarkRunner) printBaseline(out io.Writer, color bool) {
+	if r.baseline == nil {
+		return
+	}
+	if len(r.thresholds) == 0 {
+		fmt.Fprintf(out, "=== BASELINE COMPARISON ===\n\n")
+		WriteComparison(out, r.baseline, r.results(), comparisonThreshold, r.config.Color, r.unit())
+		fmt.Fprintln(out)
+		return
+	}
+
+	unit := r.unit()
+	table := newTextTable("Threshold", "Baseline", "Current", "Change", "Result")
+	for _, result := range r.regressions {
+		verdict := tableCell{text: "PASS", style: ansiGreen}
+		if result.Failed {
+			verdict = tableCell{text: "FAIL", style: ansiRed}
+		}
+		baseline, current, change := "no data", "no data", "-"
+		if !result.NoData {
+			d := result.Delta
+			baseline = formatMetric(d.Metric, d.Base, unit)
+			current = formatMetric(d.Metric, d.Current, unit)
+			change = formatChange(d.Change)
+		}
+		table.addRow(
+			tableCell{text: result.Threshold.Text},
+			tableCell{text: baseline},
+			tableCell{text: current},
+			tableCell{text: change},
+			verdict,
+		)
+	}
+
+	fmt.Fprintf(out, "=== REGRESSIONS VS. %s ===\n\n", describeResults(r.baseline))
+	table.render(out, color)
+	fmt.Fprintln(out)
+
+	if failed := r.regressionFailures(); failed > 0 {
+		log.Printf("Regression: %d of %d thresholds failed", failed, len(r.regressions))
+	}
+}
@@ -0,0 +1,132 @@
+package runner
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"math"
+	"strings"
+	"time"
+
+	"kvstore-benchmarker/pkg/compare"
+	"kvstore-benchmarker/pkg/config"
+	"kvstore-benchmarker/pkg/latency"
+)
+
+// Repeat runs the benchmark --repeat times, --cool-down apart, and prints
+// the mean with its 95% confidence interval, standard deviation, minimum and
+// maximum of throughput, latency percentiles and error rate across the
+// repetitions. Every repetition is labelled with its number and, like
+// scenario steps, gets the number as suffix of its run ID and output files.
+// Repetitions that fail their SLOs, regression thresholds or rate search
+// still count and the first such error is returned at the end; other errors
+// stop the sequence.
+func Repeat(cfg *config.BenchmarkConfig) error {
+	if cfg.Repeat <= 1 {
+		r, err := NewBenchmarkRunner(cfg)
+		if err != nil {
+			return fmt.Errorf("failed to create benchmark runner: %w", err)
+		}
+		return r.Run()
+	}
+
+	var runs []*compare.Results
+	var failed error
+	for i := 1; i <= cfg.Repeat; i++ {
+		if i > 1 && cfg.CoolDown > 0 {
+			log.Printf("Cooling down for %v", cfg.CoolDown)
+			time.Sleep(cfg.CoolDown)
+		}
+		log.Printf("Repetition %d/%d", i, cfg.Repeat)
+
+		run := cfg.WithSuffix(fmt.Sprintf("-%d", i))
+		labels := []string{fmt.Sprintf("repetition=%d", i)}
+		if run.Labels != "" {
+			labels = append(labels, run.Labels)
+		}
+		run.Labels = strings.Join(labels, ",")
+
+		r, err := NewBenchmarkRunner(run)
+		if err != nil {
+			return fmt.Errorf("failed to create benchmark runner: %w", err)
+		}
+		err = r.Run()
+		switch {
+		case errors.Is(err, ErrSLOViolated) || errors.Is(err, ErrRegressed) || errors.Is(err, ErrTargetNotMet):
+			log.Printf("Repetition %d failed: %v", i, err)
+			if failed == nil {
+				failed = err
+			}
+		case err != nil:
+			return fmt.Errorf("repetition %d: %w", i, err)
+		}
+		runs = append(runs, r.results())
+	}
+
+	out := log.Writer()
+	printRepetitions(out, runs, colorEnabled(cfg.Color, out), latency.Unit(cfg.LatencyUnit))
+	return failed
+}
+
+// spread is the distribution of a statistic across repetitions
+type spread struct {
+	mean, stddev, min, max float64
+}
+
+// spreadOf returns the mean, sample standard deviation, minimum and maximum of values
+func spreadOf(values []float64) spread {
+	s := spread{min: math.Inf(1), max: math.Inf(-1)}
+	for _, v := range values {
+		s.mean += v
+		s.min = min(s.min, v)
+		s.max = max(s.max, v)
+	}
+	s.mean /= float64(len(values))
+	if len(values) > 1 {
+		var squares float64
+		for _, v := range values {
+			squares += (v - s.mean) * (v - s.mean)
+		}
+		s.stddev = math.Sqrt(squares / float64(len(values)-1))
+	}
+	return s
+}
+
+// printRepetitions prints the spread of every statistic of every method
+// across the repetitions, for the methods and percentiles of the first
+func printRepetitions(out io.Writer, runs []*compare.Results, color bool, unit latency.Unit) {
+	if len(runs) == 0 {
+		return
+	}
+
+	table := newTextTable("Method", "Metric", "Mean", "+/-95%", "StdDev", "Min", "Max")
+	for _, m := range runs[0].Methods {
+		for _, metric := range compare.Metrics(runs[0], runs[0]) {
+			var values []float64
+			for _, run := range runs {
+				if method := run.Method(m.Name); method != nil {
+					values = append(values, metric.Value(method))
+				}
+			}
+			s := spreadOf(values)
+			style := ""
+			if m.Name == compare.Aggregated {
+				style = ansiBold
+			}
+			table.addRow(
+				tableCell{text: m.Name, style: style},
+				tableCell{text: metricLabel(metric, unit)},
+				tableCell{text: formatMetric(metric, s.mean, unit)},
+				tableCell{text: formatMetric(metric, confidence95(s, len(values)), unit)},
+				tableCell{text: formatMetric(metric, s.stddev, unit)},
+				tableCell{text: formatMetric(metric, s.min, unit)},
+				tableCell{text: formatMetric(metric, s.max, unit)},
+			)
+		}
+	}
+
+	fmt.Fprintf(out, "\n=== REPEATED RUNS (%d repetitions) ===\n\n", len(runs))
+	table.render(out, color)
+	fmt.Fprintln(out)
+}
@@ -0,0 +1,134 @@
+package runner
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
+
+	"kvstore-benchmarker/pkg/collector"
+	"kvstore-benchmarker/pkg/config"
+	"kvstore-benchmarker/pkg/manifest"
+	"kvstore-benchmarker/pkg/slo"
+)
+
+// Report is the data a --report-template is rendered with
+type Report struct {
+	RunID      string
+	Notes      string
+	Labels     map[string]string // Run labels by name
+	Start      time.Time
+	End        time.Time
+	Duration   time.Duration // Measured time of the run
+	Throughput float64       // Operations per second over the measured time
+	Unit       string        // Latency unit of the latency function
+	Methods    []collector.Stats
+	Aggregated collector.Stats
+	Tagged     []collector.TagStats
+	Breakdown  []collector.BreakdownStats
+	Summary    map[string]string // The --format summary fields by key
+	Config     *config.BenchmarkConfig
+
+	Percentiles []float64       // Reported percentiles, in the order of Stats.Percentile
+	SLOs        []slo.Result    // Outcomes of the SLO assertions
+	Build       manifest.Build  // Benchmarker version and revision
+	Client      manifest.Client // Machine the benchmark ran on
+}
+
+// parseReportTemplate parses the text/template file at path. Besides the
+// standard functions, templates can call latency to render milliseconds in
+// the configured latency unit, percentile to label a percentile ("P99.9")
+// and tsv to join values into a tab-separated line.
+func (r *BenchmarkRunner) parseReportTemplate(path string) (*template.Template, error) {
+	unit := r.unit()
+	tmpl, err := template.New(filepath.Base(path)).Funcs(template.FuncMap{
+		"latency":    unit.Value,
+		"percentile": collector.PercentileLabel,
+		"tsv":        tsvLine,
+	}).ParseFiles(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse report template: %w", err)
+	}
+	return tmpl, nil
+}
+
+// report collects the data of the final report
+func (r *BenchmarkRunner) report() *Report {
+	stats := r.collector.GetStats()
+	methods := make([]string, 0, len(stats))
+	for method, stat := range stats {
+		if stat.Count > 0 {
+			methods = append(methods, method)
+		}
+	}
+	sort.Strings(methods)
+
+	report := &Report{
+		RunID:      r.config.RunID,
+		Notes:      r.config.Notes,
+		Labels:     make(map[string]string),
+		Start:      r.startTime,
+		End:        time.Now(),
+		Duration:   r.collector.Measured(),
+		Unit:       r.unit().Name(),
+		Aggregated: r.collector.GetAggregatedStats(),
+		Tagged:     r.collector.GetTaggedStats(),
+		Breakdown:  r.collector.GetBreakdownStats(),
+		Summary:    make(map[string]string),
+		Config:     r.config,
+
+		Percentiles: r.collector.Percentiles(),
+		SLOs:        r.sloResults,
+		Build:       manifest.CollectBuild(),
+		Client:      manifest.CollectClient(),
+	}
+	for _, method := range methods {
+		report.Methods = append(report.Methods, stats[method])
+	}
+	for _, label := range r.collector.Labels() {
+		report.Labels[label.Name] = label.Value
+	}
+	for _, f := range r.summaryFields() {
+		report.Summary[f.key] = f.value
+	}
+	if seconds := report.Duration.Seconds(); seconds > 0 {
+		report.Throughput = float64(report.Aggregated.Count) / seconds
+	}
+	return report
+}
+
+// writeReport renders the report template to path, or stdout if path is "-"
+func (r *BenchmarkRunner) writeReport(tmpl *template.Template, path string) error {
+	var out io.Writer = os.Stdout
+	if path != "-" {
+		file, err := os.Create(path)
+		if err != nil {
+			return fmt.Errorf("failed to create report: %w", err)
+		}
+		defer file.Close()
+		out = file
+	}
+	if err := tmpl.Execute(out, r.report()); err != nil {
+		return fmt.Errorf("failed to render report: %w", err)
+	}
+	return nil
+}
+
+// tsvLine joins values into a line of tab-separated fields, replacing tabs
+// and line breaks within values by spaces
+func tsvLine(values ...any) string {
+	fields := make([]string, len(values))
+	for i, v := range values {
+		fields[i] = strings.Map(func(c rune) rune {
+			if c == '\t' || c == '\n' || c == '\r' {
+				return ' '
+			}
+			return c
+		}, fmt.Sprint(v))
+	}
+	return strings.Join(fields, "\t")
+}
@@ -2,167 +2,876 @@ package runner
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"io"
 	"log"
 	"math/rand"
+	"os"
+	"sort"
+	"strings"
 	"sync"
+	"sync/atomic"
+	"text/template"
 	"time"
 
+	"golang.org/x/time/rate"
+	"google.golang.org/grpc/keepalive"
+
+	pb "kvstore-benchmarker/internal/proto"
+	"kvstore-benchmarker/pkg/admin"
+	"kvstore-benchmarker/pkg/archive"
 	"kvstore-benchmarker/pkg/collector"
+	"kvstore-benchmarker/pkg/compare"
 	"kvstore-benchmarker/pkg/config"
+	"kvstore-benchmarker/pkg/controller"
+	"kvstore-benchmarker/pkg/grafana"
+	"kvstore-benchmarker/pkg/histstore"
 	"kvstore-benchmarker/pkg/kvclient"
+	"kvstore-benchmarker/pkg/latency"
+	"kvstore-benchmarker/pkg/manifest"
+	"kvstore-benchmarker/pkg/objstore"
+	"kvstore-benchmarker/pkg/slo"
 )
 
 // BenchmarkRunner orchestrates the benchmark execution
 type BenchmarkRunner struct {
-	config    *config.BenchmarkConfig
-	pool      *kvclient.ConnectionPool
-	collector *collector.Collector
-	keyGen    *KeyGenerator
-	ctx       context.Context
-	cancel    context.CancelFunc
-	wg        sync.WaitGroup
-	startTime time.Time
+	config     *config.BenchmarkConfig
+	pool       *kvclient.ConnectionPool
+	collector  *collector.Collector
+	keyGen     *KeyGenerator
+	opLimiters map[string]*rate.Limiter
+	duty       *dutyCycle
+	agents     *controller.Server
+	admin      *admin.Server
+	web        *admin.Server // Read-only web dashboard, nil without
+	events     *eventStream  // Interval statistics streamed by the web dashboard, nil without
+	exporters  []exporter
+	gate       *pauseGate
+	limiter    *rate.Limiter
+	timeline   []TimelineStep
+	ctx        context.Context
+	cancel     context.CancelFunc
+	wg         sync.WaitGroup
+	startTime  time.Time
+	rampEnd    time.Duration
+
+	// Checkpoint and resume state
+	measured    *phaseClock
+	resumeAt    time.Duration
+	priorActive time.Duration
+	gaps        []runGap
+
+	// Named phases and SLO assertions over them
+	phases      []slo.Phase
+	phaseStarts []time.Time
+	assertions  []slo.Assertion
+	sloResults  []slo.Result
+	baseline    *compare.Results    // Results of the --baseline run, nil without
+	thresholds  []compare.Threshold // Regression thresholds versus the baseline
+	regressions []compare.Result
+	outcomeMu   sync.Mutex // Guards writes of sloResults and regressions, read by the interrupt handler
+	search      *rateSearch
+	energy      *energyMeter
+	index       *indexTracker
+	convergence *convergenceProbe
+	dashboard   *dashboard                // Live terminal view of --tui, nil without
+	shown       atomic.Pointer[dashboard] // Dashboard on the screen, for the interrupt handler
+	annotator   *grafana.Annotator        // Grafana annotations of the run's phases, nil without
+	store       objstore.Store            // Bucket results are uploaded to, nil without --upload
+	notifyURL   string                    // Webhook notified when the run ends, empty without
+	notifyOnce  sync.Once                 // Notifies at the end of the run or on a signal, whichever comes first
+	creds       *requestCredentials       // Signer, bearer tokens and metadata file of requests
+	identities  []*kvclient.Identity      // Credentials by tenant, nil without tenant profiles
+	retries     retryTracker              // Adherence to suggested retry delays
+	intervals   *intervalSeries           // Interval statistics for confidence intervals and the HTML report
+	intervalLog *intervalLog              // Interval statistics for the SQLite database, nil without one
+	tagger      *opTagger
+	overhead    map[string]collector.Stats // Calibrated client overhead by method
+}
+
+// clientOptions returns the options of the connections to the target: TLS
+// credentials, unless connections are plaintext, and keepalive pings
+func clientOptions(cfg *config.BenchmarkConfig) (kvclient.Options, error) {
+	opts := kvclient.Options{
+		Keepalive: keepalive.ClientParameters{
+			Time:                cfg.KeepaliveTime,
+			Timeout:             cfg.KeepaliveTimeout,
+			PermitWithoutStream: cfg.KeepalivePermitWithoutStream,
+		},
+	}
+	if cfg.TLS {
+		creds, err := kvclient.TLSCredentials(cfg.TLSCAFile, cfg.TLSServerName)
+		if err != nil {
+			return kvclient.Options{}, err
+		}
+		opts.Credentials = creds
+	}
+	return opts, nil
 }
 
 // NewBenchmarkRunner creates a new benchmark runner
-func NewBenchmarkRunner(cfg *config.BenchmarkConfig) (*BenchmarkRunner, error) {
+func NewBenchmarkRunner(cfg *config.BenchmarkConfig) (_ *BenchmarkRunner, err error) {
+	// Release what was set up so far, in reverse order, if a step fails
+	var cleanup []func()
+	defer func() {
+		if err != nil {
+			for i := len(cleanup) - 1; i >= 0; i-- {
+				cleanup[i]()
+			}
+		}
+	}()
+
 	// Create connection pool
-	pool, err := kvclient.NewConnectionPool(cfg.TargetAddress, cfg.NumConnections)
+	clientOpts, err := clientOptions(cfg)
+	if err != nil {
+		return nil, err
+	}
+	pool, err := kvclient.NewConnectionPool(cfg.TargetAddress, cfg.NumConnections, clientOpts)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create connection pool: %w", err)
 	}
+	cleanup = append(cleanup, func() { pool.Close() })
+
+	// Sign requests and keep bearer tokens and metadata fresh for deployments
+	// that authenticate them
+	creds, err := newRequestCredentials(cfg)
+	if err != nil {
+		return nil, err
+	}
+	cleanup = append(cleanup, creds.stop)
+	creds.apply(pool)
 
-	// Create collector
-	collector, err := collector.NewCollector(cfg.OutputCSV)
+	startTime := time.Now()
+	if cfg.RunID == "" {
+		cfg.RunID = startTime.Format("20060102-150405")
+	}
+
+	// Mark the run's phases on Grafana graphs
+	annotator, err := newAnnotator(cfg)
+	if err != nil {
+		return nil, err
+	}
+	notifyURL, err := readNotifyURL(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	// Create collector; percentiles, latency SLOs and operation tags were checked by config.Validate
+	percentiles, _ := collector.ParsePercentiles(cfg.Percentiles)
+	latencySLOs, _ := collector.ParseLatencySLOs(cfg.LatencySLOs)
+	tagger := newOpTagger(cfg.OpTags, cfg.KeyTenants)
+	labels, _ := collector.ParseLabels(cfg.Labels)
+	var csvRunID string
+	if cfg.CSVAppend {
+		csvRunID = cfg.RunID // Tells apart the runs sharing the CSV
+	}
+	gate := newPauseGate()
+	collector, err := collector.NewCollector(collector.Options{
+		CSVPath:        cfg.OutputCSV,
+		BufferSize:     cfg.ResultsBufferSize,
+		BlockWhenFull:  cfg.ResultsBlocking,
+		LatencyUnit:    latency.Unit(cfg.LatencyUnit),
+		CSVFormat:      csvFormat(cfg),
+		Engine:         cfg.PercentileEngine,
+		CSVIntervals:   cfg.CSVIntervals,
+		Percentiles:    percentiles,
+		LatencySLOs:    latencySLOs,
+		Tagged:         tagger != nil,
+		Labels:         labels,
+		CSVComments:    csvComments(cfg, startTime),
+		CSVAppend:      cfg.CSVAppend,
+		CSVRunID:       csvRunID,
+		AlignIntervals: cfg.AlignIntervals,
+		LatencyMax:     cfg.LatencyMax,
+		TrimPct:        cfg.TrimPct,
+		Paused:         gate.PausedTotal,
+	})
 	if err != nil {
-		pool.Close()
 		return nil, fmt.Errorf("failed to create collector: %w", err)
 	}
+	cleanup = append(cleanup, collector.Close)
 
-	// Create key generator
-	keyGen, err := NewKeyGenerator(cfg.KeySpace)
+	// Create key generator, restoring the one saved by a previous session
+	var keyGen *KeyGenerator
+	if cfg.GeneratorState != "" {
+		keyGen, err = LoadKeyGenerator(cfg.GeneratorState, cfg.KeySpace, cfg.KeyEncoding, cfg.KeyTenants)
+		if err != nil {
+			return nil, fmt.Errorf("failed to restore key generator: %w", err)
+		}
+		if keyGen != nil {
+			log.Printf("Restored generator state from %s (%d of %d keys written)", cfg.GeneratorState, keyGen.Written(), cfg.KeySpace)
+		}
+	}
+	if keyGen == nil {
+		keyGen, err = NewKeyGenerator(cfg.KeySpace, cfg.KeyEncoding, cfg.KeyTenants)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create key generator: %w", err)
+		}
+	}
+
+	// Create binary result archive
+	if cfg.ArchivePath != "" {
+		w, err := archive.Create(cfg.ArchivePath, startTime, cfg.String())
+		if err != nil {
+			return nil, fmt.Errorf("failed to create archive: %w", err)
+		}
+		collector.SetArchive(w)
+	}
+
+	// Start agent controller
+	var agents *controller.Server
+	if cfg.AgentListen != "" {
+		agents = controller.NewServer(cfg, collector)
+		if err := agents.Start(cfg.AgentListen); err != nil {
+			return nil, fmt.Errorf("failed to start agent controller: %w", err)
+		}
+		cleanup = append(cleanup, agents.Stop)
+	}
+
+	// Load load profile timeline
+	var timeline []TimelineStep
+	if cfg.TimelinePath != "" {
+		timeline, err = LoadTimeline(cfg.TimelinePath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load timeline: %w", err)
+		}
+	}
+
+	// Phases and SLO assertions were checked by config.Validate
+	phases, _ := slo.ParsePhases(cfg.Phases)
+	assertions, _ := slo.ParseAssertions(cfg.SLOs)
+	thresholds, _ := compare.ParseThresholds(cfg.FailOn)
+
+	// Connect to the endpoints of the read-repair stress probe
+	convergence, err := newConvergenceProbe(cfg.ConvergenceEndpoints, cfg.ConvergenceInterval, cfg.ConvergenceTimeout, cfg.RunID, clientOpts, creds)
 	if err != nil {
-		pool.Close()
-		return nil, fmt.Errorf("failed to create key generator: %w", err)
+		return nil, fmt.Errorf("failed to create convergence probe: %w", err)
+	}
+	if convergence != nil {
+		cleanup = append(cleanup, convergence.close)
+	}
+
+	// Credentials of the simulated tenants
+	var identities []*kvclient.Identity
+	if cfg.TenantProfiles != "" {
+		identities, err = loadTenantIdentities(cfg.TenantProfiles, cfg)
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	ctx, cancel := context.WithCancel(context.Background())
+	cleanup = append(cleanup, cancel)
+
+	r := &BenchmarkRunner{
+		config:     cfg,
+		pool:       pool,
+		collector:  collector,
+		keyGen:     keyGen,
+		opLimiters: newOperationLimiters(cfg),
+		agents:     agents,
+		gate:       gate,
+		limiter:    rate.NewLimiter(rate.Inf, 1),
+		timeline:   timeline,
+		ctx:        ctx,
+		cancel:     cancel,
+		startTime:  startTime,
+		phases:     phases,
+		assertions: assertions,
+		thresholds: thresholds,
+		energy:     newEnergyMeter(cfg.EnergyCommand, cfg.EnergyMode),
+		index:      &indexTracker{},
+		tagger:     tagger,
+		intervals:  &intervalSeries{},
+		annotator:  annotator,
+		notifyURL:  notifyURL,
+
+		convergence: convergence,
+		creds:       creds,
+		identities:  identities,
+	}
+
+	// Start admin endpoint
+	if cfg.AdminAddress != "" {
+		r.admin = admin.NewServer("Admin server")
+		r.registerAdminRoutes(r.admin)
+		if err := r.admin.Start(cfg.AdminAddress); err != nil {
+			return nil, fmt.Errorf("failed to start admin server: %w", err)
+		}
+		cleanup = append(cleanup, func() { r.admin.Shutdown(context.Background()) })
+	}
+
+	// Serve the live web dashboard
+	if cfg.WebAddress != "" {
+		r.events = newEventStream(cfg.RunID, collector.Percentiles())
+		r.web = admin.NewServer("Web dashboard")
+		r.registerWebRoutes(r.web)
+		if err := r.web.Start(cfg.WebAddress); err != nil {
+			return nil, fmt.Errorf("failed to start web dashboard: %w", err)
+		}
+	}
 
-	return &BenchmarkRunner{
-		config:    cfg,
-		pool:      pool,
-		collector: collector,
-		keyGen:    keyGen,
-		ctx:       ctx,
-		cancel:    cancel,
-		startTime: time.Now(),
-	}, nil
+	return r, nil
 }
 
 // Run executes the benchmark
-func (r *BenchmarkRunner) Run() error {
+func (r *BenchmarkRunner) Run() (err error) {
+	// Report the outcome last, once the results were written and uploaded
+	if r.notifyURL != "" {
+		defer func() { r.notify(err) }()
+	}
+	if r.notifyURL != "" || r.config.TUI {
+		defer r.handleInterrupts()()
+	}
 	defer r.cleanup()
 
 	log.Printf("Starting benchmark with config: %s", r.config.String())
+	if r.config.Notes != "" {
+		log.Printf("Notes: %s", r.config.Notes)
+	}
+
+	// Parse the report template before the run so mistakes surface early
+	var reportTemplate *template.Template
+	if r.config.ReportTemplate != "" {
+		tmpl, err := r.parseReportTemplate(r.config.ReportTemplate)
+		if err != nil {
+			return err
+		}
+		reportTemplate = tmpl
+	}
+
+	if err := r.loadBaseline(); err != nil {
+		return err
+	}
+
+	// Check for the SQLite shell before the run rather than losing its results
+	if r.config.SQLitePath != "" {
+		if err := checkSQLite(); err != nil {
+			return err
+		}
+		r.intervalLog = &intervalLog{}
+		r.collector.AddSink(r.intervalLog)
+	}
+
+	// Likewise for the converter of PNG charts
+	if r.config.ChartsDir != "" {
+		if err := checkChartConverter(chartFormats(r.config.ChartFormat)); err != nil {
+			return err
+		}
+	}
+
+	// And for the upload credentials, which ephemeral machines often lack
+	if r.config.UploadURL != "" {
+		location, _ := objstore.Parse(r.config.UploadURL) // Checked by config.Validate
+		store, err := objstore.Open(location)
+		if err != nil {
+			return fmt.Errorf("cannot upload results: %w", err)
+		}
+		r.store = store
+	}
+
+	// Stream raw per-operation results
+	if r.config.RawLogPath != "" {
+		l, err := collector.NewRawLog(r.config.RawLogPath, r.config.RawLogSample, r.collector.Labels())
+		if err != nil {
+			return err
+		}
+		r.collector.SetRawLog(l)
+	}
+
+	// Write raw results and interval statistics as Parquet for analysis tools
+	if r.config.ParquetRaw != "" {
+		l, err := collector.NewParquetRawLog(r.config.ParquetRaw, r.config.RawLogSample, r.collector.Labels())
+		if err != nil {
+			return err
+		}
+		r.collector.SetParquetRawLog(l)
+	}
+	if r.config.ParquetIntervals != "" {
+		s, err := collector.NewParquetSink(r.config.ParquetIntervals, r.collector.Percentiles(), r.collector.Labels())
+		if err != nil {
+			return err
+		}
+		r.collector.AddSink(s)
+	}
+
+	// Log interval histograms in HdrHistogram format
+	if r.config.HistogramLog != "" {
+		l, err := collector.NewHistogramLog(r.config.HistogramLog)
+		if err != nil {
+			return err
+		}
+		r.collector.SetHistogramLog(l)
+	}
+
+	// Write per-interval latency bucket counts for heatmaps
+	if r.config.HeatmapPath != "" {
+		h, err := collector.NewHeatmap(r.config.HeatmapPath)
+		if err != nil {
+			return err
+		}
+		r.collector.SetHeatmap(h)
+	}
+
+	// Pair interval throughput with latency for throughput/latency curves
+	if r.config.CurvePath != "" {
+		l, err := collector.NewCurveLog(r.config.CurvePath)
+		if err != nil {
+			return err
+		}
+		r.collector.SetCurveLog(l)
+	}
+
+	// Persist interval histograms for post-run queries
+	if r.config.HistogramStore != "" {
+		w, err := histstore.Create(r.config.HistogramStore, r.startTime)
+		if err != nil {
+			return err
+		}
+		r.collector.SetHistogramStore(w)
+	}
+
+	// Keep interval statistics for confidence intervals and the charts of the
+	// HTML report and web dashboard
+	r.collector.AddSink(r.intervals)
+	if r.events != nil {
+		r.collector.AddSink(r.events)
+	}
+
+	// Send per-operation metrics to StatsD
+	if r.config.StatsDAddress != "" {
+		s, err := collector.NewStatsDSink(r.config.StatsDAddress, r.config.StatsDPrefix, statsdTags(r.config.StatsDTags, r.collector.Labels()))
+		if err != nil {
+			return err
+		}
+		r.collector.SetStatsD(s)
+	}
+
+	// Continue an interrupted run from its checkpoint
+	if r.config.Resume {
+		if err := r.resume(); err != nil {
+			return err
+		}
+	}
 
 	// Start collector
 	r.collector.Start(r.ctx)
 
+	// Start metric exporters
+	if err := r.startExporters(); err != nil {
+		return err
+	}
+
+	// Toggle pause/resume on SIGUSR2
+	r.handlePauseSignal(r.ctx)
+
 	// Health check
 	if err := r.pool.HealthCheck(r.ctx, 5*time.Second); err != nil {
 		log.Printf("Warning: health check failed: %v", err)
 	}
 
+	// Measure the client's own overhead against a null backend
+	if r.config.CalibrateDuration > 0 {
+		if err := r.runCalibration(r.ctx); err != nil {
+			return err
+		}
+	}
+
+	if r.annotator != nil {
+		r.annotator.Point(time.Now(), fmt.Sprintf("Run %s started", r.config.RunID))
+	}
+
+	// Show the live dashboard instead of progress lines
+	r.dashboard = r.newDashboard()
+	if r.dashboard != nil {
+		r.dashboard.start()
+	}
+
 	// Warm-up phase
 	if r.config.WarmupDuration > 0 {
 		log.Printf("Starting warm-up phase for %v", r.config.WarmupDuration)
+		if r.dashboard != nil {
+			r.dashboard.setPhase("warm-up", r.config.WarmupDuration)
+		}
+		warmupStart := time.Now()
 		r.runWorkers(r.config.WarmupDuration, true)
 		log.Printf("Warm-up phase completed")
+		if r.annotator != nil {
+			r.annotator.Region(warmupStart, time.Now(), fmt.Sprintf("Run %s: warm-up", r.config.RunID))
+		}
+	}
+
+	// Actual benchmark phase, or a search for the highest rate meeting an objective
+	r.search = r.newRateSearch()
+	if r.search == nil {
+		log.Printf("Starting benchmark phase for %v", r.config.Duration)
+	}
+	if r.dashboard != nil {
+		if r.search == nil {
+			r.dashboard.setPhase("measuring", r.config.Duration)
+		} else {
+			r.dashboard.setPhase("searching", 0)
+		}
+	}
+	if r.agents != nil {
+		r.agents.SetAccepting(true)
+	}
+	measuredStart := time.Now()
+	r.collector.StartIntervals(measuredStart)
+	r.sampleEnergy(r.ctx)
+	if r.convergence != nil {
+		r.convergence.start(r.ctx)
+	}
+	if r.search != nil {
+		r.runSearch(r.search)
+	} else {
+		r.startPhases(measuredStart)
+		r.runWorkers(r.config.Duration, false)
+	}
+	if r.convergence != nil {
+		r.convergence.stop()
+	}
+	if r.agents != nil {
+		r.agents.SetAccepting(false)
+	}
+	if r.dashboard != nil {
+		r.dashboard.close()
+	}
+	r.collector.Flush()
+	measuredEnd := time.Now()
+	r.collector.EndRun(measuredEnd)
+	r.collector.EndInterval(measuredEnd)
+	r.annotatePhases(measuredStart, measuredEnd)
+	r.sampleEnergy(context.Background())
+	r.evaluateSLOs(measuredEnd)
+	r.checkRegressions()
+
+	// Save the final state, marking the run completed unless it was stopped early
+	if r.config.CheckpointPath != "" {
+		position := r.measured.Elapsed()
+		if err := r.saveCheckpoint(position, position >= r.config.Duration); err != nil {
+			log.Printf("Warning: %v", err)
+		}
 	}
 
-	// Actual benchmark phase
-	log.Printf("Starting benchmark phase for %v", r.config.Duration)
-	r.runWorkers(r.config.Duration, false)
+	// Keep the key space for the next session
+	if r.config.GeneratorState != "" {
+		if err := r.keyGen.SaveState(r.config.GeneratorState); err != nil {
+			log.Printf("Warning: %v", err)
+		} else {
+			log.Printf("Saved generator state to %s (%d of %d keys written)", r.config.GeneratorState, r.keyGen.Written(), r.config.KeySpace)
+		}
+	}
 
 	// Print final results
 	r.printResults()
+	if r.search != nil {
+		r.printSearch(r.search)
+	}
+	if err := r.writeSummary(os.Stdout, r.config.OutputFormat); err != nil {
+		log.Printf("Warning: failed to write summary: %v", err)
+	}
+	if reportTemplate != nil {
+		if err := r.writeReport(reportTemplate, r.config.ReportOutput); err != nil {
+			log.Printf("Warning: %v", err)
+		}
+	}
+	if r.config.JSONSummary != "" {
+		if err := r.writeJSONSummary(r.config.JSONSummary); err != nil {
+			log.Printf("Warning: %v", err)
+		}
+	}
+	if r.config.YAMLSummary != "" {
+		if err := r.writeYAMLSummary(r.config.YAMLSummary); err != nil {
+			log.Printf("Warning: %v", err)
+		}
+	}
+	if r.config.MarkdownReport != "" {
+		if err := r.writeMarkdownReport(r.config.MarkdownReport); err != nil {
+			log.Printf("Warning: %v", err)
+		}
+	}
+	if r.config.HTMLReport != "" {
+		if err := r.writeHTMLReport(r.config.HTMLReport); err != nil {
+			log.Printf("Warning: %v", err)
+		}
+	}
+	if r.config.ChartsDir != "" {
+		if err := r.writeCharts(r.config.ChartsDir); err != nil {
+			log.Printf("Warning: %v", err)
+		}
+	}
+	if r.config.SQLitePath != "" {
+		if err := r.writeSQLite(r.config.SQLitePath); err != nil {
+			log.Printf("Warning: %v", err)
+		}
+	}
+
+	// Record the run and the client it ran on
+	if r.config.ManifestPath != "" {
+		if err := r.writeManifest(r.config.ManifestPath); err != nil {
+			log.Printf("Warning: %v", err)
+		}
+	}
+
+	// Hand the results to bespoke systems
+	r.runPostProcessors()
 
+	// Push final metrics to the Pushgateway
+	if r.config.PushgatewayURL != "" {
+		if err := collector.PushToGateway(r.collector, r.config.PushgatewayURL, "kvstore-benchmarker", r.config.RunID); err != nil {
+			log.Printf("Warning: %v", err)
+		} else {
+			log.Printf("Pushed final metrics to %s (run_id=%s)", r.config.PushgatewayURL, r.config.RunID)
+		}
+	}
+
+	if r.sloFailures() > 0 {
+		return ErrSLOViolated
+	}
+	if r.regressionFailures() > 0 {
+		return ErrRegressed
+	}
+	if r.search != nil && r.search.certified == 0 {
+		return ErrTargetNotMet
+	}
 	return nil
 }
 
 // runWorkers starts the worker goroutines for the specified duration
 func (r *BenchmarkRunner) runWorkers(duration time.Duration, isWarmup bool) {
-	ctx, cancel := context.WithTimeout(r.ctx, duration)
+	// The phase timer is suspended while the benchmark is paused
+	ctx, cancel := context.WithCancel(r.ctx)
 	defer cancel()
+	clock := newPhaseClock(r.gate)
+	if !isWarmup {
+		clock.advance(r.resumeAt)
+		r.measured = clock
+	}
+	go runPhaseTimer(ctx, cancel, clock, duration)
+
+	// The duty cycle only shapes the measured phase
+	r.duty = nil
+	if !isWarmup {
+		r.duty = newDutyCycle(r.config.DutyCycleOn, r.config.DutyCycleOff, clock)
+		if r.duty != nil {
+			log.Printf("Duty cycle enabled: %v load, %v idle", r.config.DutyCycleOn, r.config.DutyCycleOff)
+		}
+	}
 
 	// Start workers
-	for i := 0; i < r.config.NumWorkers; i++ {
+	group := &workerGroup{ctx: ctx, isWarmup: isWarmup}
+	numWorkers := r.config.NumWorkers
+	if !isWarmup && len(r.timeline) > 0 && r.timeline[0].Offset == 0 && r.timeline[0].Workers > 0 {
+		numWorkers = r.timeline[0].Workers
+	}
+	if r.config.RampUp > 0 && numWorkers > 1 {
+		r.wg.Add(1)
+		go func() {
+			defer r.wg.Done()
+			r.rampUp(ctx, clock, group, numWorkers, r.config.RampUp, isWarmup)
+		}()
+	} else {
+		r.scaleWorkers(group, numWorkers)
+	}
+
+	// Follow the load profile timeline during the measured phase
+	if !isWarmup && len(r.timeline) > 0 {
 		r.wg.Add(1)
-		go r.worker(ctx, i, isWarmup)
+		go func() {
+			defer r.wg.Done()
+			r.followTimeline(ctx, clock, group, r.timeline)
+		}()
 	}
 
-	// Start progress reporter if not in warmup
+	// Start progress reporter and checkpoints if not in warmup
 	if !isWarmup {
 		go r.progressReporter(ctx)
+		if r.config.CheckpointPath != "" {
+			r.wg.Add(1)
+			go func() {
+				defer r.wg.Done()
+				r.checkpointer(ctx, clock)
+			}()
+		}
 	}
 
 	// Wait for completion
 	r.wg.Wait()
 }
 
+// workerGroup tracks the workers of a phase so that they can be scaled
+type workerGroup struct {
+	mu       sync.Mutex
+	ctx      context.Context
+	isWarmup bool
+	cancels  []context.CancelFunc
+}
+
+// size returns the number of running workers
+func (g *workerGroup) size() int {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return len(g.cancels)
+}
+
+// scaleWorkers starts or stops workers until n are running
+func (r *BenchmarkRunner) scaleWorkers(g *workerGroup, n int) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	for len(g.cancels) < n {
+		ctx, cancel := context.WithCancel(g.ctx)
+		g.cancels = append(g.cancels, cancel)
+		r.wg.Add(1)
+		go r.worker(ctx, len(g.cancels)-1, g.isWarmup)
+	}
+	for len(g.cancels) > n {
+		last := len(g.cancels) - 1
+		g.cancels[last]()
+		g.cancels = g.cancels[:last]
+	}
+}
+
 // worker is the main worker goroutine
 func (r *BenchmarkRunner) worker(ctx context.Context, workerID int, isWarmup bool) {
 	defer r.wg.Done()
 
 	client := r.pool.GetClient()
 
+	// Spread worker start times so that workers do not run in lockstep
+	if !waitStartJitter(ctx, r.config.WorkerStartJitter) {
+		return
+	}
+
+	// Last throttle with a suggested retry delay, for the adherence check
+	var retry pendingRetry
+	throttling := backoff{base: r.config.ThrottleBackoff, max: r.config.ThrottleBackoffMax}
 	for {
 		select {
 		case <-ctx.Done():
 			return
 		default:
-			r.performOperation(ctx, client, isWarmup, workerID)
+			if !r.gate.wait(ctx) {
+				return
+			}
+			if r.duty != nil && !r.duty.wait(ctx) {
+				return
+			}
+			r.retries.observe(&retry, time.Now())
+			throttled, hint := r.performOperation(ctx, client, isWarmup, workerID)
+			if hint > 0 {
+				retry = pendingRetry{at: time.Now(), delay: hint}
+			}
+			if delay := r.throttleDelay(&throttling, throttled, hint); delay > 0 && !waitThrottled(ctx, delay) {
+				return
+			}
 		}
 	}
 }
 
-// performOperation performs a single operation based on configured ratios
-func (r *BenchmarkRunner) performOperation(ctx context.Context, client *kvclient.Client, isWarmup bool, workerID int) {
-	// Select operation based on ratios
-	op := r.selectOperation()
+// performOperation performs a single operation based on configured ratios,
+// and reports whether the server throttled it and the retry delay it suggested
+func (r *BenchmarkRunner) performOperation(ctx context.Context, client *kvclient.Client, isWarmup bool, workerID int) (bool, time.Duration) {
+	// Apply the total rate limit
+	if err := r.limiter.Wait(ctx); err != nil {
+		return false, 0
+	}
+
+	// Select operation based on ratios and per-operation rate limits
+	op, err := r.admitOperation(ctx, r.selectOperation())
+	if err != nil {
+		return false, 0
+	}
 
 	// Get key and value
-	key := r.keyGen.GetRandomKey()
+	keyIndex := r.keyGen.GetRandomKeyIndex()
+	key := r.keyGen.Key(keyIndex)
 	var value []byte
-	var err error
 
+	opCtx, cancel := r.opContext(ctx)
+	if r.identities != nil {
+		opCtx = kvclient.WithIdentity(opCtx, r.identities[keyIndex%len(r.identities)])
+	}
+	opCtx, wire := kvclient.WithWireSize(opCtx)
+	var timing *kvclient.Timing
+	if r.config.LatencyBreakdown {
+		opCtx, timing = kvclient.WithTiming(opCtx)
+	}
 	start := time.Now()
 
+	// Payload sizes count keys and values, not protocol framing
+	sent := int64(len(key))
+	var received int64
 	switch op {
 	case "Get":
-		_, err = client.Get(ctx, key)
+		var resp *pb.GetResponse
+		resp, err = client.Get(opCtx, key)
+		if err == nil {
+			received = int64(len(resp.Value))
+		}
 	case "Put":
 		value, err = GenerateValue(r.config.ValueSize)
 		if err == nil {
-			_, err = client.Put(ctx, key, value)
+			sent += int64(len(value))
+			_, err = client.Put(opCtx, key, value)
 		}
 	case "Delete":
-		_, err = client.Delete(ctx, key)
+		_, err = client.Delete(opCtx, key)
+	case "IndexPut":
+		sent, err = r.indexPut(opCtx, client, key)
+	case "IndexGet":
+		sent, received, err = r.indexGet(opCtx, client, key, isWarmup)
 	}
 
-	latency := time.Since(start).Milliseconds()
+	elapsed := time.Since(start)
+	cancel()
+
+	// Track which keys hold data for the saved generator state
+	if r.config.GeneratorState != "" && err == nil && (op == "Put" || op == "Delete") {
+		r.keyGen.MarkWritten(keyIndex, op == "Put")
+	}
+
+	// Operations interrupted because the worker was stopped are not results
+	if ctx.Err() != nil {
+		return false, 0
+	}
+
+	// Throttled operations may come with a suggested retry delay
+	throttled := collector.IsThrottled(err)
+	var retryAfter time.Duration
+	if throttled {
+		retryAfter = kvclient.RetryAfter(err)
+	}
 
 	// Create result
 	result := &collector.BenchmarkResult{
 		Method:    op,
-		LatencyMs: float64(latency),
+		LatencyMs: float64(elapsed) / float64(time.Millisecond),
 		Error:     err,
 		Timestamp: time.Now(),
+		Worker:    workerID,
+
+		BytesSent:     sent,
+		BytesReceived: received,
+
+		WireSent:     wire.Sent(),
+		WireReceived: wire.Received(),
+
+		Warmup: isWarmup,
+
+		RetryAfter: retryAfter,
+	}
+	if r.tagger != nil {
+		result.Tags = r.tagger.tagsOf(keyIndex, sent+received)
+	}
+	if timing != nil {
+		result.Breakdown = breakdownOf(timing.Breakdown())
 	}
 
-	// Add to collector (only if not warmup)
-	if !isWarmup {
+	// Warm-up results are dropped unless collected into their own statistics
+	if !isWarmup || r.config.CollectWarmup {
 		r.collector.AddResult(result)
 	}
 
@@ -171,15 +880,16 @@ func (r *BenchmarkRunner) performOperation(ctx context.Context, client *kvclient
 		if err != nil {
 			log.Printf("Worker %d: %s failed for key %x: %v", workerID, op, key, err)
 		} else if r.config.LogRequests {
-			log.Printf("Worker %d: %s succeeded for key %x in %dms", workerID, op, key, latency)
+			log.Printf("Worker %d: %s succeeded for key %x in %s", workerID, op, key, r.unit().Display(result.LatencyMs))
 		}
 	}
+	return throttled, retryAfter
 }
 
 // selectOperation selects an operation based on configured ratios
 func (r *BenchmarkRunner) selectOperation() string {
 	// Create weighted distribution
-	dist := make([]string, 0, r.config.ReadRatio+r.config.WriteRatio+r.config.DeleteRatio)
+	dist := make([]string, 0, 100)
 
 	// Add operations based on ratios
 	for i := 0; i < r.config.ReadRatio; i++ {
@@ -191,6 +901,12 @@ func (r *BenchmarkRunner) selectOperation() string {
 	for i := 0; i < r.config.DeleteRatio; i++ {
 		dist = append(dist, "Delete")
 	}
+	for i := 0; i < r.config.IndexWriteRatio; i++ {
+		dist = append(dist, "IndexPut")
+	}
+	for i := 0; i < r.config.IndexReadRatio; i++ {
+		dist = append(dist, "IndexGet")
+	}
 
 	// Select random operation
 	return dist[rand.Intn(len(dist))]
@@ -198,88 +914,378 @@ func (r *BenchmarkRunner) selectOperation() string {
 
 // progressReporter reports progress at regular intervals
 func (r *BenchmarkRunner) progressReporter(ctx context.Context) {
-	ticker := time.NewTicker(r.config.ReportInterval)
+	ticker := collector.NewIntervalTicker(r.config.ReportInterval, r.config.AlignIntervals)
 	defer ticker.Stop()
 
 	for {
 		select {
 		case <-ctx.Done():
 			return
-		case <-ticker.C:
-			r.printProgress()
+		case now := <-ticker.C:
+			if r.dashboard == nil {
+				r.printProgress()
+			}
+			r.collector.EndInterval(now)
+			r.sampleEnergy(ctx)
 		}
 	}
 }
 
-// printProgress prints current progress with aggregated percentiles
+// printProgress prints current progress with aggregated percentiles, as a
+// log line or with --progress-format=json as a JSON line on stdout
 func (r *BenchmarkRunner) printProgress() {
-	stats := r.collector.GetAggregatedStats()
-	if stats.Count == 0 {
+	total := r.collector.GetAggregatedStats()
+	if total.Count == 0 {
 		return
 	}
 
-	// Calculate RPS based on the report interval
-	elapsed := time.Since(r.startTime).Seconds()
+	// Rates, latencies and errors cover only the last report interval
+	_, stats, window := r.collector.SnapshotAndReset(time.Now())
+	if r.config.ProgressFormat == "json" {
+		r.writeProgressJSON(total, stats, window)
+		return
+	}
+	elapsed := window.Seconds()
 	rps := float64(stats.Count) / elapsed
 
-	log.Printf("[%s] Total: %d | RPS: %.0f | Avg: %.1fms | P50: %.1fms | P95: %.1fms | P99: %.1fms | Errors: %d (%.1f%%)",
+	unit := r.unit()
+	var percentiles strings.Builder
+	for i, p := range r.collector.Percentiles() {
+		fmt.Fprintf(&percentiles, " | %s: %s", collector.PercentileLabel(p), unit.Display(stats.Percentile(i)))
+	}
+	if conformance := stats.SLOConformance(); conformance >= 0 {
+		fmt.Fprintf(&percentiles, " | SLO Met: %.2f%% (%d violations)", conformance, stats.SLOViolations)
+	}
+	var throttled string
+	if stats.Throttled > 0 {
+		throttled = fmt.Sprintf(" | Throttled: %d (%.1f%%) | Admitted RPS: %.0f", stats.Throttled, stats.ThrottleRate(), float64(stats.Admitted())/elapsed)
+	}
+	log.Printf("[%s] Total: %d | RPS: %.0f | Read: %.2f MB/s | Write: %.2f MB/s | Avg: %s%s | Errors: %d (%.1f%%)%s",
 		time.Now().Format("15:04:05"),
-		stats.Count,
+		total.Count,
 		rps,
-		stats.AvgLatency,
-		stats.P50Latency,
-		stats.P95Latency,
-		stats.P99Latency,
+		collector.MBPerSec(stats.BytesRecv, elapsed),
+		collector.MBPerSec(stats.BytesSent, elapsed),
+		unit.Display(stats.AvgLatency),
+		percentiles.String(),
 		stats.ErrorCount,
 		stats.ErrorRate,
+		throttled,
 	)
 }
 
-// printResults prints final benchmark results with detailed aggregated statistics
+// statsdTags splits a comma-separated tag list, ignoring empty entries, and
+// adds the run labels as name:value tags
+func statsdTags(list string, labels []collector.Label) []string {
+	var tags []string
+	for _, tag := range strings.Split(list, ",") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+	for _, label := range labels {
+		tags = append(tags, label.Name+":"+label.Value)
+	}
+	return tags
+}
+
+// csvFormat builds the collector CSV rendering options from the configuration
+func csvFormat(cfg *config.BenchmarkConfig) collector.CSVFormat {
+	delimiter, _ := collector.ParseCSVDelimiter(cfg.CSVDelimiter)
+	return collector.CSVFormat{
+		Delimiter:  delimiter,
+		Precision:  cfg.CSVPrecision,
+		Timestamps: cfg.CSVTimestamps,
+	}
+}
+
+// csvComments returns the --csv-metadata lines describing the run, so that a
+// results CSV explains itself without the other outputs of the run
+func csvComments(cfg *config.BenchmarkConfig, created time.Time) []string {
+	if !cfg.CSVMetadata {
+		return nil
+	}
+	config, _ := json.Marshal(cfg)
+	return []string{
+		"kvstore-benchmarker " + manifest.CollectBuild().String(),
+		"run_id: " + cfg.RunID,
+		"created: " + created.UTC().Format(time.RFC3339),
+		"client: " + describeClient(manifest.CollectClient()),
+		"config: " + string(config),
+	}
+}
+
+// unit returns the configured latency output unit
+func (r *BenchmarkRunner) unit() latency.Unit {
+	return latency.Unit(r.config.LatencyUnit)
+}
+
+// printResults prints final benchmark results as a table of per-method and aggregated statistics
 func (r *BenchmarkRunner) printResults() {
-	log.Printf("\n=== FINAL RESULTS ===")
+	unit := r.unit()
+	out := log.Writer()
+	color := colorEnabled(r.config.Color, out)
+
+	if r.config.CollectWarmup {
+		r.printWarmup(out, color)
+	}
 
-	// Print per-method statistics
 	stats := r.collector.GetStats()
-	for method, stat := range stats {
-		if stat.Count == 0 {
-			continue
+	aggregated := r.collector.GetAggregatedStats()
+	table, methods := r.statsTable(stats, aggregated)
+
+	fmt.Fprintf(out, "\n=== FINAL RESULTS (latencies in %s) ===\n\n", unit.Name())
+	if r.config.Notes != "" {
+		fmt.Fprintf(out, "Run %s: %s\n\n", r.config.RunID, r.config.Notes)
+	}
+	table.render(out, color)
+	fmt.Fprintln(out)
+	r.printTagged(out, color)
+	r.printBreakdown(out, color)
+	r.printOverhead(out, color, stats)
+
+	// Error breakdown by gRPC status code
+	if aggregated.ErrorCount > 0 {
+		codes := newTextTable("Method", "Code", "Errors", "Share%")
+		for _, method := range methods {
+			stat := stats[method]
+			for _, code := range collector.SortedErrorCodes(stat.ErrorCodes) {
+				n := stat.ErrorCodes[code]
+				codes.addRow(
+					tableCell{text: method},
+					tableCell{text: code, style: ansiRed},
+					tableCell{text: fmt.Sprintf("%d", n)},
+					tableCell{text: fmt.Sprintf("%.1f", float64(n)/float64(stat.ErrorCount)*100)},
+				)
+			}
 		}
+		fmt.Fprintf(out, "=== ERRORS BY CODE ===\n\n")
+		codes.render(out, color)
+		fmt.Fprintln(out)
 
-		log.Printf("\n%s:", method)
-		log.Printf("  Count: %d", stat.Count)
-		log.Printf("  Errors: %d (%.2f%%)", stat.ErrorCount, stat.ErrorRate)
-		log.Printf("  Avg Latency: %.2fms", stat.AvgLatency)
-		log.Printf("  P50 Latency: %.2fms", stat.P50Latency)
-		log.Printf("  P95 Latency: %.2fms", stat.P95Latency)
-		log.Printf("  P99 Latency: %.2fms", stat.P99Latency)
-		log.Printf("  Min Latency: %.2fms", stat.MinLatency)
-		log.Printf("  Max Latency: %.2fms", stat.MaxLatency)
+		// Failures are kept out of the latency columns above
+		failed := newTextTable("Method", "Errors", "Avg", "P50", "P99", "Max")
+		addFailed := func(stat collector.Stats, style string) {
+			failed.addRow(
+				tableCell{text: stat.Method, style: style},
+				tableCell{text: fmt.Sprintf("%d", stat.ErrorCount), style: style},
+				tableCell{text: unit.Value(stat.ErrorAvgLatency), style: style},
+				tableCell{text: unit.Value(stat.ErrorP50Latency), style: style},
+				tableCell{text: unit.Value(stat.ErrorP99Latency), style: style},
+				tableCell{text: unit.Value(stat.ErrorMaxLatency), style: style},
+			)
+		}
+		for _, method := range methods {
+			if stat := stats[method]; stat.ErrorCount > 0 {
+				addFailed(stat, "")
+			}
+		}
+		addFailed(aggregated, ansiBold)
+		fmt.Fprintf(out, "=== FAILED OPERATION LATENCIES (latencies in %s) ===\n\n", unit.Name())
+		failed.render(out, color)
+		fmt.Fprintln(out)
 	}
 
-	// Print aggregated statistics
-	aggregated := r.collector.GetAggregatedStats()
-	if aggregated.Count > 0 {
-		log.Printf("\n=== AGGREGATED STATISTICS ===")
-		log.Printf("Total Operations: %d", aggregated.Count)
-		log.Printf("Total Errors: %d (%.2f%%)", aggregated.ErrorCount, aggregated.ErrorRate)
-		log.Printf("Overall Avg Latency: %.2fms", aggregated.AvgLatency)
-		log.Printf("Overall P50 Latency: %.2fms", aggregated.P50Latency)
-		log.Printf("Overall P95 Latency: %.2fms", aggregated.P95Latency)
-		log.Printf("Overall P99 Latency: %.2fms", aggregated.P99Latency)
-		log.Printf("Overall Min Latency: %.2fms", aggregated.MinLatency)
-		log.Printf("Overall Max Latency: %.2fms", aggregated.MaxLatency)
+	r.printConformance(out, color, stats, methods)
+	r.printSLOs(out, color)
+	r.printBaseline(out, color)
+	r.printConfidence(out, color)
+	r.printEnergy()
+	r.printIndex()
+	r.printConvergence()
 
+	if aggregated.Count > 0 {
 		// Calculate final throughput
-		totalDuration := time.Since(r.startTime).Seconds()
+		totalDuration := r.collector.Measured().Seconds()
 		finalRPS := float64(aggregated.Count) / totalDuration
 		log.Printf("Final Throughput: %.0f ops/sec", finalRPS)
+		log.Printf("Final Bandwidth: read %.2f MB/s, write %.2f MB/s",
+			collector.MBPerSec(aggregated.BytesRecv, totalDuration), collector.MBPerSec(aggregated.BytesSent, totalDuration))
+		if aggregated.WireSent > 0 || aggregated.WireRecv > 0 {
+			sentPerOp, recvPerOp := aggregated.WirePerOp()
+			log.Printf("Wire Bandwidth: sent %.2f MB/s, received %.2f MB/s (%.0f B/op sent, %.0f B/op received)",
+				collector.MBPerSec(aggregated.WireSent, totalDuration), collector.MBPerSec(aggregated.WireRecv, totalDuration), sentPerOp, recvPerOp)
+		}
+		if r.config.CostPerHour > 0 {
+			log.Printf("Cost: %.4f per million ops at %.0f ops/sec (%.2f per hour)",
+				costPerMillion(r.config.CostPerHour, finalRPS), finalRPS, r.config.CostPerHour)
+		}
+	}
+
+	for _, gap := range r.gaps {
+		log.Printf("Gap: %s to %s (%v not measured, resumed from checkpoint)",
+			gap.From.Format(time.RFC3339), gap.To.Format(time.RFC3339), gap.To.Sub(gap.From).Round(time.Second))
+	}
+
+	if r.rampEnd > 0 {
+		log.Printf("Ramp-up Window: first %v of the measured phase (included in the statistics above)", r.rampEnd.Round(time.Millisecond))
+	}
+
+	if aggregated.Successes() > 0 {
+		unit := r.unit()
+		log.Printf("Robust Latency: trimmed mean %s (%g%% trimmed per tail), MAD %s (mean %s)",
+			unit.Display(aggregated.TrimmedMean), r.collector.TrimPct(), unit.Display(aggregated.MAD), unit.Display(aggregated.AvgLatency))
+	}
+
+	r.logThrottling(aggregated)
+
+	if aggregated.Overflow > 0 {
+		log.Printf("Latency Overflow: %d operations slower than the %v bound (recorded at the bound, Max shows the true maximum)",
+			aggregated.Overflow, r.collector.LatencyMax())
+	}
+
+	if dropped := r.collector.MeasuredDropped(); dropped > 0 {
+		pct := float64(dropped) / float64(aggregated.Count+dropped) * 100
+		log.Printf("Dropped Results: %d (%.2f%% of all results, results channel full, statistics are incomplete)", dropped, pct)
+	}
+}
+
+// statsTable builds the results table of per-method and aggregated statistics,
+// returning the methods with results in table order
+func (r *BenchmarkRunner) statsTable(stats map[string]collector.Stats, aggregated collector.Stats) (*textTable, []string) {
+	header := []string{"Method", "Count", "Errors", "Error%", "Avg"}
+	for _, p := range r.collector.Percentiles() {
+		header = append(header, collector.PercentileLabel(p))
+	}
+	table := newTextTable(append(header, "Min", "Max")...)
+	addStats := func(stat collector.Stats, style string) {
+		table.addRow(r.statsRow(stat, style)...)
+	}
+
+	// Per-method rows in a stable order
+	methods := make([]string, 0, len(stats))
+	for method, stat := range stats {
+		if stat.Count > 0 {
+			methods = append(methods, method)
+		}
+	}
+	sort.Strings(methods)
+	for _, method := range methods {
+		addStats(stats[method], "")
 	}
+
+	if aggregated.Count > 0 {
+		addStats(aggregated, ansiBold)
+	}
+	return table, methods
+}
+
+// statsRow renders statistics as the cells of a results table row
+func (r *BenchmarkRunner) statsRow(stat collector.Stats, style string) []tableCell {
+	unit := r.unit()
+	errorStyle := ansiGreen
+	if stat.ErrorCount > 0 {
+		errorStyle = ansiRed
+	}
+	row := []tableCell{
+		{text: stat.Method, style: style},
+		{text: fmt.Sprintf("%d", stat.Count), style: style},
+		{text: fmt.Sprintf("%d", stat.ErrorCount), style: errorStyle},
+		{text: fmt.Sprintf("%.2f", stat.ErrorRate), style: errorStyle},
+		{text: unit.Value(stat.AvgLatency), style: style},
+	}
+	for i := range r.collector.Percentiles() {
+		row = append(row, tableCell{text: unit.Value(stat.Percentile(i)), style: style})
+	}
+	return append(row,
+		tableCell{text: unit.Value(stat.MinLatency), style: style},
+		tableCell{text: unit.Value(stat.MaxLatency), style: style},
+	)
+}
+
+// printWarmup prints the separately collected warm-up statistics and how the
+// measured phase compares, e.g. cold against warmed caches
+func (r *BenchmarkRunner) printWarmup(out io.Writer, color bool) {
+	unit := r.unit()
+	stats, aggregated := r.collector.GetWarmupStats()
+	if aggregated.Count == 0 {
+		log.Printf("Warm-up: no results collected")
+		return
+	}
+	table, _ := r.statsTable(stats, aggregated)
+
+	fmt.Fprintf(out, "\n=== WARM-UP RESULTS (latencies in %s) ===\n\n", unit.Name())
+	table.render(out, color)
+	fmt.Fprintln(out)
+
+	measured := r.collector.GetAggregatedStats()
+	warmupRPS := float64(aggregated.Count) / r.config.WarmupDuration.Seconds()
+	line := fmt.Sprintf("Warm-up Throughput: %.0f ops/sec | Avg: %s", warmupRPS, unit.Display(aggregated.AvgLatency))
+	if measured.Count > 0 {
+		line += fmt.Sprintf(" (%s measured)", unit.Display(measured.AvgLatency))
+	}
+	log.Print(line)
+}
+
+// exporter periodically pushes collector statistics to an external system
+type exporter interface {
+	Start(ctx context.Context)
+	Stop()
+}
+
+// startExporters creates and starts the configured metric exporters
+func (r *BenchmarkRunner) startExporters() error {
+	if r.config.RemoteWriteURL != "" {
+		r.exporters = append(r.exporters, collector.NewRemoteWriteExporter(r.collector, r.config.RemoteWriteURL, r.config.ReportInterval))
+	}
+	if r.config.OTLPEndpoint != "" {
+		r.exporters = append(r.exporters, collector.NewOTLPExporter(r.collector, r.config.OTLPEndpoint, r.config.RunID, r.config.ReportInterval))
+	}
+	if r.config.GraphiteAddress != "" {
+		r.exporters = append(r.exporters, collector.NewGraphiteExporter(r.collector, r.config.GraphiteAddress, r.config.GraphiteTemplate, r.config.RunID, r.config.ReportInterval))
+	}
+	if r.config.CloudWatchEMF != "" {
+		e, err := collector.NewCloudWatchEMFExporter(r.collector, r.config.CloudWatchEMF, r.config.CloudWatchNamespace, r.config.ReportInterval)
+		if err != nil {
+			return fmt.Errorf("failed to create CloudWatch exporter: %w", err)
+		}
+		r.exporters = append(r.exporters, e)
+	}
+	if r.config.GCPProject != "" {
+		r.exporters = append(r.exporters, collector.NewGCPMonitoringExporter(r.collector, r.config.GCPProject, r.config.ReportInterval))
+	}
+
+	for _, e := range r.exporters {
+		e.Start(r.ctx)
+	}
+	return nil
+}
+
+// activeElapsed returns the time since the runner started, excluding paused
+// time, plus the active time before the checkpoint of a resumed run
+func (r *BenchmarkRunner) activeElapsed() time.Duration {
+	return r.priorActive + time.Since(r.startTime) - r.gate.PausedTotal()
 }
 
 // cleanup performs cleanup operations
 func (r *BenchmarkRunner) cleanup() {
 	r.cancel()
+	if r.admin != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		r.admin.Shutdown(ctx)
+		cancel()
+	}
+	for _, e := range r.exporters {
+		e.Stop()
+	}
+	if r.agents != nil {
+		r.agents.Stop()
+	}
 	r.collector.Stop()
+	// After the collector, which ends the event streams with the final statistics
+	if r.web != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		r.web.Shutdown(ctx)
+		cancel()
+	}
 	r.pool.Close()
+	if r.convergence != nil {
+		r.convergence.close()
+	}
+	r.creds.stop()
+	if r.annotator != nil {
+		r.annotator.Wait()
+	}
+	// Last, once the collector has closed the CSV and the other outputs
+	r.uploadResults()
 }
@@ -0,0 +1,118 @@
+package runner
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"kvstore-benchmarker/pkg/slo"
+)
+
+// ErrTargetNotMet is returned by Run when a rate search found no rate meeting
+// its objective
+var ErrTargetNotMet = errors.New("no rate met the search objective")
+
+// minAchievedRatio is the share of a step's target rate that must actually be
+// achieved for the step to pass
+const minAchievedRatio = 0.95
+
+// rateSearch finds the highest total rate at which an objective holds for a
+// whole step
+type rateSearch struct {
+	objective  slo.Assertion
+	hold       time.Duration
+	minRate    int
+	maxRate    int
+	resolution int
+	steps      int
+	certified  int // Highest passing rate, 0 if none passed
+}
+
+// newRateSearch returns the rate search configured by cfg, or nil if none is
+func (r *BenchmarkRunner) newRateSearch() *rateSearch {
+	if r.config.SearchSLO == "" {
+		return nil
+	}
+
+	// The objective was checked by config.Validate
+	assertions, _ := slo.ParseAssertions(r.config.SearchSLO)
+	s := &rateSearch{
+		objective:  assertions[0],
+		hold:       r.config.SearchHold,
+		minRate:    r.config.SearchMinRate,
+		maxRate:    r.config.SearchMaxRate,
+		resolution: r.config.SearchResolution,
+	}
+	if s.resolution <= 0 {
+		s.resolution = max(s.maxRate/100, 1)
+	}
+	return s
+}
+
+// runSearch runs steps of the measured phase at different rates, bisecting
+// between the highest passing and lowest failing rate until they are within
+// the resolution
+func (r *BenchmarkRunner) runSearch(s *rateSearch) {
+	log.Printf("Searching for the highest rate in [%d, %d] ops/sec meeting %s for %v",
+		s.minRate, s.maxRate, s.objective.Text, s.hold)
+
+	if r.searchStep(s, s.maxRate) {
+		return
+	}
+	lo, hi := 0, s.maxRate
+	if s.minRate > 0 {
+		if !r.searchStep(s, s.minRate) {
+			return
+		}
+		lo = s.minRate
+	}
+	for hi-lo > s.resolution && r.ctx.Err() == nil {
+		mid := lo + (hi-lo)/2
+		if r.searchStep(s, mid) {
+			lo = mid
+		} else {
+			hi = mid
+		}
+	}
+}
+
+// searchStep holds the given rate for a step and reports whether the
+// objective held and the rate was achieved
+func (r *BenchmarkRunner) searchStep(s *rateSearch, opsPerSec int) bool {
+	if r.ctx.Err() != nil {
+		return false
+	}
+	s.steps++
+	name := fmt.Sprintf("step-%d", s.steps)
+
+	r.setTargetRate(opsPerSec)
+	start := time.Now()
+	r.collector.SetPhases([]string{name}, []time.Time{start})
+	r.runWorkers(s.hold, false)
+	r.collector.Flush()
+	seconds := time.Since(start).Seconds()
+
+	stats, aggregated, _ := r.collector.GetPhaseStats(name)
+	result := s.objective.Evaluate(stats, aggregated, seconds)
+	achieved := float64(aggregated.Count) / seconds
+
+	passed := result.Passed && achieved >= minAchievedRatio*float64(opsPerSec)
+	verdict := "FAIL"
+	if passed {
+		verdict = "PASS"
+		s.certified = max(s.certified, opsPerSec)
+	}
+	log.Printf("Search step %d: target %d ops/sec, achieved %.0f ops/sec, %s %s: %s",
+		s.steps, opsPerSec, achieved, s.objective.Metric, r.formatSLOActual(result), verdict)
+	return passed
+}
+
+// printSearch reports the certified rate of a search
+func (r *BenchmarkRunner) printSearch(s *rateSearch) {
+	if s.certified == 0 {
+		log.Printf("Certified Rate: none (no rate in [%d, %d] ops/sec met %s)", s.minRate, s.maxRate, s.objective.Text)
+		return
+	}
+	log.Printf("Certified Rate: %d ops/sec meeting %s for %v (%d steps)", s.certified, s.objective.Text, s.hold, s.steps)
+}
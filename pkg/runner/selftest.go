@@ -0,0 +1,150 @@
+package runner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"time"
+
+	"kvstore-benchmarker/pkg/collector"
+	"kvstore-benchmarker/pkg/config"
+	"kvstore-benchmarker/pkg/latency"
+	"kvstore-benchmarker/pkg/nullbackend"
+)
+
+// ErrSelfTestFailed is returned when the host cannot sustain the target rate
+// or the percentile engine is less accurate than the tolerance
+var ErrSelfTestFailed = errors.New("self-test failed")
+
+// selfTestSamples is the number of synthetic latencies of the accuracy check
+const selfTestSamples = 200000
+
+// SelfTestResult is the outcome of a self-test
+type SelfTestResult struct {
+	MaxRate      float64 // Highest rate in ops/sec the client generated against the null backend
+	TargetRate   int     // Rate the client had to sustain, 0 if not checked
+	AchievedRate float64 // Rate achieved at the target rate
+	Engine       string
+	Tolerance    float64   // Allowed relative error of percentiles
+	Percentiles  []float64 // Checked percentiles
+	Errors       []float64 // Relative error at each checked percentile
+}
+
+// RateMet reports whether the target rate was sustained
+func (r *SelfTestResult) RateMet() bool {
+	return r.TargetRate == 0 || r.AchievedRate >= minAchievedRatio*float64(r.TargetRate)
+}
+
+// AccuracyMet reports whether every checked percentile was within the tolerance
+func (r *SelfTestResult) AccuracyMet() bool {
+	for _, e := range r.Errors {
+		if e > r.Tolerance {
+			return false
+		}
+	}
+	return true
+}
+
+// SelfTestTolerance returns the default relative percentile error allowed for
+// an engine: HDR histograms keep three significant digits and exact
+// recorders fall back to them, sketches are less precise
+func SelfTestTolerance(engine string) float64 {
+	if engine == collector.EngineHDR || engine == collector.EngineExact {
+		return 0.01
+	}
+	return 0.05
+}
+
+// SelfTest benchmarks the client against an in-process null backend with the
+// connections, workers and operation mix of cfg: first unlimited for each
+// duration to find the highest rate this host can generate, then at the
+// target rate if one is given. It also checks the percentile engine against
+// exact percentiles of a synthetic latency distribution.
+func SelfTest(cfg *config.BenchmarkConfig, duration time.Duration, targetRate int, tolerance float64) (*SelfTestResult, error) {
+	server := nullbackend.NewServer(cfg.ValueSize)
+	if err := server.Start(); err != nil {
+		return nil, fmt.Errorf("failed to start null backend: %w", err)
+	}
+	defer server.Stop()
+
+	test := *cfg
+	test.TargetAddress = server.Address()
+	r, err := NewBenchmarkRunner(&test)
+	if err != nil {
+		return nil, err
+	}
+	defer r.cleanup()
+	r.collector.Start(r.ctx)
+
+	result := &SelfTestResult{
+		TargetRate:  targetRate,
+		Engine:      cfg.PercentileEngine,
+		Tolerance:   tolerance,
+		Percentiles: r.collector.Percentiles(),
+	}
+	result.MaxRate = r.selfTestPhase("max", duration, 0)
+	if targetRate > 0 {
+		result.AchievedRate = r.selfTestPhase("target", duration, targetRate)
+	}
+
+	result.Errors, err = percentileErrors(cfg, result.Percentiles)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// selfTestPhase runs the workers at a rate, 0 for unlimited, and returns the
+// achieved rate
+func (r *BenchmarkRunner) selfTestPhase(name string, duration time.Duration, opsPerSec int) float64 {
+	r.setTargetRate(opsPerSec)
+	start := time.Now()
+	r.collector.SetPhases([]string{name}, []time.Time{start})
+	r.runWorkers(duration, false)
+	r.collector.Flush()
+
+	_, aggregated, _ := r.collector.GetPhaseStats(name)
+	return float64(aggregated.Successes()) / time.Since(start).Seconds()
+}
+
+// percentileErrors records a synthetic log-uniform latency distribution from
+// 10µs to 100ms with the configured engine and returns the relative error of
+// each percentile against the exact value
+func percentileErrors(cfg *config.BenchmarkConfig, percentiles []float64) ([]float64, error) {
+	c, err := collector.NewCollector(collector.Options{
+		BufferSize:    cfg.ResultsBufferSize,
+		BlockWhenFull: true,
+		LatencyUnit:   latency.Unit(cfg.LatencyUnit),
+		Engine:        cfg.PercentileEngine,
+		Percentiles:   percentiles,
+		LatencyMax:    cfg.LatencyMax,
+		TrimPct:       cfg.TrimPct,
+	})
+	if err != nil {
+		return nil, err
+	}
+	c.Start(context.Background())
+	defer c.Stop()
+
+	rng := rand.New(rand.NewSource(1))
+	samples := make([]float64, selfTestSamples)
+	now := time.Now()
+	for i := range samples {
+		samples[i] = 0.01 * math.Pow(10, 4*rng.Float64())
+		c.AddResult(&collector.BenchmarkResult{Method: "SelfTest", LatencyMs: samples[i], Timestamp: now})
+	}
+	c.Flush()
+	sort.Float64s(samples)
+
+	stats := c.GetStats()["SelfTest"]
+	errs := make([]float64, len(percentiles))
+	for i, p := range percentiles {
+		rank := int(math.Ceil(p/100*float64(len(samples)))) - 1
+		exact := samples[max(rank, 0)]
+		errs[i] = math.Abs(stats.Percentile(i)-exact) / exact
+	}
+	return errs, nil
+}
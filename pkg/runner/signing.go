@@ -0,0 +1,156 @@
+package runner
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+
+	"kvstore-benchmarker/pkg/config"
+	"kvstore-benchmarker/pkg/kvclient"
+)
+
+// newSigner creates the request signer of the configuration, or returns nil
+// if requests are not signed. Surrounding whitespace of the secret file, such
+// as a trailing newline, is not part of the secret.
+func newSigner(cfg *config.BenchmarkConfig) (kvclient.Signer, error) {
+	if cfg.Sign == "" {
+		return nil, nil
+	}
+	var secret []byte
+	if cfg.SignSecretFile != "" {
+		data, err := os.ReadFile(cfg.SignSecretFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read signing secret: %w", err)
+		}
+		secret = bytes.TrimSpace(data)
+	}
+	return kvclient.NewSigner(cfg.Sign, cfg.SignKeyID, secret)
+}
+
+// newTokenProvider starts refreshing the bearer token of the configuration,
+// or returns nil if requests carry no token
+func newTokenProvider(cfg *config.BenchmarkConfig) (*kvclient.TokenProvider, error) {
+	var source kvclient.TokenSource
+	switch {
+	case cfg.TokenCommand != "":
+		source = kvclient.CommandTokenSource(cfg.TokenCommand)
+	case cfg.TokenFile != "":
+		source = kvclient.FileTokenSource(cfg.TokenFile)
+	case cfg.OAuth2TokenURL != "":
+		var secret []byte
+		if cfg.OAuth2ClientSecretFile != "" {
+			data, err := os.ReadFile(cfg.OAuth2ClientSecretFile)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read OAuth2 client secret: %w", err)
+			}
+			secret = bytes.TrimSpace(data)
+		}
+		var scopes []string
+		for _, scope := range strings.Split(cfg.OAuth2Scopes, ",") {
+			if scope = strings.TrimSpace(scope); scope != "" {
+				scopes = append(scopes, scope)
+			}
+		}
+		source = kvclient.ClientCredentialsTokenSource(cfg.OAuth2TokenURL, cfg.OAuth2ClientID, string(secret), scopes)
+	default:
+		return nil, nil
+	}
+
+	tokens, err := kvclient.NewTokenProvider(source, cfg.TokenRefresh)
+	if err != nil {
+		return nil, fmt.Errorf("failed to obtain bearer token: %w", err)
+	}
+	return tokens, nil
+}
+
+// newMetadataFile starts rereading the metadata file of the configuration, or
+// returns nil if requests carry no extra metadata
+func newMetadataFile(cfg *config.BenchmarkConfig) (*kvclient.MetadataFile, error) {
+	if cfg.MetadataFile == "" {
+		return nil, nil
+	}
+	return kvclient.NewMetadataFile(cfg.MetadataFile, cfg.TokenRefresh)
+}
+
+// requestCredentials are sent with every request to the target: a signature,
+// a bearer token and the metadata of a file, each nil if not configured
+type requestCredentials struct {
+	signer   kvclient.Signer
+	tokens   *kvclient.TokenProvider
+	metadata *kvclient.MetadataFile
+}
+
+// credentialed is a client or connection pool that sends request credentials
+type credentialed interface {
+	SetSigner(signer kvclient.Signer)
+	SetTokens(tokens *kvclient.TokenProvider)
+	SetMetadata(metadata *kvclient.MetadataFile)
+}
+
+// newRequestCredentials creates the request credentials of the configuration
+// and starts refreshing them
+func newRequestCredentials(cfg *config.BenchmarkConfig) (*requestCredentials, error) {
+	signer, err := newSigner(cfg)
+	if err != nil {
+		return nil, err
+	}
+	tokens, err := newTokenProvider(cfg)
+	if err != nil {
+		return nil, err
+	}
+	metadata, err := newMetadataFile(cfg)
+	if err != nil {
+		if tokens != nil {
+			tokens.Stop()
+		}
+		return nil, err
+	}
+	return &requestCredentials{signer: signer, tokens: tokens, metadata: metadata}, nil
+}
+
+// apply sends the credentials with every subsequent request of c
+func (rc *requestCredentials) apply(c credentialed) {
+	c.SetSigner(rc.signer)
+	if rc.tokens != nil {
+		c.SetTokens(rc.tokens)
+	}
+	if rc.metadata != nil {
+		c.SetMetadata(rc.metadata)
+	}
+}
+
+// stop stops refreshing the credentials
+func (rc *requestCredentials) stop() {
+	if rc.tokens != nil {
+		rc.tokens.Stop()
+	}
+	if rc.metadata != nil {
+		rc.metadata.Stop()
+	}
+}
+
+// NewClient connects a client to the target of the configuration with the
+// connection options and request credentials of the benchmark's own
+// connections, e.g. for the interactive shell. The returned function closes
+// the client and stops refreshing its credentials.
+func NewClient(cfg *config.BenchmarkConfig) (*kvclient.Client, func(), error) {
+	opts, err := clientOptions(cfg)
+	if err != nil {
+		return nil, nil, err
+	}
+	creds, err := newRequestCredentials(cfg)
+	if err != nil {
+		return nil, nil, err
+	}
+	client, err := kvclient.NewClient(cfg.TargetAddress, opts)
+	if err != nil {
+		creds.stop()
+		return nil, nil, err
+	}
+	creds.apply(client)
+	return client, func() {
+		client.Close()
+		creds.stop()
+	}, nil
+}
@@ -0,0 +1,150 @@
+package runner
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"time"
+
+	"kvstore-benchmarker/pkg/collector"
+	"kvstore-benchmarker/pkg/slo"
+)
+
+// ErrSLOViolated is returned by Run when the benchmark completed but at least
+// one SLO assertion failed
+var ErrSLOViolated = errors.New("SLO assertions failed")
+
+// startPhases splits the results of the measured phase, which starts at the
+// given time, into the configured phases
+func (r *BenchmarkRunner) startPhases(start time.Time) {
+	if len(r.phases) == 0 {
+		return
+	}
+
+	// A resumed run starts part way through the measured phase
+	start = start.Add(-r.resumeAt)
+	names := make([]string, len(r.phases))
+	starts := make([]time.Time, len(r.phases))
+	for i, phase := range r.phases {
+		names[i] = phase.Name
+		starts[i] = start.Add(phase.Offset)
+	}
+	r.phaseStarts = starts
+	r.collector.SetPhases(names, starts)
+}
+
+// evaluateSLOs evaluates the SLO assertions against the whole run or their
+// phase, which lasts until the next phase starts or the measured phase ends
+func (r *BenchmarkRunner) evaluateSLOs(end time.Time) {
+	var sloResults []slo.Result
+	for _, a := range r.assertions {
+		if a.Phase == slo.RunPhase {
+			sloResults = append(sloResults, a.Evaluate(r.collector.GetStats(), r.collector.GetAggregatedStats(), r.collector.Measured().Seconds()))
+			continue
+		}
+
+		for i, phase := range r.phases {
+			if phase.Name != a.Phase {
+				continue
+			}
+			phaseEnd := end
+			if i+1 < len(r.phaseStarts) && r.phaseStarts[i+1].Before(end) {
+				phaseEnd = r.phaseStarts[i+1]
+			}
+			seconds := max(phaseEnd.Sub(r.phaseStarts[i]).Seconds(), 0)
+			stats, aggregated, _ := r.collector.GetPhaseStats(phase.Name)
+			sloResults = append(sloResults, a.Evaluate(stats, aggregated, seconds))
+		}
+	}
+	r.outcomeMu.Lock()
+	r.sloResults = sloResults
+	r.outcomeMu.Unlock()
+}
+
+// sloFailures returns the number of failed SLO assertions
+func (r *BenchmarkRunner) sloFailures() int {
+	failed := 0
+	for _, result := range r.sloResults {
+		if !result.Passed {
+			failed++
+		}
+	}
+	return failed
+}
+
+// printSLOs prints the outcome of every SLO assertion
+func (r *BenchmarkRunner) printSLOs(out io.Writer, color bool) {
+	if len(r.sloResults) == 0 {
+		return
+	}
+
+	table := newTextTable("Phase", "Assertion", "Actual", "Result")
+	for _, result := range r.sloResults {
+		verdict := tableCell{text: "PASS", style: ansiGreen}
+		if !result.Passed {
+			verdict = tableCell{text: "FAIL", style: ansiRed}
+		}
+		table.addRow(
+			tableCell{text: result.Phase},
+			tableCell{text: result.Text},
+			tableCell{text: r.formatSLOActual(result)},
+			verdict,
+		)
+	}
+
+	fmt.Fprintf(out, "=== SLO ===\n\n")
+	table.render(out, color)
+	fmt.Fprintln(out)
+
+	if failed := r.sloFailures(); failed > 0 {
+		log.Printf("SLO: %d of %d assertions failed", failed, len(r.sloResults))
+	}
+}
+
+// formatSLOActual renders the measured value of an evaluated assertion
+func (r *BenchmarkRunner) formatSLOActual(result slo.Result) string {
+	unit := r.unit()
+	switch {
+	case result.NoData:
+		return "no data"
+	case result.IsLatency():
+		return unit.Value(result.Actual) + " " + unit.Name()
+	case result.Metric == "error_rate":
+		return fmt.Sprintf("%.2f%%", result.Actual)
+	default:
+		return fmt.Sprintf("%.0f ops/s", result.Actual)
+	}
+}
+
+// printConformance prints the share of operations of each method that met
+// its latency SLO
+func (r *BenchmarkRunner) printConformance(out io.Writer, color bool, stats map[string]collector.Stats, methods []string) {
+	if !r.collector.HasLatencySLOs() {
+		return
+	}
+
+	unit := r.unit()
+	table := newTextTable("Method", "Threshold", "Met%", "Violations")
+	for _, method := range methods {
+		stat := stats[method]
+		conformance := stat.SLOConformance()
+		if conformance < 0 {
+			continue
+		}
+		style := ansiGreen
+		if stat.SLOViolations > 0 {
+			style = ansiRed
+		}
+		table.addRow(
+			tableCell{text: method},
+			tableCell{text: unit.Display(stat.SLOThreshold)},
+			tableCell{text: fmt.Sprintf("%.2f", conformance), style: style},
+			tableCell{text: fmt.Sprintf("%d", stat.SLOViolations), style: style},
+		)
+	}
+
+	fmt.Fprintf(out, "=== LATENCY SLO CONFORMANCE ===\n\n")
+	table.render(out, color)
+	fmt.Fprintln(out)
+}
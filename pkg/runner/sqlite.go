@@ -0,0 +1,232 @@
+package runner
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"kvstore-benchmarker/pkg/collector"
+)
+
+// sqliteCommand is the SQLite shell results are written with
+const sqliteCommand = "sqlite3"
+
+// sqliteSchemaVersion is stored as the database's user_version; it changes
+// only when existing tables or columns change incompatibly
+const sqliteSchemaVersion = 1
+
+// sqliteSchema creates the results tables of a --sqlite database. Every table
+// is keyed by run ID, so runs are appended and a rerun of a run ID (e.g. after
+// --resume) replaces its rows. Latencies are in milliseconds and times are
+// UTC in SQLite's "YYYY-MM-DD HH:MM:SS.SSS" format.
+const sqliteSchema = `CREATE TABLE IF NOT EXISTS runs (
+	run_id TEXT PRIMARY KEY,
+	started_at TEXT NOT NULL,
+	ended_at TEXT NOT NULL,
+	duration_s REAL,
+	throughput_ops REAL,
+	target TEXT NOT NULL,
+	notes TEXT NOT NULL,
+	config TEXT NOT NULL
+);
+CREATE INDEX IF NOT EXISTS runs_started_at ON runs (started_at);
+CREATE TABLE IF NOT EXISTS run_labels (
+	run_id TEXT NOT NULL,
+	name TEXT NOT NULL,
+	value TEXT NOT NULL,
+	PRIMARY KEY (run_id, name)
+);
+CREATE TABLE IF NOT EXISTS method_stats (
+	run_id TEXT NOT NULL,
+	method TEXT NOT NULL,
+	ops INTEGER NOT NULL,
+	errors INTEGER NOT NULL,
+	throttled INTEGER NOT NULL,
+	dropped INTEGER NOT NULL,
+	throughput_ops REAL,
+	avg_ms REAL,
+	min_ms REAL,
+	max_ms REAL,
+	PRIMARY KEY (run_id, method)
+);
+CREATE TABLE IF NOT EXISTS method_percentiles (
+	run_id TEXT NOT NULL,
+	method TEXT NOT NULL,
+	percentile REAL NOT NULL,
+	latency_ms REAL,
+	PRIMARY KEY (run_id, method, percentile)
+);
+CREATE TABLE IF NOT EXISTS intervals (
+	run_id TEXT NOT NULL,
+	method TEXT NOT NULL,
+	offset_s REAL NOT NULL,
+	ended_at TEXT NOT NULL,
+	ops INTEGER NOT NULL,
+	errors INTEGER NOT NULL,
+	throttled INTEGER NOT NULL,
+	throughput_ops REAL,
+	avg_ms REAL,
+	max_ms REAL,
+	PRIMARY KEY (run_id, method, offset_s)
+);
+CREATE TABLE IF NOT EXISTS interval_percentiles (
+	run_id TEXT NOT NULL,
+	method TEXT NOT NULL,
+	offset_s REAL NOT NULL,
+	percentile REAL NOT NULL,
+	latency_ms REAL,
+	PRIMARY KEY (run_id, method, offset_s, percentile)
+);
+`
+
+// sqliteTables are the tables holding rows of a run, children first
+var sqliteTables = []string{"interval_percentiles", "intervals", "method_percentiles", "method_stats", "run_labels", "runs"}
+
+// intervalLog is a sink keeping the per-method statistics of every report
+// interval for the --sqlite database
+type intervalLog struct {
+	mu        sync.Mutex
+	intervals []collector.SinkStats
+}
+
+// Start implements Sink; the log starts empty
+func (l *intervalLog) Start(time.Time) error { return nil }
+
+// RecordInterval keeps the statistics of an interval, without tag combinations
+func (l *intervalLog) RecordInterval(interval collector.SinkStats) error {
+	if len(interval.Methods) == 0 {
+		return nil
+	}
+	interval.Tagged = nil
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.intervals = append(l.intervals, interval)
+	return nil
+}
+
+// RecordFinal implements Sink; the final statistics come from the report
+func (l *intervalLog) RecordFinal(collector.SinkStats) error { return nil }
+
+// Close implements Sink; the intervals stay available
+func (l *intervalLog) Close() error { return nil }
+
+// snapshot returns the intervals recorded so far
+func (l *intervalLog) snapshot() []collector.SinkStats {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return append([]collector.SinkStats(nil), l.intervals...)
+}
+
+// checkSQLite fails early when the SQLite shell needed for --sqlite is missing
+func checkSQLite() error {
+	if _, err := exec.LookPath(sqliteCommand); err != nil {
+		return fmt.Errorf("--sqlite requires the %s command-line shell: %w", sqliteCommand, err)
+	}
+	return nil
+}
+
+// writeSQLite appends the run's configuration, final per-method statistics
+// and interval series to the SQLite database at path, creating the database
+// and its tables if needed. The rows are written in a single transaction.
+func (r *BenchmarkRunner) writeSQLite(path string) error {
+	report := r.report()
+	config, err := json.Marshal(r.config)
+	if err != nil {
+		return fmt.Errorf("failed to encode configuration: %w", err)
+	}
+	run := sqlText(report.RunID)
+	percentiles := r.collector.Percentiles()
+
+	var b strings.Builder
+	b.WriteString(".bail on\nBEGIN;\n")
+	b.WriteString(sqliteSchema)
+	fmt.Fprintf(&b, "PRAGMA user_version = %d;\n", sqliteSchemaVersion)
+	for _, table := range sqliteTables {
+		fmt.Fprintf(&b, "DELETE FROM %s WHERE run_id = %s;\n", table, run)
+	}
+
+	fmt.Fprintf(&b, "INSERT INTO runs VALUES (%s, %s, %s, %s, %s, %s, %s, %s);\n",
+		run, sqlTime(report.Start), sqlTime(report.End), sqlReal(report.Duration.Seconds()), sqlReal(report.Throughput),
+		sqlText(r.config.TargetAddress), sqlText(report.Notes), sqlText(string(config)))
+	for _, label := range r.collector.Labels() {
+		fmt.Fprintf(&b, "INSERT INTO run_labels VALUES (%s, %s, %s);\n", run, sqlText(label.Name), sqlText(label.Value))
+	}
+
+	dropped := r.collector.DroppedByMethod()
+	elapsed := report.Duration.Seconds()
+	writeStats := func(stats collector.Stats, dropped int64) {
+		var throughput float64
+		if elapsed > 0 {
+			throughput = float64(stats.Successes()) / elapsed
+		}
+		method := sqlText(stats.Method)
+		fmt.Fprintf(&b, "INSERT INTO method_stats VALUES (%s, %s, %d, %d, %d, %d, %s, %s, %s, %s);\n",
+			run, method, stats.Count, stats.ErrorCount, stats.Throttled, dropped, sqlReal(throughput),
+			sqlReal(stats.AvgLatency), sqlReal(stats.MinLatency), sqlReal(stats.MaxLatency))
+		for i, p := range percentiles {
+			fmt.Fprintf(&b, "INSERT INTO method_percentiles VALUES (%s, %s, %s, %s);\n",
+				run, method, sqlReal(p), sqlReal(stats.Percentile(i)))
+		}
+	}
+	for _, stats := range report.Methods {
+		writeStats(stats, dropped[stats.Method])
+	}
+	if report.Aggregated.Count > 0 {
+		writeStats(report.Aggregated, r.collector.MeasuredDropped())
+	}
+
+	writeInterval := func(interval collector.SinkStats, stats collector.Stats) {
+		method := sqlText(stats.Method)
+		offset := sqlReal(interval.Offset.Seconds())
+		fmt.Fprintf(&b, "INSERT INTO intervals VALUES (%s, %s, %s, %s, %d, %d, %d, %s, %s, %s);\n",
+			run, method, offset, sqlTime(interval.End), stats.Count, stats.ErrorCount, stats.Throttled,
+			sqlReal(interval.Throughput(stats)), sqlReal(stats.AvgLatency), sqlReal(stats.MaxLatency))
+		for i, p := range percentiles {
+			fmt.Fprintf(&b, "INSERT INTO interval_percentiles VALUES (%s, %s, %s, %s, %s);\n",
+				run, method, offset, sqlReal(p), sqlReal(stats.Percentile(i)))
+		}
+	}
+	if r.intervalLog != nil {
+		for _, interval := range r.intervalLog.snapshot() {
+			for _, stats := range interval.Methods {
+				writeInterval(interval, stats)
+			}
+			writeInterval(interval, interval.Aggregated)
+		}
+	}
+	b.WriteString("COMMIT;\n")
+
+	cmd := exec.Command(sqliteCommand, path)
+	cmd.Stdin = strings.NewReader(b.String())
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("failed to write results database %s: %w: %s", path, err, strings.TrimSpace(stderr.String()))
+	}
+	return nil
+}
+
+// sqlText quotes a string as an SQL literal
+func sqlText(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
+// sqlReal renders a float as an SQL literal, NULL if it is not finite
+func sqlReal(v float64) string {
+	if math.IsNaN(v) || math.IsInf(v, 0) {
+		return "NULL"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+// sqlTime renders a time as an SQL literal in UTC with millisecond precision
+func sqlTime(t time.Time) string {
+	return sqlText(t.UTC().Format("2006-01-02 15:04:05.000"))
+}
@@ -0,0 +1,128 @@
+package runner
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"kvstore-benchmarker/pkg/manifest"
+)
+
+// summaryField is a named headline number of the run
+type summaryField struct {
+	key   string
+	value string
+}
+
+// summaryFields returns the headline numbers of the run in a fixed order
+func (r *BenchmarkRunner) summaryFields() []summaryField {
+	unit := r.unit()
+	aggregated := r.collector.GetAggregatedStats()
+	elapsed := r.collector.Measured().Seconds()
+
+	var throughput float64
+	if elapsed > 0 {
+		throughput = float64(aggregated.Count) / elapsed
+	}
+
+	latencyKey := func(name string) string {
+		return name + "_" + unit.Name()
+	}
+
+	fields := []summaryField{
+		{"run_id", r.config.RunID},
+		{"duration_s", fmt.Sprintf("%.3f", elapsed)},
+		{"ramp_s", fmt.Sprintf("%.3f", r.rampEnd.Seconds())},
+		{"gap_s", fmt.Sprintf("%.3f", r.gapTotal().Seconds())},
+		{"ops", fmt.Sprintf("%d", aggregated.Count)},
+		{"errors", fmt.Sprintf("%d", aggregated.ErrorCount)},
+		{"error_rate_pct", fmt.Sprintf("%.2f", aggregated.ErrorRate)},
+		{"dropped", fmt.Sprintf("%d", r.collector.MeasuredDropped())},
+		{"slo_failed", fmt.Sprintf("%d", r.sloFailures())},
+		{"throughput_ops", fmt.Sprintf("%.0f", throughput)},
+		{latencyKey("avg"), unit.Value(aggregated.AvgLatency)},
+		{latencyKey("p50"), unit.Value(aggregated.P50Latency)},
+		{latencyKey("p95"), unit.Value(aggregated.P95Latency)},
+		{latencyKey("p99"), unit.Value(aggregated.P99Latency)},
+		{latencyKey("p999"), unit.Value(aggregated.P999Latency)},
+		{latencyKey("min"), unit.Value(aggregated.MinLatency)},
+		{latencyKey("max"), unit.Value(aggregated.MaxLatency)},
+		{latencyKey("trimmed_mean"), unit.Value(aggregated.TrimmedMean)},
+		{latencyKey("mad"), unit.Value(aggregated.MAD)},
+	}
+
+	// Optional fields are appended, keeping the other positions fixed
+	if aggregated.Throttled > 0 {
+		fields = append(fields, summaryField{"throttled", fmt.Sprintf("%d", aggregated.Throttled)})
+	}
+	if r.search != nil {
+		fields = append(fields, summaryField{"certified_ops", fmt.Sprintf("%d", r.search.certified)})
+	}
+	if r.energy != nil {
+		fields = append(fields, summaryField{"ops_per_joule", fmt.Sprintf("%.2f", r.opsPerJoule())})
+	}
+	if r.config.CostPerHour > 0 {
+		fields = append(fields, summaryField{"cost_per_million_ops", fmt.Sprintf("%.4f", costPerMillion(r.config.CostPerHour, throughput))})
+	}
+	return fields
+}
+
+// costPerMillion returns the cost of a million operations at the given
+// throughput in ops/sec, with infrastructure costing costPerHour
+func costPerMillion(costPerHour, throughput float64) float64 {
+	if throughput <= 0 {
+		return 0
+	}
+	return costPerHour / (throughput * 3600) * 1e6
+}
+
+// writeManifest writes the run manifest with the headline numbers
+func (r *BenchmarkRunner) writeManifest(path string) error {
+	summary := make(map[string]string)
+	for _, f := range r.summaryFields() {
+		summary[f.key] = f.value
+	}
+	labels := make(map[string]string)
+	for _, label := range r.collector.Labels() {
+		labels[label.Name] = label.Value
+	}
+	return manifest.Write(path, &manifest.Manifest{
+		RunID:   r.config.RunID,
+		Labels:  labels,
+		Notes:   r.config.Notes,
+		Start:   r.startTime,
+		End:     time.Now(),
+		Config:  r.config.String(),
+		Summary: summary,
+		Build:   manifest.CollectBuild(),
+		Client:  manifest.CollectClient(),
+	})
+}
+
+// writeSummary writes the headline numbers in the given format: "kv" writes a
+// single key=value line, "tsv" a header line followed by a value line
+func (r *BenchmarkRunner) writeSummary(w io.Writer, format string) error {
+	fields := r.summaryFields()
+
+	switch format {
+	case "kv":
+		parts := make([]string, len(fields))
+		for i, f := range fields {
+			parts[i] = f.key + "=" + f.value
+		}
+		_, err := fmt.Fprintln(w, strings.Join(parts, " "))
+		return err
+	case "tsv":
+		keys := make([]string, len(fields))
+		values := make([]string, len(fields))
+		for i, f := range fields {
+			keys[i] = f.key
+			values[i] = f.value
+		}
+		_, err := fmt.Fprintf(w, "%s\n%s\n", strings.Join(keys, "\t"), strings.Join(values, "\t"))
+		return err
+	default:
+		return nil
+	}
+}
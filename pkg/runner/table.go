@@ -0,0 +1,153 @@
+package runner
+
+import (
+	"io"
+	"os"
+	"strings"
+)
+
+// ANSI escape sequences used by the color mode
+const (
+	ansiReset = "\033[0m"
+	ansiBold  = "\033[1m"
+	ansiRed   = "\033[31m"
+	ansiGreen = "\033[32m"
+)
+
+// tableCell is a table cell with an optional ANSI style
+type tableCell struct {
+	text  string
+	style string
+}
+
+// textTable renders rows as aligned columns: the first column is left-aligned,
+// all others are right-aligned
+type textTable struct {
+	header []string
+	rows   [][]tableCell
+}
+
+// newTextTable creates a table with the given column headers
+func newTextTable(header ...string) *textTable {
+	return &textTable{header: header}
+}
+
+// addRow appends a row of cells
+func (t *textTable) addRow(cells ...tableCell) {
+	t.rows = append(t.rows, cells)
+}
+
+// render writes the table, styling cells only when color is enabled
+func (t *textTable) render(w io.Writer, color bool) {
+	widths := make([]int, len(t.header))
+	for i, h := range t.header {
+		widths[i] = len(h)
+	}
+	for _, row := range t.rows {
+		for i, cell := range row {
+			if i < len(widths) && len(cell.text) > widths[i] {
+				widths[i] = len(cell.text)
+			}
+		}
+	}
+
+	var b strings.Builder
+	writeRow := func(cells []tableCell) {
+		for i, cell := range cells {
+			if i > 0 {
+				b.WriteString("  ")
+			}
+			pad := strings.Repeat(" ", widths[i]-len(cell.text))
+			if i > 0 {
+				b.WriteString(pad)
+			}
+			if color && cell.style != "" {
+				b.WriteString(cell.style + cell.text + ansiReset)
+			} else {
+				b.WriteString(cell.text)
+			}
+			if i == 0 && i < len(cells)-1 {
+				b.WriteString(pad)
+			}
+		}
+		b.WriteString("\n")
+	}
+
+	header := make([]tableCell, len(t.header))
+	total := 0
+	for i, h := range t.header {
+		header[i] = tableCell{text: h, style: ansiBold}
+		total += widths[i]
+	}
+	writeRow(header)
+	b.WriteString(strings.Repeat("-", total+2*(len(widths)-1)) + "\n")
+	for _, row := range t.rows {
+		writeRow(row)
+	}
+
+	io.WriteString(w, b.String())
+}
+
+// renderMarkdown writes the table as a GitHub-flavored Markdown table with
+// the same alignment as render; bold cells are emphasized, other styles dropped
+func (t *textTable) renderMarkdown(w io.Writer) {
+	var b strings.Builder
+	writeRow := func(cells []string) {
+		b.WriteString("|")
+		for _, cell := range cells {
+			b.WriteString(" " + strings.ReplaceAll(cell, "|", "\\|") + " |")
+		}
+		b.WriteString("\n")
+	}
+
+	writeRow(t.header)
+	align := make([]string, len(t.header))
+	for i := range align {
+		align[i] = "---:"
+	}
+	if len(align) > 0 {
+		align[0] = ":---"
+	}
+	writeRow(align)
+	for _, row := range t.rows {
+		cells := make([]string, len(row))
+		for i, cell := range row {
+			cells[i] = cell.text
+			if cell.style == ansiBold && cell.text != "" {
+				cells[i] = "**" + cell.text + "**"
+			}
+		}
+		writeRow(cells)
+	}
+
+	io.WriteString(w, b.String())
+}
+
+// colorEnabled resolves a color mode ("auto", "always" or "never") for a
+// writer; auto enables color on terminals unless NO_COLOR is set
+func colorEnabled(mode string, w io.Writer) bool {
+	switch mode {
+	case "always":
+		return true
+	case "never":
+		return false
+	}
+
+	if os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+	return isTerminal(w)
+}
+
+// isTerminal reports whether w is a terminal
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
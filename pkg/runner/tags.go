@@ -0,0 +1,92 @@
+package runner
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"kvstore-benchmarker/pkg/collector"
+)
+
+// Payload size classes of the {size_class} tag
+const (
+	sizeClassSmallMax  = 1 << 10  // Up to 1 KiB
+	sizeClassMediumMax = 64 << 10 // Up to 64 KiB, larger is "large"
+)
+
+// opTagger builds the tag combination of each operation from static tags and
+// tags derived from the key and payload
+type opTagger struct {
+	tags    []collector.Tag // Sorted by name
+	tenants int
+}
+
+// newOpTagger returns a tagger for a tag list checked by config.Validate, or
+// nil if no tags are configured
+func newOpTagger(list string, tenants int) *opTagger {
+	tags, _ := collector.ParseTags(list)
+	if len(tags) == 0 {
+		return nil
+	}
+	return &opTagger{tags: tags, tenants: max(tenants, 1)}
+}
+
+// tagsOf returns the tag combination of an operation on the key at keyIndex
+// that transferred the given payload bytes
+func (t *opTagger) tagsOf(keyIndex int, payload int64) string {
+	var b strings.Builder
+	for i, tag := range t.tags {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(tag.Name)
+		b.WriteByte('=')
+		switch tag.Value {
+		case collector.TagTenant:
+			// Tenants are assigned round-robin, as in composite keys
+			b.WriteString(strconv.Itoa(keyIndex % t.tenants))
+		case collector.TagSizeClass:
+			b.WriteString(sizeClass(payload))
+		default:
+			b.WriteString(tag.Value)
+		}
+	}
+	return b.String()
+}
+
+// sizeClass classifies an operation by its key and value bytes
+func sizeClass(payload int64) string {
+	switch {
+	case payload <= sizeClassSmallMax:
+		return "small"
+	case payload <= sizeClassMediumMax:
+		return "medium"
+	default:
+		return "large"
+	}
+}
+
+// printTagged prints the statistics of every tag combination of the run
+func (r *BenchmarkRunner) printTagged(out io.Writer, color bool) {
+	groups := r.collector.GetTaggedStats()
+	if len(groups) == 0 {
+		return
+	}
+
+	header := []string{"Tags", "Method", "Count", "Errors", "Error%", "Avg"}
+	for _, p := range r.collector.Percentiles() {
+		header = append(header, collector.PercentileLabel(p))
+	}
+	table := newTextTable(append(header, "Min", "Max")...)
+	for _, group := range groups {
+		for _, stat := range group.Methods {
+			table.addRow(append([]tableCell{{text: group.Tags}}, r.statsRow(stat, "")...)...)
+		}
+		table.addRow(append([]tableCell{{text: group.Tags, style: ansiBold}}, r.statsRow(group.Aggregated, ansiBold)...)...)
+	}
+
+	fmt.Fprintf(out, "=== RESULTS BY TAGS (latencies in %s) ===\n\n", r.unit().Name())
+	table.render(out, color)
+	fmt.Fprintln(out)
+}
@@ -0,0 +1,95 @@
+package runner
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"kvstore-benchmarker/pkg/config"
+	"kvstore-benchmarker/pkg/kvclient"
+)
+
+// TenantProfile holds the credentials of one simulated tenant
+type TenantProfile struct {
+	Tenant int `yaml:"tenant"`
+	// gRPC metadata sent with the tenant's requests; values expand
+	// environment variables, so secrets can stay out of the file
+	Metadata map[string]string `yaml:"metadata"`
+	// Signing key of the tenant, used with the --sign scheme
+	SignKeyID      string `yaml:"sign_key_id"`
+	SignSecretFile string `yaml:"sign_secret_file"`
+}
+
+// loadTenantIdentities loads the tenant profiles of a YAML (or JSON) file and
+// returns the identity of every tenant by index, nil for tenants without a
+// profile. Tenants are assigned to keys round-robin, as in composite keys.
+func loadTenantIdentities(path string, cfg *config.BenchmarkConfig) ([]*kvclient.Identity, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read tenant profiles: %w", err)
+	}
+	var profiles []TenantProfile
+	if err := yaml.Unmarshal(data, &profiles); err != nil {
+		return nil, fmt.Errorf("failed to parse tenant profiles: %w", err)
+	}
+	if len(profiles) == 0 {
+		return nil, fmt.Errorf("tenant profiles %s define no tenants", path)
+	}
+
+	identities := make([]*kvclient.Identity, max(cfg.KeyTenants, 1))
+	for _, profile := range profiles {
+		if profile.Tenant < 0 || profile.Tenant >= len(identities) {
+			return nil, fmt.Errorf("tenant profile %d: tenant must be between 0 and %d", profile.Tenant, len(identities)-1)
+		}
+		if identities[profile.Tenant] != nil {
+			return nil, fmt.Errorf("tenant profile %d: defined twice", profile.Tenant)
+		}
+		id, err := newTenantIdentity(profile, cfg.Sign)
+		if err != nil {
+			return nil, fmt.Errorf("tenant profile %d: %w", profile.Tenant, err)
+		}
+		identities[profile.Tenant] = id
+	}
+	return identities, nil
+}
+
+// newTenantIdentity creates the identity of a tenant profile, signing with
+// the given scheme if the profile has a signing key
+func newTenantIdentity(profile TenantProfile, scheme string) (*kvclient.Identity, error) {
+	id := &kvclient.Identity{}
+
+	// Sorted for a stable metadata order; gRPC metadata keys are lowercase
+	names := make([]string, 0, len(profile.Metadata))
+	for name := range profile.Metadata {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		id.Metadata = append(id.Metadata, strings.ToLower(name), os.ExpandEnv(profile.Metadata[name]))
+	}
+
+	if profile.SignKeyID == "" && profile.SignSecretFile == "" {
+		return id, nil
+	}
+	if scheme == "" {
+		return nil, fmt.Errorf("signing keys require a --sign scheme")
+	}
+	var secret []byte
+	if profile.SignSecretFile != "" {
+		data, err := os.ReadFile(profile.SignSecretFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read signing secret: %w", err)
+		}
+		secret = bytes.TrimSpace(data)
+	}
+	signer, err := kvclient.NewSigner(scheme, profile.SignKeyID, secret)
+	if err != nil {
+		return nil, err
+	}
+	id.Signer = signer
+	return id, nil
+}
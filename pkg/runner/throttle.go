@@ -0,0 +1,122 @@
+package runner
+
+import (
+	"context"
+	"log"
+	"sync/atomic"
+	"time"
+
+	"kvstore-benchmarker/pkg/collector"
+)
+
+// retryTracker measures how well workers adhere to the retry delays servers
+// suggest when throttling them: a worker adheres if its next operation
+// starts no earlier than the suggested delay after the throttle
+type retryTracker struct {
+	hinted  atomic.Int64 // Throttles with a suggested delay followed by another operation
+	adhered atomic.Int64 // Those followed only after the delay
+}
+
+// pendingRetry is the last throttle with a suggested delay of a worker, zero
+// once the worker's next operation was accounted
+type pendingRetry struct {
+	at    time.Time
+	delay time.Duration
+}
+
+// observe accounts the operation a worker starts at now after a pending throttle
+func (t *retryTracker) observe(pending *pendingRetry, now time.Time) {
+	if pending.delay == 0 {
+		return
+	}
+	t.hinted.Add(1)
+	if now.Sub(pending.at) >= pending.delay {
+		t.adhered.Add(1)
+	}
+	*pending = pendingRetry{}
+}
+
+// adherence returns the number of suggested delays observed and the
+// percentage of them that workers waited out, -1 without any
+func (t *retryTracker) adherence() (int64, float64) {
+	hinted := t.hinted.Load()
+	if hinted == 0 {
+		return 0, -1
+	}
+	return hinted, float64(t.adhered.Load()) / float64(hinted) * 100
+}
+
+// backoff is the exponential backoff of a throttled worker
+type backoff struct {
+	base, max time.Duration
+	next      time.Duration // Delay after the next throttle, 0 before the first
+}
+
+// delay returns the wait after another consecutive throttle: the base delay
+// doubled for every throttle before, capped at max, of which a random half
+// is jittered so that workers throttled together do not retry in lockstep
+func (b *backoff) delay() time.Duration {
+	if b.base <= 0 {
+		return 0
+	}
+	if b.next == 0 {
+		b.next = b.base
+	}
+	d := b.next
+	b.next = min(2*b.next, b.max)
+	return d/2 + jitter(d/2)
+}
+
+// reset starts the backoff over after an operation that was not throttled
+func (b *backoff) reset() {
+	b.next = 0
+}
+
+// throttleDelay returns how long a worker waits before its next operation
+// after one that the server throttled or not, suggesting the retry delay hint
+func (r *BenchmarkRunner) throttleDelay(b *backoff, throttled bool, hint time.Duration) time.Duration {
+	if !throttled {
+		b.reset()
+		return 0
+	}
+	delay := b.delay()
+	if r.config.HonorRetryAfter {
+		delay = max(delay, hint)
+	}
+	return delay
+}
+
+// waitThrottled waits out the delay after a throttle, and returns false if
+// ctx was cancelled meanwhile
+func waitThrottled(ctx context.Context, delay time.Duration) bool {
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
+
+// logThrottling logs how many operations the server throttled, the retry
+// delays it suggested and whether workers waited them out
+func (r *BenchmarkRunner) logThrottling(aggregated collector.Stats) {
+	if aggregated.Throttled == 0 {
+		return
+	}
+	unit := r.unit()
+	log.Printf("Throttled: %d operations (%.2f%% of all, RESOURCE_EXHAUSTED, not counted as errors)",
+		aggregated.Throttled, aggregated.ThrottleRate())
+	if elapsed := r.collector.Measured().Seconds(); elapsed > 0 {
+		log.Printf("Offered Load: %.0f ops/sec, admitted %.0f ops/sec",
+			float64(aggregated.Count)/elapsed, float64(aggregated.Admitted())/elapsed)
+	}
+	if aggregated.RetryAfterHinted > 0 {
+		log.Printf("Suggested Retry-After: %d throttles, avg %s, max %s",
+			aggregated.RetryAfterHinted, unit.Display(aggregated.RetryAfterAvg), unit.Display(aggregated.RetryAfterMax))
+	}
+	if observed, pct := r.retries.adherence(); pct >= 0 {
+		log.Printf("Retry-After Adherence: %.2f%% of %d following operations started after the suggested delay", pct, observed)
+	}
+}
@@ -0,0 +1,155 @@
+package runner
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/time/rate"
+	"gopkg.in/yaml.v3"
+)
+
+// TimelineStep sets the target rate and worker count from Offset until the next step
+type TimelineStep struct {
+	Offset  time.Duration `yaml:"offset"`
+	Rate    int           `yaml:"rate"`    // Total ops/sec, 0 means unlimited
+	Workers int           `yaml:"workers"` // Active workers, 0 keeps the current count
+}
+
+// LoadTimeline loads a load profile timeline from a CSV or YAML file
+func LoadTimeline(path string) ([]TimelineStep, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open timeline: %w", err)
+	}
+	defer file.Close()
+
+	var steps []TimelineStep
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		if err := yaml.NewDecoder(file).Decode(&steps); err != nil && err != io.EOF {
+			return nil, fmt.Errorf("failed to parse timeline: %w", err)
+		}
+	case ".csv":
+		steps, err = parseTimelineCSV(file)
+		if err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unsupported timeline format %q (use .csv, .yaml or .yml)", filepath.Ext(path))
+	}
+
+	if len(steps) == 0 {
+		return nil, fmt.Errorf("timeline %s has no steps", path)
+	}
+	for i, step := range steps {
+		if step.Offset < 0 || step.Rate < 0 || step.Workers < 0 {
+			return nil, fmt.Errorf("timeline step %d: offset, rate and workers cannot be negative", i+1)
+		}
+	}
+
+	sort.SliceStable(steps, func(i, j int) bool { return steps[i].Offset < steps[j].Offset })
+	return steps, nil
+}
+
+// parseTimelineCSV parses "offset,rate,workers" rows with an optional header.
+// Offsets are durations ("90s") or plain seconds ("90").
+func parseTimelineCSV(r io.Reader) ([]TimelineStep, error) {
+	reader := csv.NewReader(r)
+	reader.Comment = '#'
+	reader.TrimLeadingSpace = true
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse timeline: %w", err)
+	}
+
+	var steps []TimelineStep
+	for i, record := range records {
+		if i == 0 && strings.EqualFold(record[0], "offset") {
+			continue
+		}
+		if len(record) != 3 {
+			return nil, fmt.Errorf("timeline line %d: expected offset,rate,workers", i+1)
+		}
+
+		offset, err := parseOffset(record[0])
+		if err != nil {
+			return nil, fmt.Errorf("timeline line %d: invalid offset %q", i+1, record[0])
+		}
+		rateValue, err := strconv.Atoi(record[1])
+		if err != nil {
+			return nil, fmt.Errorf("timeline line %d: invalid rate %q", i+1, record[1])
+		}
+		workers, err := strconv.Atoi(record[2])
+		if err != nil {
+			return nil, fmt.Errorf("timeline line %d: invalid workers %q", i+1, record[2])
+		}
+
+		steps = append(steps, TimelineStep{Offset: offset, Rate: rateValue, Workers: workers})
+	}
+	return steps, nil
+}
+
+// parseOffset parses a duration string or a number of seconds
+func parseOffset(s string) (time.Duration, error) {
+	if seconds, err := strconv.ParseFloat(s, 64); err == nil {
+		return time.Duration(seconds * float64(time.Second)), nil
+	}
+	return time.ParseDuration(s)
+}
+
+// followTimeline applies each timeline step once the phase clock reaches its offset
+func (r *BenchmarkRunner) followTimeline(ctx context.Context, clock *phaseClock, group *workerGroup, steps []TimelineStep) {
+	for _, step := range steps {
+		for {
+			wait := step.Offset - clock.Elapsed()
+			if wait <= 0 {
+				break
+			}
+			timer := time.NewTimer(wait)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return
+			case <-timer.C:
+			}
+		}
+
+		r.setTargetRate(step.Rate)
+		if step.Workers > 0 {
+			r.scaleWorkers(group, step.Workers)
+		}
+		log.Printf("Timeline step at %v: rate=%s workers=%d", step.Offset, formatRate(step.Rate), group.size())
+	}
+}
+
+// setTargetRate sets the total operation rate limit, 0 means unlimited
+func (r *BenchmarkRunner) setTargetRate(opsPerSec int) {
+	if opsPerSec <= 0 {
+		r.limiter.SetLimit(rate.Inf)
+		return
+	}
+	burst := opsPerSec / 100
+	if burst < 1 {
+		burst = 1
+	}
+	r.limiter.SetBurst(burst)
+	r.limiter.SetLimit(rate.Limit(opsPerSec))
+}
+
+// formatRate renders a rate limit for logging
+func formatRate(opsPerSec int) string {
+	if opsPerSec <= 0 {
+		return "unlimited"
+	}
+	return fmt.Sprintf("%d/s", opsPerSec)
+}
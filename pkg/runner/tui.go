@@ -0,0 +1,282 @@
+package runner
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"log"
+	"math"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"kvstore-benchmarker/pkg/collector"
+)
+
+const (
+	dashboardRefresh  = time.Second
+	dashboardHistory  = 60  // Sparkline points, one per refresh
+	dashboardLogShown = 8   // Latest log lines shown below the results
+	dashboardLogKept  = 200 // Log lines replayed when the dashboard closes
+)
+
+// ANSI escape sequences controlling the terminal while the dashboard is shown
+const (
+	ansiAltScreen  = "\033[?1049h\033[?25l" // Switch to the alternate screen, hide the cursor
+	ansiMainScreen = "\033[?25h\033[?1049l" // Show the cursor, back to the main screen
+	ansiHome       = "\033[H"
+	ansiClearLine  = "\033[K"
+	ansiClearBelow = "\033[J"
+)
+
+// sparkBars are the bars sparklines are drawn with, from lowest to highest
+var sparkBars = []rune("▁▂▃▄▅▆▇█")
+
+// dashboard is the live terminal view of --tui. Every second it redraws
+// sparklines of the throughput and P99 over the last minute, the results
+// table so far with its error counters and the latest log lines on the
+// alternate screen of the terminal, so that the screen does not scroll.
+// Log lines are held back while it is shown and replayed when it closes.
+type dashboard struct {
+	r      *BenchmarkRunner
+	out    io.Writer
+	color  bool
+	logs   *logTail
+	prev   io.Writer // Log output before the dashboard
+	stop   chan struct{}
+	done   chan struct{}
+	closed sync.Once
+
+	mu         sync.Mutex
+	phase      string
+	phaseStart time.Time
+	phaseLen   time.Duration // 0 when the phase has no fixed duration
+
+	throughput []float64
+	tail       []float64
+	tailLabel  string
+	tailIndex  int
+}
+
+// newDashboard returns the dashboard of --tui, nil without the flag or when
+// the log output is not a terminal
+func (r *BenchmarkRunner) newDashboard() *dashboard {
+	if !r.config.TUI {
+		return nil
+	}
+	out := log.Writer()
+	if !isTerminal(out) {
+		log.Printf("Warning: --tui needs a terminal, printing progress lines instead")
+		return nil
+	}
+
+	// Sparkline P99, or the highest percentile when P99 is not configured
+	percentiles := r.collector.Percentiles()
+	index := slices.Index(percentiles, 99)
+	if index < 0 {
+		index = len(percentiles) - 1
+	}
+	return &dashboard{
+		r:         r,
+		out:       out,
+		color:     colorEnabled(r.config.Color, out),
+		logs:      &logTail{max: dashboardLogKept},
+		tailLabel: collector.PercentileLabel(percentiles[index]),
+		tailIndex: index,
+	}
+}
+
+// start switches to the alternate screen and redraws it every second until
+// close. The interrupt handler of Run closes it to restore the terminal.
+func (d *dashboard) start() {
+	d.prev = log.Writer()
+	log.SetOutput(d.logs)
+	io.WriteString(d.out, ansiAltScreen)
+
+	d.stop = make(chan struct{})
+	d.done = make(chan struct{})
+	go d.run()
+	d.r.shown.Store(d)
+}
+
+// run redraws the dashboard until close
+func (d *dashboard) run() {
+	defer close(d.done)
+	ticker := time.NewTicker(dashboardRefresh)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-d.stop:
+			return
+		case now := <-ticker.C:
+			d.sample(now)
+			d.draw(now)
+		}
+	}
+}
+
+// close leaves the alternate screen and replays the log lines held back.
+// Closing again does nothing.
+func (d *dashboard) close() {
+	d.closed.Do(func() {
+		d.r.shown.Store(nil)
+		close(d.stop)
+		<-d.done
+		d.restore()
+	})
+}
+
+// restore leaves the alternate screen and restores the log output
+func (d *dashboard) restore() {
+	io.WriteString(d.out, ansiMainScreen)
+	log.SetOutput(d.prev)
+	d.logs.replay(d.prev)
+}
+
+// setPhase names the phase shown in the header, with its duration if fixed
+func (d *dashboard) setPhase(name string, duration time.Duration) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.phase = name
+	d.phaseStart = time.Now()
+	d.phaseLen = duration
+}
+
+// sample appends the throughput and tail latency since the last refresh to
+// the sparklines. The dashboard takes over the progress window from the
+// progress log lines it replaces.
+func (d *dashboard) sample(now time.Time) {
+	_, stats, window := d.r.collector.SnapshotAndReset(now)
+	var throughput float64
+	if seconds := window.Seconds(); seconds > 0 {
+		throughput = float64(stats.Count) / seconds
+	}
+	tail := math.NaN()
+	if stats.Count > 0 {
+		tail = stats.Percentile(d.tailIndex)
+	}
+	d.throughput = appendHistory(d.throughput, throughput)
+	d.tail = appendHistory(d.tail, tail)
+}
+
+// appendHistory appends v, keeping the last dashboardHistory values
+func appendHistory(history []float64, v float64) []float64 {
+	history = append(history, v)
+	if len(history) > dashboardHistory {
+		history = history[len(history)-dashboardHistory:]
+	}
+	return history
+}
+
+// draw renders the dashboard over the previous frame
+func (d *dashboard) draw(now time.Time) {
+	r := d.r
+	unit := r.unit()
+	var frame bytes.Buffer
+
+	d.mu.Lock()
+	elapsed := now.Sub(d.phaseStart).Truncate(time.Second)
+	progress := elapsed.String()
+	if d.phaseLen > 0 {
+		progress += " / " + d.phaseLen.String()
+	}
+	fmt.Fprintf(&frame, "kvstore-benchmarker  run %s  target %s  %s %s\n\n",
+		r.config.RunID, r.config.TargetAddress, d.phase, progress)
+	d.mu.Unlock()
+
+	current := func(history []float64) float64 {
+		if len(history) == 0 {
+			return math.NaN()
+		}
+		return history[len(history)-1]
+	}
+	throughput := "-"
+	if v := current(d.throughput); !math.IsNaN(v) {
+		throughput = fmt.Sprintf("%.0f ops/sec", v)
+	}
+	tail := "-"
+	if v := current(d.tail); !math.IsNaN(v) {
+		tail = unit.Display(v)
+	}
+	fmt.Fprintf(&frame, "%-10s %14s  %s\n", "Throughput", throughput, sparkline(d.throughput))
+	fmt.Fprintf(&frame, "%-10s %14s  %s\n\n", d.tailLabel, tail, sparkline(d.tail))
+
+	aggregated := r.collector.GetAggregatedStats()
+	fmt.Fprintf(&frame, "Errors: %d (%.2f%%)  Throttled: %d  Dropped: %d\n\n",
+		aggregated.ErrorCount, aggregated.ErrorRate, aggregated.Throttled, r.collector.MeasuredDropped())
+	if aggregated.Count > 0 {
+		table, _ := r.statsTable(r.collector.GetStats(), aggregated)
+		table.render(&frame, d.color)
+		frame.WriteString("\n")
+	}
+
+	for _, line := range d.logs.last(dashboardLogShown) {
+		frame.WriteString(line)
+	}
+
+	// Clear the rest of every line and below the frame instead of the whole
+	// screen, which would flicker
+	text := strings.ReplaceAll(frame.String(), "\n", ansiClearLine+"\n")
+	io.WriteString(d.out, ansiHome+text+ansiClearBelow)
+}
+
+// sparkline draws values as bars scaled from zero to their maximum; unknown
+// values are left blank
+func sparkline(values []float64) string {
+	highest := 0.0
+	for _, v := range values {
+		if !math.IsNaN(v) {
+			highest = max(highest, v)
+		}
+	}
+
+	var b strings.Builder
+	for _, v := range values {
+		switch {
+		case math.IsNaN(v):
+			b.WriteRune(' ')
+		case highest == 0:
+			b.WriteRune(sparkBars[0])
+		default:
+			b.WriteRune(sparkBars[int(v/highest*float64(len(sparkBars)-1)+0.5)])
+		}
+	}
+	return b.String()
+}
+
+// logTail is a log output keeping the latest lines
+type logTail struct {
+	mu    sync.Mutex
+	max   int
+	lines []string
+}
+
+// Write keeps a log line, dropping the oldest beyond the maximum
+func (t *logTail) Write(p []byte) (int, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.lines = append(t.lines, string(p))
+	if len(t.lines) > t.max {
+		t.lines = t.lines[len(t.lines)-t.max:]
+	}
+	return len(p), nil
+}
+
+// last returns the latest n lines
+func (t *logTail) last(n int) []string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return append([]string(nil), t.lines[max(len(t.lines)-n, 0):]...)
+}
+
+// replay writes the kept lines to w and forgets them
+func (t *logTail) replay(w io.Writer) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for _, line := range t.lines {
+		io.WriteString(w, line)
+	}
+	t.lines = nil
+}
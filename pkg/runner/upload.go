@@ -0,0 +1,104 @@
+package runner
+
+import (
+	"bytes"
+	"context"
+	"log"
+	"mime"
+	"os"
+	"path/filepath"
+	"text/template"
+	"time"
+
+	"kvstore-benchmarker/pkg/collector"
+	"kvstore-benchmarker/pkg/objstore"
+)
+
+// uploadTimeout bounds how long uploading the results of a run may take
+const uploadTimeout = 10 * time.Minute
+
+// uploadKey is the data of the --upload-key template
+type uploadKey struct {
+	RunID  string
+	File   string // Base name of the file, e.g. "summary.json", or "charts/latency.svg"
+	Start  time.Time
+	Labels map[string]string
+}
+
+// uploadResults uploads the output files of the run to the --upload bucket,
+// opened when the run started, once the collector has closed them. Failures are logged: the results are
+// still on disk.
+func (r *BenchmarkRunner) uploadResults() {
+	if r.store == nil {
+		return
+	}
+	// The location and key template were checked by config.Validate
+	location, _ := objstore.Parse(r.config.UploadURL)
+	tmpl, _ := template.New("upload-key").Option("missingkey=zero").Parse(r.config.UploadKey)
+
+	// Files by the name they are uploaded as, skipping those never created,
+	// e.g. by a run that failed early
+	var files, names []string
+	add := func(path, name string) {
+		if info, err := os.Stat(path); err == nil && info.Mode().IsRegular() {
+			files = append(files, path)
+			names = append(names, name)
+		}
+	}
+	for _, path := range r.config.OutputFiles() {
+		add(path, filepath.Base(path))
+	}
+	if dir := r.config.ChartsDir; dir != "" {
+		charts, _ := filepath.Glob(filepath.Join(dir, "*"))
+		for _, path := range charts {
+			add(path, filepath.Base(dir)+"/"+filepath.Base(path))
+		}
+	}
+	if len(files) == 0 {
+		log.Printf("Warning: --upload found no output files to upload (add e.g. --json)")
+		return
+	}
+
+	labels := make(map[string]string)
+	parsed, _ := collector.ParseLabels(r.config.Labels)
+	for _, label := range parsed {
+		labels[label.Name] = label.Value
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), uploadTimeout)
+	defer cancel()
+	uploaded := 0
+	for i, path := range files {
+		var key bytes.Buffer
+		data := uploadKey{RunID: r.config.RunID, File: names[i], Start: r.startTime, Labels: labels}
+		if err := tmpl.Execute(&key, data); err != nil {
+			log.Printf("Warning: failed to render upload key of %s: %v", path, err)
+			continue
+		}
+		if err := uploadFile(ctx, r.store, location.Key(key.String()), path); err != nil {
+			log.Printf("Warning: failed to upload %s to %s: %v", path, location.URL(location.Key(key.String())), err)
+			continue
+		}
+		uploaded++
+	}
+	log.Printf("Uploaded %d of %d result files to %s", uploaded, len(files), r.config.UploadURL)
+}
+
+// uploadFile streams a file to the store as the object key
+func uploadFile(ctx context.Context, store objstore.Store, key, path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	info, err := file.Stat()
+	if err != nil {
+		return err
+	}
+
+	contentType := mime.TypeByExtension(filepath.Ext(path))
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	return store.Put(ctx, key, file, info.Size(), contentType)
+}
@@ -0,0 +1,169 @@
+package runner
+
+import (
+	"fmt"
+	"html/template"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"kvstore-benchmarker/pkg/admin"
+	"kvstore-benchmarker/pkg/chart"
+)
+
+// webPage is the data of the web dashboard page
+type webPage struct {
+	RunID  string
+	Notes  string
+	Target string
+	Load   string
+	Labels map[string]string
+	Live   webLive
+}
+
+// webLive is the data of the live part of the web dashboard, which the page
+// fetches again every few seconds
+type webLive struct {
+	Status string
+	Unit   string
+	Header []string
+	Rows   [][]htmlCell
+	Charts []template.HTML
+}
+
+// registerWebRoutes serves the read-only web dashboard: "/" is the page,
+// "/live" the results table and charts it refreshes itself with and
+// "/events" the stream of interval statistics for programs
+func (r *BenchmarkRunner) registerWebRoutes(s *admin.Server) {
+	s.Handle("/", func(w http.ResponseWriter, req *http.Request) {
+		if req.URL.Path != "/" {
+			http.NotFound(w, req)
+			return
+		}
+		cfg := r.config
+		page := webPage{
+			RunID:  cfg.RunID,
+			Notes:  cfg.Notes,
+			Target: cfg.TargetAddress,
+			Load: fmt.Sprintf("%d connections, %d workers, %d%% reads, %d%% writes, %d%% deletes",
+				cfg.NumConnections, cfg.NumWorkers, cfg.ReadRatio, cfg.WriteRatio, cfg.DeleteRatio),
+			Labels: make(map[string]string),
+			Live:   r.webLive(),
+		}
+		if cfg.IndexWriteRatio > 0 {
+			page.Load += fmt.Sprintf(", %d%% index writes", cfg.IndexWriteRatio)
+		}
+		if cfg.IndexReadRatio > 0 {
+			page.Load += fmt.Sprintf(", %d%% index reads", cfg.IndexReadRatio)
+		}
+		for _, label := range r.collector.Labels() {
+			page.Labels[label.Name] = label.Value
+		}
+		r.writeWeb(w, "page", page)
+	})
+
+	s.Handle("/live", func(w http.ResponseWriter, req *http.Request) {
+		r.writeWeb(w, "live", r.webLive())
+	})
+
+	s.Handle("/events", r.events.serve)
+}
+
+// writeWeb renders a web dashboard template as the response
+func (r *BenchmarkRunner) writeWeb(w http.ResponseWriter, name string, data interface{}) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	if err := webTemplates.ExecuteTemplate(w, name, data); err != nil {
+		log.Printf("Warning: failed to render web dashboard: %v", err)
+	}
+}
+
+// webLive collects the results so far and the charts of the report intervals
+func (r *BenchmarkRunner) webLive() webLive {
+	aggregated := r.collector.GetAggregatedStats()
+	status := []string{"Updated " + time.Now().Format("15:04:05")}
+	if r.gate.IsPaused() {
+		status = append(status, "paused")
+	}
+	status = append(status, fmt.Sprintf("%d ops, %d errors (%.2f%%), %d throttled",
+		aggregated.Count, aggregated.ErrorCount, aggregated.ErrorRate, aggregated.Throttled))
+
+	live := webLive{
+		Status: strings.Join(status, " · "),
+		Unit:   r.unit().Name(),
+		Charts: htmlSVGs(append(r.htmlCharts(), r.errorChart())),
+	}
+	if aggregated.Count > 0 {
+		table, _ := r.statsTable(r.collector.GetStats(), aggregated)
+		live.Header = table.header
+		live.Rows = htmlRows(table)
+	}
+	return live
+}
+
+// errorChart charts the failed operations per second over the report intervals
+func (r *BenchmarkRunner) errorChart() chart.Chart {
+	errors := chart.Series{Name: "Errors"}
+	for _, point := range r.intervals.snapshot() {
+		errors.Points = append(errors.Points, chart.Point{X: point.offset, Y: point.errors})
+	}
+	return chart.Chart{
+		Name:   "errors",
+		Title:  "Errors over time",
+		XLabel: "Time since start (s)",
+		YLabel: "Errors (ops/sec)",
+		Series: []chart.Series{errors},
+	}
+}
+
+// webTemplates are the web dashboard page and its live part
+var webTemplates = template.Must(template.New("web").Parse(`{{define "live"}}<p id="status">{{.Status}}</p>
+{{if .Rows}}<table>
+<caption>Latencies in {{.Unit}}</caption>
+<tr>{{range .Header}}<th>{{.}}</th>{{end}}</tr>
+{{range .Rows}}<tr>{{range .}}<td{{with .Class}} class="{{.}}"{{end}}>{{.Text}}</td>{{end}}</tr>
+{{end}}</table>
+{{else}}<p>No results yet.</p>
+{{end}}{{range .Charts}}{{.}}{{end}}{{end}}
+
+{{define "page"}}<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>Live: benchmark run {{.RunID}}</title>
+` + htmlStyle + `
+</head>
+<body>
+<h1>Benchmark run {{.RunID}}</h1>
+{{with .Notes}}<p>{{.}}</p>
+{{end}}<dl>
+<dt>Target</dt><dd><code>{{.Target}}</code></dd>
+<dt>Load</dt><dd>{{.Load}}</dd>
+{{with .Labels}}<dt>Labels</dt><dd>{{range $name, $value := .}}<code>{{$name}}={{$value}}</code> {{end}}</dd>
+{{end}}</dl>
+<p id="offline" hidden><strong>The benchmark has finished or is unreachable; showing the last results.</strong></p>
+<div id="live">
+{{template "live" .Live}}
+</div>
+<script>
+setInterval(function () {
+	fetch("live", {cache: "no-store"})
+		.then(function (response) {
+			if (!response.ok) {
+				throw new Error(response.statusText);
+			}
+			return response.text();
+		})
+		.then(function (html) {
+			document.getElementById("live").innerHTML = html;
+			document.getElementById("offline").hidden = true;
+		})
+		.catch(function () {
+			document.getElementById("offline").hidden = false;
+		});
+}, 2000);
+</script>
+</body>
+</html>
+{{end}}`))
@@ -0,0 +1,57 @@
+package runner
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+// writeYAMLSummary writes the summary of --json as block-style YAML to path,
+// or stdout if path is "-". Keys keep the order and names of the JSON summary,
+// so that runs committed to a repository diff line by line.
+func (r *BenchmarkRunner) writeYAMLSummary(path string) error {
+	summary, err := r.jsonSummary()
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(summary)
+	if err != nil {
+		return fmt.Errorf("failed to encode YAML summary: %w", err)
+	}
+	// JSON is YAML: decoding it into a node keeps the key order, which
+	// encoding a map would sort
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return fmt.Errorf("failed to encode YAML summary: %w", err)
+	}
+	blockStyle(&doc)
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(&doc); err != nil {
+		return fmt.Errorf("failed to encode YAML summary: %w", err)
+	}
+	enc.Close()
+
+	if path == "-" {
+		_, err = os.Stdout.Write(buf.Bytes())
+		return err
+	}
+	if err := os.WriteFile(path, buf.Bytes(), 0o644); err != nil {
+		return fmt.Errorf("failed to write YAML summary: %w", err)
+	}
+	return nil
+}
+
+// blockStyle clears the flow and quoting styles decoded from JSON, so that
+// the encoder writes one key per line and quotes only where YAML requires it
+func blockStyle(node *yaml.Node) {
+	node.Style = 0
+	for _, child := range node.Content {
+		blockStyle(child)
+	}
+}
@@ -0,0 +1,114 @@
+// Package scenario holds the library of ready-made benchmark scenarios
+// embedded in the binary, such as the YCSB core workloads, and turns their
+// steps into benchmark configurations.
+package scenario
+
+import (
+	"embed"
+	"flag"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"kvstore-benchmarker/pkg/config"
+)
+
+//go:embed scenarios/*.yaml
+var files embed.FS
+
+// Step is one benchmark run of a scenario, configured by benchmark flags
+type Step struct {
+	Name  string   `yaml:"name"`
+	Flags []string `yaml:"flags"`
+}
+
+// Scenario is a named sequence of benchmark runs
+type Scenario struct {
+	Name        string `yaml:"-"`
+	Description string `yaml:"description"`
+	Steps       []Step `yaml:"steps"`
+}
+
+// List returns the scenarios of the library, sorted by name
+func List() ([]*Scenario, error) {
+	entries, err := files.ReadDir("scenarios")
+	if err != nil {
+		return nil, err
+	}
+	var scenarios []*Scenario
+	for _, entry := range entries {
+		s, err := Get(strings.TrimSuffix(entry.Name(), ".yaml"))
+		if err != nil {
+			return nil, err
+		}
+		scenarios = append(scenarios, s)
+	}
+	sort.Slice(scenarios, func(i, j int) bool { return scenarios[i].Name < scenarios[j].Name })
+	return scenarios, nil
+}
+
+// Get returns the scenario of the library with the given name
+func Get(name string) (*Scenario, error) {
+	data, err := Source(name)
+	if err != nil {
+		return nil, err
+	}
+	s := &Scenario{Name: name}
+	if err := yaml.Unmarshal(data, s); err != nil {
+		return nil, fmt.Errorf("invalid scenario %s: %w", name, err)
+	}
+	if len(s.Steps) == 0 {
+		return nil, fmt.Errorf("scenario %s has no steps", name)
+	}
+	return s, nil
+}
+
+// Source returns the YAML definition of the scenario with the given name
+func Source(name string) ([]byte, error) {
+	if name == "" || strings.ContainsAny(name, `/\.`) {
+		return nil, fmt.Errorf("unknown scenario %q", name)
+	}
+	data, err := files.ReadFile(path.Join("scenarios", name+".yaml"))
+	if err != nil {
+		return nil, fmt.Errorf("unknown scenario %q (see \"scenarios list\")", name)
+	}
+	return data, nil
+}
+
+// StepConfig returns the configuration of step i: the defaults, overridden by
+// the step's flags and then by the user's benchmark flags in args. Every step
+// is labelled with the scenario name; when there are several steps they are
+// also labelled with the step name, and the run ID and per-run output files
+// get the step name as suffix so that steps do not overwrite each other.
+func (s *Scenario) StepConfig(i int, args []string) (*config.BenchmarkConfig, error) {
+	step := s.Steps[i]
+	cfg := config.DefaultConfig()
+	fs := flag.NewFlagSet(s.Name, flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	cfg.RegisterFlags(fs)
+	if err := fs.Parse(step.Flags); err != nil {
+		return nil, fmt.Errorf("scenario %s step %s: %w", s.Name, step.Name, err)
+	}
+	fs.SetOutput(nil)
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+	if fs.NArg() > 0 {
+		return nil, fmt.Errorf("unexpected arguments: %s", strings.Join(fs.Args(), " "))
+	}
+
+	labels := []string{"scenario=" + s.Name}
+	if len(s.Steps) > 1 {
+		labels = append(labels, "step="+step.Name)
+		cfg = cfg.WithSuffix("-" + step.Name)
+	}
+	if cfg.Labels != "" {
+		labels = append(labels, cfg.Labels)
+	}
+	cfg.Labels = strings.Join(labels, ",")
+	return cfg, nil
+}
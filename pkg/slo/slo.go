@@ -0,0 +1,183 @@
+// Package slo parses and evaluates service level objective assertions, such
+// as "P99 below 10ms", over the whole run or over named phases of it.
+package slo
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"kvstore-benchmarker/pkg/collector"
+)
+
+// RunPhase names the whole measured phase in assertions without a phase
+const RunPhase = "run"
+
+// Assertion is a threshold on a statistic, e.g. "failover:Get.p99<500ms"
+type Assertion struct {
+	Text      string
+	Phase     string // Phase name, RunPhase for the whole run
+	Method    string // Method name, empty for all methods combined
+	Metric    string
+	Op        string
+	Threshold float64 // Milliseconds for latency metrics
+}
+
+// Phase is a named part of the measured phase, lasting from Offset until the
+// next phase starts
+type Phase struct {
+	Name   string
+	Offset time.Duration
+}
+
+// latencyMetrics maps latency metric names to their statistic
+var latencyMetrics = map[string]func(collector.Stats) float64{
+	"avg":  func(s collector.Stats) float64 { return s.AvgLatency },
+	"p50":  func(s collector.Stats) float64 { return s.P50Latency },
+	"p95":  func(s collector.Stats) float64 { return s.P95Latency },
+	"p99":  func(s collector.Stats) float64 { return s.P99Latency },
+	"p999": func(s collector.Stats) float64 { return s.P999Latency },
+	"max":  func(s collector.Stats) float64 { return s.MaxLatency },
+}
+
+// assertionPattern matches "[phase:][method.]metric<op>value"
+var assertionPattern = regexp.MustCompile(`^(?:([\w-]+):)?(?:(\w+)\.)?(\w+)\s*(<=|>=|<|>)\s*(\S+)$`)
+
+// ParseAssertions parses a comma-separated list of assertions. Latency
+// metrics (avg, p50, p95, p99, p999, max) take durations, error_rate a
+// percentage and throughput operations per second.
+func ParseAssertions(list string) ([]Assertion, error) {
+	var assertions []Assertion
+	for _, text := range strings.Split(list, ",") {
+		text = strings.TrimSpace(text)
+		if text == "" {
+			continue
+		}
+
+		m := assertionPattern.FindStringSubmatch(text)
+		if m == nil {
+			return nil, fmt.Errorf("invalid SLO assertion %q (expected [phase:][method.]metric<value)", text)
+		}
+		a := Assertion{Text: text, Phase: m[1], Method: m[2], Metric: m[3], Op: m[4]}
+		if a.Phase == "" {
+			a.Phase = RunPhase
+		}
+
+		var err error
+		switch {
+		case latencyMetrics[a.Metric] != nil:
+			var d time.Duration
+			d, err = time.ParseDuration(m[5])
+			a.Threshold = float64(d) / float64(time.Millisecond)
+		case a.Metric == "error_rate":
+			a.Threshold, err = strconv.ParseFloat(strings.TrimSuffix(m[5], "%"), 64)
+		case a.Metric == "throughput":
+			a.Threshold, err = strconv.ParseFloat(m[5], 64)
+		default:
+			return nil, fmt.Errorf("SLO assertion %q: unknown metric %q", text, a.Metric)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("SLO assertion %q: invalid threshold %q", text, m[5])
+		}
+		assertions = append(assertions, a)
+	}
+	return assertions, nil
+}
+
+// ParsePhases parses a comma-separated list of "name=offset" phases, where
+// offsets count from the start of the measured phase
+func ParsePhases(list string) ([]Phase, error) {
+	var phases []Phase
+	for _, item := range strings.Split(list, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+
+		name, offset, ok := strings.Cut(item, "=")
+		if !ok || name == "" {
+			return nil, fmt.Errorf("invalid phase %q (expected name=offset)", item)
+		}
+		if name == RunPhase {
+			return nil, fmt.Errorf("phase name %q is reserved for the whole run", RunPhase)
+		}
+		d, err := time.ParseDuration(offset)
+		if err != nil || d < 0 {
+			return nil, fmt.Errorf("phase %q: invalid offset %q", name, offset)
+		}
+		if len(phases) > 0 && d <= phases[len(phases)-1].Offset {
+			return nil, fmt.Errorf("phase %q must start after phase %q", name, phases[len(phases)-1].Name)
+		}
+		phases = append(phases, Phase{Name: name, Offset: d})
+	}
+	return phases, nil
+}
+
+// Validate checks that every assertion refers to a defined phase
+func Validate(assertions []Assertion, phases []Phase) error {
+	for _, a := range assertions {
+		if a.Phase == RunPhase {
+			continue
+		}
+		found := false
+		for _, p := range phases {
+			found = found || p.Name == a.Phase
+		}
+		if !found {
+			return fmt.Errorf("SLO assertion %q refers to undefined phase %q", a.Text, a.Phase)
+		}
+	}
+	return nil
+}
+
+// IsLatency reports whether the assertion thresholds a latency
+func (a Assertion) IsLatency() bool {
+	return latencyMetrics[a.Metric] != nil
+}
+
+// Result is the outcome of evaluating an assertion
+type Result struct {
+	Assertion
+	Actual float64
+	NoData bool // The phase or method had no operations
+	Passed bool
+}
+
+// Evaluate checks an assertion against the statistics of its scope, which
+// lasted the given number of seconds. Assertions on scopes without
+// operations fail.
+func (a Assertion) Evaluate(stats map[string]collector.Stats, aggregated collector.Stats, seconds float64) Result {
+	stat := aggregated
+	if a.Method != "" {
+		stat = stats[a.Method]
+	}
+
+	result := Result{Assertion: a}
+	if stat.Count == 0 {
+		result.NoData = true
+		return result
+	}
+
+	switch {
+	case a.IsLatency():
+		result.Actual = latencyMetrics[a.Metric](stat)
+	case a.Metric == "error_rate":
+		result.Actual = stat.ErrorRate
+	case a.Metric == "throughput" && seconds > 0:
+		result.Actual = float64(stat.Count) / seconds
+	}
+
+	switch a.Op {
+	case "<":
+		result.Passed = result.Actual < a.Threshold
+	case "<=":
+		result.Passed = result.Actual <= a.Threshold
+	case ">":
+		result.Passed = result.Actual > a.Threshold
+	case ">=":
+		result.Passed = result.Actual >= a.Threshold
+	}
+	return result
+}